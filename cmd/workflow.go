@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/repository"
+	"github.com/certimate-go/certimate/internal/workflow"
+)
+
+func NewWorkflowCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "workflow",
+		Short: "Manages workflows",
+	}
+
+	command.AddCommand(workflowExportCommand())
+	command.AddCommand(workflowImportCommand())
+
+	return command
+}
+
+func workflowExportCommand() *cobra.Command {
+	var flagFormat string
+	var flagOutput string
+
+	command := &cobra.Command{
+		Use:          "export [workflowId]",
+		Example:      "workflow export abcdefghijklmno --format yaml --out ./workflow.yaml",
+		Short:        "Exports a workflow and its sub-workflows as a portable bundle",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := newWorkflowService().ExportBundle(getCommandContext(cmd), &dtos.WorkflowExportBundleReq{
+				WorkflowId: args[0],
+				Format:     flagFormat,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flagOutput == "" {
+				fmt.Fprint(cmd.OutOrStdout(), res.Content)
+				return nil
+			}
+
+			return os.WriteFile(flagOutput, []byte(res.Content), 0o644)
+		},
+	}
+
+	command.PersistentFlags().StringVar(&flagFormat, "format", "yaml", "bundle format, \"yaml\" or \"json\"")
+	command.PersistentFlags().StringVar(&flagOutput, "out", "", "output file (default stdout)")
+
+	return command
+}
+
+func workflowImportCommand() *cobra.Command {
+	var flagAccesses []string
+
+	command := &cobra.Command{
+		Use:          "import [file]",
+		Example:      "workflow import ./workflow.yaml --access access1=abcdefghijklmno",
+		Short:        "Imports workflows from a portable bundle",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var content []byte
+			var err error
+			if args[0] == "-" {
+				content, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				content, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			accessMappings := make(map[string]string)
+			for _, pair := range flagAccesses {
+				ref, accessId, ok := strings.Cut(pair, "=")
+				if !ok {
+					return fmt.Errorf("invalid access mapping '%s', expected 'ref=accessId'", pair)
+				}
+				accessMappings[strings.TrimSpace(ref)] = strings.TrimSpace(accessId)
+			}
+
+			res, err := newWorkflowService().ImportBundle(getCommandContext(cmd), &dtos.WorkflowImportBundleReq{
+				Content:        string(content),
+				AccessMappings: accessMappings,
+				Author:         "cli",
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, item := range res.Workflows {
+				fmt.Fprintf(cmd.OutOrStdout(), "imported workflow '%s' (ref: %s) as #%s\n", item.Name, item.Ref, item.Id)
+			}
+
+			return nil
+		},
+	}
+
+	command.PersistentFlags().StringArrayVar(&flagAccesses, "access", nil, "maps an access placeholder to a local access record, in the form of 'ref=accessId'")
+
+	return command
+}
+
+// 命令未经 ExecuteContext 执行时 cmd.Context() 为 nil，此时回退到空上下文。
+func getCommandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func newWorkflowService() *workflow.WorkflowService {
+	return workflow.NewWorkflowService(
+		repository.NewWorkflowRepository(),
+		repository.NewWorkflowRunRepository(),
+		repository.NewWorkflowVersionRepository(),
+		repository.NewCertificateRepository(),
+		repository.NewAccessRepository(),
+		repository.NewSettingsRepository(),
+		repository.NewPrivateCARepository(),
+	)
+}
@@ -0,0 +1,36 @@
+package applicators
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/s3"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	if err := ACMEHttp01Registries.Register(domain.ACMEHttp01ProviderTypeAliyunOSS, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		credentials := domain.AccessConfigForAliyun{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		// 阿里云 OSS 仅支持以虚拟主机风格访问存储桶
+		region := xmaps.GetString(options.ProviderExtendedConfig, "region")
+		provider, err := s3.NewChallengeProvider(&s3.ChallengeProviderConfig{
+			Endpoint:        lo.CoalesceOrEmpty(xmaps.GetString(options.ProviderExtendedConfig, "endpoint"), fmt.Sprintf("https://oss-%s.aliyuncs.com", region)),
+			Region:          region,
+			AccessKeyId:     credentials.AccessKeyId,
+			SecretAccessKey: credentials.AccessKeySecret,
+			Bucket:          xmaps.GetString(options.ProviderExtendedConfig, "bucket"),
+			PathPrefix:      xmaps.GetString(options.ProviderExtendedConfig, "pathPrefix"),
+			ACL:             xmaps.GetString(options.ProviderExtendedConfig, "acl"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,36 @@
+package applicators
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/s3"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	if err := ACMEHttp01Registries.Register(domain.ACMEHttp01ProviderTypeAWSS3, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		credentials := domain.AccessConfigForAWS{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		region := xmaps.GetString(options.ProviderExtendedConfig, "region")
+		provider, err := s3.NewChallengeProvider(&s3.ChallengeProviderConfig{
+			Endpoint:        lo.CoalesceOrEmpty(xmaps.GetString(options.ProviderExtendedConfig, "endpoint"), fmt.Sprintf("https://s3.%s.amazonaws.com", region)),
+			Region:          region,
+			AccessKeyId:     credentials.AccessKeyId,
+			SecretAccessKey: credentials.SecretAccessKey,
+			UsePathStyle:    xmaps.GetBool(options.ProviderExtendedConfig, "usePathStyle"),
+			Bucket:          xmaps.GetString(options.ProviderExtendedConfig, "bucket"),
+			PathPrefix:      xmaps.GetString(options.ProviderExtendedConfig, "pathPrefix"),
+			ACL:             xmaps.GetString(options.ProviderExtendedConfig, "acl"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/local"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/standalone"
+	tlsalpnlocal "github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-tlsalpn01/providers/local"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
@@ -17,4 +19,25 @@ func init() {
 	}); err != nil {
 		panic(err)
 	}
+
+	if err := ACMEHttp01Registries.Register(domain.ACMEHttp01ProviderTypeLocalStandalone, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		provider, err := standalone.NewChallengeProvider(&standalone.ChallengeProviderConfig{
+			ListenAddress:     xmaps.GetString(options.ProviderExtendedConfig, "listenAddress"),
+			ForwardMode:       xmaps.GetBool(options.ProviderExtendedConfig, "forwardMode"),
+			ForwardedHeader:   xmaps.GetString(options.ProviderExtendedConfig, "forwardedHeader"),
+			ListenWaitTimeout: xmaps.GetInt32(options.ProviderExtendedConfig, "listenWaitTimeout"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
+
+	if err := ACMETlsAlpn01Registries.Register(domain.ACMETlsAlpn01ProviderTypeLocal, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		provider, err := tlsalpnlocal.NewChallengeProvider(&tlsalpnlocal.ChallengeProviderConfig{
+			ListenAddress: xmaps.GetString(options.ProviderExtendedConfig, "listenAddress"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,34 @@
+package applicators
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-dns01/providers/rfc2136"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	if err := ACMEDns01Registries.Register(domain.ACMEDns01ProviderTypeRFC2136, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		credentials := domain.AccessConfigForRFC2136{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		provider, err := rfc2136.NewChallengeProvider(&rfc2136.ChallengeProviderConfig{
+			Nameserver:            lo.CoalesceOrEmpty(xmaps.GetString(options.ProviderExtendedConfig, "nameserver"), credentials.Nameserver),
+			Zone:                  xmaps.GetString(options.ProviderExtendedConfig, "zone"),
+			TsigAlgorithm:         credentials.TsigAlgorithm,
+			TsigKey:               credentials.TsigKey,
+			TsigSecret:            credentials.TsigSecret,
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+			DnsTTL:                options.DnsTTL,
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,35 @@
+package applicators
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/s3"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	if err := ACMEHttp01Registries.Register(domain.ACMEHttp01ProviderTypeS3, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		credentials := domain.AccessConfigForS3{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		provider, err := s3.NewChallengeProvider(&s3.ChallengeProviderConfig{
+			Endpoint:                 credentials.Endpoint,
+			Region:                   credentials.Region,
+			AccessKeyId:              credentials.AccessKeyId,
+			SecretAccessKey:          credentials.SecretAccessKey,
+			UsePathStyle:             credentials.UsePathStyle,
+			AllowInsecureConnections: credentials.AllowInsecureConnections,
+			Bucket:                   xmaps.GetString(options.ProviderExtendedConfig, "bucket"),
+			PathPrefix:               xmaps.GetString(options.ProviderExtendedConfig, "pathPrefix"),
+			ACL:                      xmaps.GetString(options.ProviderExtendedConfig, "acl"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-http01/providers/ssh"
+	tlsalpnssh "github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-tlsalpn01/providers/ssh"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
@@ -48,4 +49,43 @@ func init() {
 	}); err != nil {
 		panic(err)
 	}
+
+	if err := ACMETlsAlpn01Registries.Register(domain.ACMETlsAlpn01ProviderTypeSSH, func(options *ProviderFactoryOptions) (challenge.Provider, error) {
+		credentials := domain.AccessConfigForSSH{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		jumpServers := make([]tlsalpnssh.ServerConfig, len(credentials.JumpServers))
+		for i, jumpServer := range credentials.JumpServers {
+			jumpServers[i] = tlsalpnssh.ServerConfig{
+				SshHost:          jumpServer.Host,
+				SshPort:          jumpServer.Port,
+				SshAuthMethod:    jumpServer.AuthMethod,
+				SshUsername:      jumpServer.Username,
+				SshPassword:      jumpServer.Password,
+				SshKey:           jumpServer.Key,
+				SshKeyPassphrase: jumpServer.KeyPassphrase,
+			}
+		}
+
+		provider, err := tlsalpnssh.NewChallengeProvider(&tlsalpnssh.ChallengeProviderConfig{
+			ServerConfig: tlsalpnssh.ServerConfig{
+				SshHost:          credentials.Host,
+				SshPort:          credentials.Port,
+				SshAuthMethod:    credentials.AuthMethod,
+				SshUsername:      credentials.Username,
+				SshPassword:      credentials.Password,
+				SshKey:           credentials.Key,
+				SshKeyPassphrase: credentials.KeyPassphrase,
+			},
+			JumpServers: jumpServers,
+			ListenPort:  xmaps.GetInt32(options.ProviderExtendedConfig, "listenPort"),
+			WorkDir:     xmaps.GetString(options.ProviderExtendedConfig, "workDir"),
+			OpenSSLPath: xmaps.GetString(options.ProviderExtendedConfig, "opensslPath"),
+		})
+		return provider, err
+	}); err != nil {
+		panic(err)
+	}
 }
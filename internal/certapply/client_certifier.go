@@ -110,17 +110,23 @@ func (c *ACMEClient) sendObtainCertificateRequest(request *ObtainCertificateRequ
 				return nil, fmt.Errorf("failed to initialize dns-01 provider '%s': %w", request.Provider, err)
 			}
 
+			// 未指定 DNS 服务器时，若提供商自身管理域名服务器（如 RFC 2136），则向其检查传播情况
+			nameservers := request.Nameservers
+			if p, ok := provider.(interface{ PropagationNameservers() []string }); ok && len(nameservers) == 0 {
+				nameservers = p.PropagationNameservers()
+			}
+
 			c.client.Challenge.SetDNS01Provider(provider,
 				dns01.CondOption(
-					len(request.Nameservers) > 0,
-					dns01.AddRecursiveNameservers(dns01.ParseNameservers(request.Nameservers)),
+					len(nameservers) > 0,
+					dns01.AddRecursiveNameservers(dns01.ParseNameservers(nameservers)),
 				),
 				dns01.CondOption(
 					request.DnsPropagationWait > 0,
 					dns01.PropagationWait(time.Duration(request.DnsPropagationWait)*time.Second, true),
 				),
 				dns01.CondOption(
-					len(request.Nameservers) > 0 || request.DnsPropagationWait > 0,
+					len(nameservers) > 0 || request.DnsPropagationWait > 0,
 					dns01.DisableAuthoritativeNssPropagationRequirement(),
 				),
 			)
@@ -146,6 +152,24 @@ func (c *ACMEClient) sendObtainCertificateRequest(request *ObtainCertificateRequ
 			)
 		}
 
+	case "tls-alpn-01":
+		{
+			providerFactory, err := applicators.ACMETlsAlpn01Registries.Get(domain.ACMETlsAlpn01ProviderType(request.Provider))
+			if err != nil {
+				return nil, err
+			}
+
+			provider, err := providerFactory(&applicators.ProviderFactoryOptions{
+				ProviderAccessConfig:   request.ProviderAccessConfig,
+				ProviderExtendedConfig: request.ProviderExtendedConfig,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize tls-alpn-01 provider '%s': %w", request.Provider, err)
+			}
+
+			c.client.Challenge.SetTLSALPN01Provider(provider)
+		}
+
 	default:
 		return nil, fmt.Errorf("unsupported challenge type: '%s'", request.ChallengeType)
 	}
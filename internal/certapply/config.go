@@ -95,6 +95,9 @@ func NewACMEConfig(options *ACMEConfigOptions) (*ACMEConfig, error) {
 			ca.CADirUrl = acmeDirUrls[string(domain.CAProviderTypeSSLCom)]
 		}
 
+	case domain.CAProviderTypePrivateCA:
+		return nil, errors.New("the private ca does not support ACME protocol")
+
 	case domain.CAProviderTypeACMECA:
 		credentials := &domain.AccessConfigForACMECA{}
 		if err := xmaps.Populate(caAccessConfig, &credentials); err != nil {
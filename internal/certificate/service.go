@@ -13,18 +13,26 @@ import (
 	"github.com/pocketbase/dbx"
 
 	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/cluster"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/eventbus"
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 )
 
 type CertificateService struct {
+	cluster  cluster.Cluster
+	eventBus eventbus.EventBus
+
 	certificateRepo certificateRepository
 	settingsRepo    settingsRepository
 }
 
 func NewCertificateService(certificateRepo certificateRepository, settingsRepo settingsRepository) *CertificateService {
 	return &CertificateService{
+		cluster:  cluster.GetSingletonCluster(),
+		eventBus: eventbus.GetSingletonEventBus(),
+
 		certificateRepo: certificateRepo,
 		settingsRepo:    settingsRepo,
 	}
@@ -36,6 +44,20 @@ func (s *CertificateService) InitSchedule(ctx context.Context) error {
 		s.cleanupExpiredCertificates(context.Background())
 	})
 
+	// 每小时发布即将过期证书事件，由订阅的工作流按各自的剩余天数阈值决定是否触发（同一证书只会触发一次）
+	app.GetScheduler().MustAdd("publishCertificateExpiringEvents", "0 * * * *", func() {
+		// 多实例模式下仅由获取到租约的实例发布，以免重复触发工作流
+		leaseName := fmt.Sprintf("publishCertificateExpiringEvents@%s", time.Now().Truncate(time.Hour).Format(time.RFC3339))
+		if acquired, err := s.cluster.TryAcquireLease(context.Background(), leaseName, time.Hour); err != nil {
+			app.GetLogger().Error("failed to acquire lease", slog.String("lease", leaseName), slog.Any("error", err))
+			return
+		} else if !acquired {
+			return
+		}
+
+		s.publishExpiringEvents(context.Background())
+	})
+
 	return nil
 }
 
@@ -191,6 +213,24 @@ func (s *CertificateService) ValidatePrivateKey(ctx context.Context, req *dtos.C
 	}, nil
 }
 
+func (s *CertificateService) publishExpiringEvents(ctx context.Context) error {
+	certificates, err := s.certificateRepo.ListExpiringSoon(ctx, domain.CertificateExpiringEventMaxDaysLeft)
+	if err != nil {
+		app.GetLogger().Error("failed to list expiring certificates", slog.Any("error", err))
+		return err
+	}
+
+	for _, certificate := range certificates {
+		s.eventBus.Publish(ctx, domain.NewCertificateEvent(domain.EventTypeCertificateExpiring, certificate))
+	}
+
+	if len(certificates) > 0 {
+		app.GetLogger().Info(fmt.Sprintf("published %d certificate expiring events", len(certificates)))
+	}
+
+	return nil
+}
+
 func (s *CertificateService) cleanupExpiredCertificates(ctx context.Context) error {
 	settings, err := s.settingsRepo.GetByName(ctx, "persistence")
 	if err != nil {
@@ -9,7 +9,7 @@ import (
 )
 
 type certificateRepository interface {
-	ListExpiringSoon(ctx context.Context) ([]*domain.Certificate, error)
+	ListExpiringSoon(ctx context.Context, daysLeft int) ([]*domain.Certificate, error)
 	GetById(ctx context.Context, id string) (*domain.Certificate, error)
 	DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+var haEnabled = false
+
+func init() {
+	envHAEnabled := os.Getenv("CERTIMATE_HA_ENABLED")
+	if envHAEnabled == "1" || envHAEnabled == "true" {
+		haEnabled = true
+	}
+}
+
+const (
+	heartbeatInterval = 10 * time.Second
+	heartbeatTimeout  = 30 * time.Second // 超过此时长未上报心跳的实例视为已失效
+)
+
+type Cluster interface {
+	InstanceId() string
+	IsHAEnabled() bool
+
+	Bootup(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+
+	// 获取所有存活实例的 ID，包含当前实例。
+	GetAliveInstanceIds(ctx context.Context) ([]string, error)
+	// 尝试获取指定名称的租约。未启用多实例模式时总是获取成功。
+	TryAcquireLease(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	// 注册心跳回调。仅在多实例模式下，每次上报心跳后调用。
+	OnHeartbeat(callback func(ctx context.Context))
+}
+
+type cluster struct {
+	booted     bool
+	instanceId string
+
+	loopCtx    context.Context
+	loopCancel context.CancelFunc
+	loopWg     sync.WaitGroup
+
+	hooksMtx         sync.RWMutex
+	onHeartbeatHooks []func(ctx context.Context)
+
+	instanceRepo instanceRepository
+	leaseRepo    leaseRepository
+
+	syslog *slog.Logger
+}
+
+var _ Cluster = (*cluster)(nil)
+
+func (c *cluster) InstanceId() string {
+	return c.instanceId
+}
+
+func (c *cluster) IsHAEnabled() bool {
+	return haEnabled
+}
+
+func (c *cluster) Bootup(ctx context.Context) error {
+	if c.booted {
+		return errors.New("could not re-bootup")
+	}
+
+	if haEnabled {
+		if err := c.heartbeat(ctx); err != nil {
+			return err
+		}
+
+		c.loopCtx, c.loopCancel = context.WithCancel(context.Background())
+		c.loopWg.Add(1)
+		go func() {
+			defer c.loopWg.Done()
+			c.heartbeatLoop(c.loopCtx)
+		}()
+
+		c.syslog.Info(fmt.Sprintf("cluster instance #%s is up", c.instanceId))
+	}
+
+	c.booted = true
+	return nil
+}
+
+func (c *cluster) Shutdown(ctx context.Context) error {
+	if !c.booted {
+		return errors.New("could not re-shutdown")
+	}
+
+	if haEnabled {
+		c.loopCancel()
+		c.loopWg.Wait()
+
+		// 主动注销实例，以便其他实例尽快接管本实例中断的运行
+		if err := c.instanceRepo.DeleteById(ctx, c.instanceId); err != nil {
+			return err
+		}
+	}
+
+	c.booted = false
+	return nil
+}
+
+func (c *cluster) GetAliveInstanceIds(ctx context.Context) ([]string, error) {
+	instanceIds := []string{c.instanceId}
+	if !haEnabled {
+		return instanceIds, nil
+	}
+
+	instances, err := c.instanceRepo.ListAlive(ctx, heartbeatTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range instances {
+		if !slices.Contains(instanceIds, instance.Id) {
+			instanceIds = append(instanceIds, instance.Id)
+		}
+	}
+
+	return instanceIds, nil
+}
+
+func (c *cluster) TryAcquireLease(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	if !haEnabled {
+		return true, nil
+	}
+
+	return c.leaseRepo.TryAcquire(ctx, name, c.instanceId, ttl)
+}
+
+func (c *cluster) OnHeartbeat(callback func(ctx context.Context)) {
+	c.hooksMtx.Lock()
+	defer c.hooksMtx.Unlock()
+	c.onHeartbeatHooks = append(c.onHeartbeatHooks, callback)
+}
+
+func (c *cluster) heartbeat(ctx context.Context) error {
+	hostname, _ := os.Hostname()
+	instance := &domain.Instance{
+		Meta:        domain.Meta{Id: c.instanceId},
+		Hostname:    hostname,
+		HeartbeatAt: time.Now(),
+	}
+	if _, err := c.instanceRepo.Save(ctx, instance); err != nil {
+		return fmt.Errorf("failed to report heartbeat of cluster instance #%s: %w", c.instanceId, err)
+	}
+
+	return nil
+}
+
+func (c *cluster) heartbeatLoop(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.heartbeat(ctx); err != nil {
+			c.syslog.Error(err.Error())
+			continue
+		}
+
+		if _, err := c.leaseRepo.DeleteWhere(ctx, dbx.NewExp("expiresAt<DATETIME('now')")); err != nil {
+			c.syslog.Warn("failed to cleanup expired leases", slog.Any("error", err))
+		}
+		if _, err := c.instanceRepo.DeleteWhere(ctx, dbx.NewExp("heartbeatAt<DATETIME('now', '-1 days')")); err != nil {
+			c.syslog.Warn("failed to cleanup dead instances", slog.Any("error", err))
+		}
+
+		c.fireOnHeartbeatHooks(ctx)
+	}
+}
+
+func (c *cluster) fireOnHeartbeatHooks(ctx context.Context) {
+	c.hooksMtx.RLock()
+	hooks := slices.Clone(c.onHeartbeatHooks)
+	c.hooksMtx.RUnlock()
+
+	for _, cb := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.syslog.Error(fmt.Sprintf("cluster: panic in onHeartbeat hook: %v", r))
+					slog.Default().Error(fmt.Sprintf("cluster: panic in onHeartbeat hook: %v, stack trace: %s", r, string(debug.Stack())))
+				}
+			}()
+
+			cb(ctx)
+		}()
+	}
+}
+
+func newCluster() Cluster {
+	return &cluster{
+		instanceId: core.GenerateDefaultRandomId(),
+
+		onHeartbeatHooks: make([]func(ctx context.Context), 0),
+
+		instanceRepo: repository.NewInstanceRepository(),
+		leaseRepo:    repository.NewLeaseRepository(),
+
+		syslog: app.GetLogger(),
+	}
+}
@@ -0,0 +1,22 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/pocketbase/dbx"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type instanceRepository interface {
+	ListAlive(ctx context.Context, timeout time.Duration) ([]*domain.Instance, error)
+	Save(ctx context.Context, instance *domain.Instance) (*domain.Instance, error)
+	DeleteById(ctx context.Context, id string) error
+	DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error)
+}
+
+type leaseRepository interface {
+	TryAcquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error)
+}
@@ -0,0 +1,17 @@
+package cluster
+
+import (
+	"sync"
+)
+
+var (
+	instance    Cluster
+	intanceOnce sync.Once
+)
+
+func GetSingletonCluster() Cluster {
+	intanceOnce.Do(func() {
+		instance = newCluster()
+	})
+	return instance
+}
@@ -335,6 +335,22 @@ type AccessConfigForRatPanel struct {
 	AllowInsecureConnections bool   `json:"allowInsecureConnections,omitempty"`
 }
 
+type AccessConfigForRFC2136 struct {
+	Nameserver    string `json:"nameserver"`
+	TsigAlgorithm string `json:"tsigAlgorithm,omitempty"`
+	TsigKey       string `json:"tsigKey,omitempty"`
+	TsigSecret    string `json:"tsigSecret,omitempty"`
+}
+
+type AccessConfigForS3 struct {
+	Endpoint                 string `json:"endpoint"`
+	Region                   string `json:"region"`
+	AccessKeyId              string `json:"accessKeyId"`
+	SecretAccessKey          string `json:"secretAccessKey"`
+	UsePathStyle             bool   `json:"usePathStyle,omitempty"`
+	AllowInsecureConnections bool   `json:"allowInsecureConnections,omitempty"`
+}
+
 type AccessConfigForSafeLine struct {
 	ServerUrl                string `json:"serverUrl"`
 	ApiToken                 string `json:"apiToken"`
@@ -38,7 +38,16 @@ type Certificate struct {
 }
 
 func (c *Certificate) PopulateFromX509(certX509 *x509.Certificate) *Certificate {
-	c.SubjectAltNames = strings.Join(certX509.DNSNames, ";")
+	subjectAltNames := make([]string, 0, len(certX509.DNSNames)+len(certX509.IPAddresses))
+	subjectAltNames = append(subjectAltNames, certX509.DNSNames...)
+	for _, ip := range certX509.IPAddresses {
+		subjectAltNames = append(subjectAltNames, ip.String())
+	}
+	subjectAltNames = append(subjectAltNames, certX509.EmailAddresses...)
+	for _, uri := range certX509.URIs {
+		subjectAltNames = append(subjectAltNames, uri.String())
+	}
+	c.SubjectAltNames = strings.Join(subjectAltNames, ";")
 	c.SerialNumber = strings.ToUpper(certX509.SerialNumber.Text(16))
 	c.IssuerOrg = strings.Join(certX509.Issuer.Organization, ";")
 	c.ValidityNotBefore = certX509.NotBefore
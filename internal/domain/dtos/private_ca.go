@@ -0,0 +1,28 @@
+package dtos
+
+import "github.com/certimate-go/certimate/internal/domain"
+
+type PrivateCACreateReq struct {
+	Name             string `json:"name"`                       // 名称（零值时默认值同通用名称）
+	ParentId         string `json:"parentId,omitempty"`         // 上级 CA 记录 ID（零值时创建根 CA，否则创建中间 CA）
+	CommonName       string `json:"commonName"`                 // 通用名称
+	Organization     string `json:"organization,omitempty"`     // 组织名称
+	KeyAlgorithm     string `json:"keyAlgorithm,omitempty"`     // 密钥算法（零值时默认值 "EC384"）
+	ValidityLifetime string `json:"validityLifetime,omitempty"` // 有效期，形如 "3650d"（零值时根 CA 默认 10 年，中间 CA 默认 5 年）
+	MaxPathLen       *int32 `json:"maxPathLen,omitempty"`       // 路径长度约束（未指定时根 CA 不限制，中间 CA 默认值 0）
+}
+
+type PrivateCACreateResp struct {
+	PrivateCA *domain.PrivateCA `json:"privateCA"`
+}
+
+type PrivateCAImportReq struct {
+	Name        string `json:"name"`               // 名称（零值时默认值同证书的通用名称）
+	ParentId    string `json:"parentId,omitempty"` // 上级 CA 记录 ID（零值时表示根 CA 或外部签发的中间 CA）
+	Certificate string `json:"certificate"`        // CA 证书 PEM 内容，可附带其上级证书链
+	PrivateKey  string `json:"privateKey"`         // CA 私钥 PEM 内容
+}
+
+type PrivateCAImportResp struct {
+	PrivateCA *domain.PrivateCA `json:"privateCA"`
+}
@@ -5,12 +5,44 @@ import "github.com/certimate-go/certimate/internal/domain"
 type WorkflowStartRunReq struct {
 	WorkflowId string                     `json:"-"`
 	RunTrigger domain.WorkflowTriggerType `json:"trigger"`
+	RunPayload map[string]any             `json:"-"`
+	DryRun     bool                       `json:"dryRun,omitempty"`
+	Priority   *int32                     `json:"priority,omitempty"` // 运行优先级（未指定时使用工作流的优先级）
 }
 
 type WorkflowStartRunResp struct {
 	RunId string `json:"runId"`
 }
 
+type WorkflowWebhookTriggerReq struct {
+	WorkflowId string `json:"-"`
+	WebhookKey string `json:"-"`
+	Signature  string `json:"-"`
+	Body       []byte `json:"-"`
+}
+
+type WorkflowResumeRunReq struct {
+	WorkflowId string `json:"-"`
+	RunId      string `json:"-"`
+}
+
+type WorkflowResumeRunResp struct {
+	RunId string `json:"runId"`
+}
+
+type WorkflowPreviewTemplateReq struct {
+	WorkflowId string `json:"-"`
+	RunId      string `json:"-"`
+	NodeId     string `json:"nodeId,omitempty"`  // 通知节点 ID（非零值时，缺省的模板取自运行流程图中该节点的配置）
+	Subject    string `json:"subject,omitempty"` // 通知主题模板
+	Message    string `json:"message,omitempty"` // 通知内容模板
+}
+
+type WorkflowPreviewTemplateResp struct {
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
 type WorkflowCancelRunReq struct {
 	WorkflowId string `json:"-"`
 	RunId      string `json:"-"`
@@ -18,8 +50,114 @@ type WorkflowCancelRunReq struct {
 
 type WorkflowCancelRunResp struct{}
 
+type WorkflowDecideApprovalReq struct {
+	WorkflowId string `json:"-"`
+	RunId      string `json:"-"`
+	Action     string `json:"-"`                 // 审批操作，可取值 "approve"、"reject"
+	Comment    string `json:"comment,omitempty"` // 审批意见
+	DecidedBy  string `json:"-"`                 // 审批人
+}
+
+type WorkflowDecideApprovalByLinkReq struct {
+	WorkflowId string `json:"-"`
+	RunId      string `json:"-"`
+	NodeId     string `json:"-"`
+	Action     string `json:"-"`
+	Expires    int64  `json:"-"` // 链接过期时间戳（为零值时表示不过期）
+	Signature  string `json:"-"`
+	Comment    string `json:"-"` // 审批意见
+	DecidedBy  string `json:"-"` // 审批人名称（可选，仅用于记录）
+}
+
+type WorkflowDecideApprovalResp struct {
+	Status domain.WorkflowRunApprovalStatusType `json:"status"`
+}
+
 type WorkflowStatisticsResp struct {
-	Concurrency      int      `json:"concurrency"`
-	PendingRunIds    []string `json:"pendingRunIds"`
-	ProcessingRunIds []string `json:"processingRunIds"`
+	Concurrency         int                                  `json:"concurrency"`
+	ProviderConcurrency map[string]int                       `json:"providerConcurrency"`
+	PendingRunIds       []string                             `json:"pendingRunIds"`
+	PendingRunGroups    []*WorkflowStatisticsPendingRunGroup `json:"pendingRunGroups"`
+	ProcessingRunIds    []string                             `json:"processingRunIds"`
+}
+
+type WorkflowStatisticsPendingRunGroup struct {
+	Priority int32    `json:"priority"`
+	RunIds   []string `json:"runIds"`
+}
+
+type WorkflowDiffVersionsReq struct {
+	WorkflowId  string `json:"-"`
+	FromVersion int32  `json:"-"`
+	ToVersion   int32  `json:"-"`
+}
+
+type WorkflowDiffVersionsResp struct {
+	From  *domain.WorkflowVersion         `json:"from"`
+	To    *domain.WorkflowVersion         `json:"to"`
+	Nodes []*domain.WorkflowGraphNodeDiff `json:"nodes"`
+}
+
+type WorkflowRollbackVersionReq struct {
+	WorkflowId string `json:"-"`
+	Version    int32  `json:"-"`
+	Author     string `json:"-"`
+	Note       string `json:"note,omitempty"`
+}
+
+type WorkflowRollbackVersionResp struct {
+	Version int32 `json:"version"`
+}
+
+type WorkflowRotateWebhookSecretReq struct {
+	WorkflowId string `json:"-"`
+	Revoke     bool   `json:"revoke,omitempty"` // 是否清除签名密钥（即不再校验签名）
+}
+
+type WorkflowRotateWebhookSecretResp struct {
+	WebhookSecret string `json:"webhookSecret"` // 新生成的签名密钥（仅在此处返回一次）
+}
+
+type WorkflowValidateGraphReq struct {
+	WorkflowId string                `json:"-"`
+	Graph      *domain.WorkflowGraph `json:"graph,omitempty"` // 待校验的流程图（零值时校验当前草稿）
+}
+
+type WorkflowValidateGraphResp struct {
+	Valid  bool                       `json:"valid"` // 是否不存在错误级别的问题
+	Issues domain.WorkflowGraphIssues `json:"issues"`
+}
+
+type WorkflowExportBundleReq struct {
+	WorkflowId string `json:"-"`
+	Format     string `json:"format"` // 导出格式，可取值 "yaml"、"json"（零值时默认值 "yaml"）
+}
+
+type WorkflowExportBundleResp struct {
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+type WorkflowImportBundleReq struct {
+	Content        string            `json:"content"`        // YAML 或 JSON 格式的导出包内容
+	AccessMappings map[string]string `json:"accessMappings"` // 授权记录占位符到本地授权记录 ID 的映射，Key 为占位符的引用名
+	Author         string            `json:"-"`
+}
+
+type WorkflowImportBundleResp struct {
+	Workflows []*WorkflowImportBundleRespItem `json:"workflows"`
+}
+
+type WorkflowImportBundleRespItem struct {
+	Ref  string `json:"ref"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WorkflowGitOpsStatusResp struct {
+	Enabled bool                          `json:"enabled"`
+	Dir     string                        `json:"dir,omitempty"`
+	Mode    domain.WorkflowGitOpsModeType `json:"mode,omitempty"`
+	Prune   bool                          `json:"prune"`
+	Report  *domain.WorkflowGitOpsReport  `json:"report,omitempty"` // 最近一次同步的报告
 }
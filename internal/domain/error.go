@@ -3,6 +3,9 @@ package domain
 var (
 	ErrInvalidParams  = NewError(400, "invalid params")
 	ErrRecordNotFound = NewError(404, "record not found")
+
+	ErrWebhookUnauthorized      = NewError(401, "invalid webhook key or signature")
+	ErrApprovalLinkUnauthorized = NewError(401, "invalid approval link or signature")
 )
 
 type Error struct {
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTypeCertificateIssued    = EventType("certificate.issued")
+	EventTypeCertificateExpiring  = EventType("certificate.expiring")
+	EventTypeWorkflowRunSucceeded = EventType("workflowRun.succeeded")
+	EventTypeWorkflowRunFailed    = EventType("workflowRun.failed")
+)
+
+const (
+	// 发布证书即将过期事件的范围（剩余有效天数），工作流触发器中的阈值不能超过该值
+	CertificateExpiringEventMaxDaysLeft = 90
+	// 事件触发链的最大深度，达到后不再触发其他工作流
+	EventChainMaxDepth = 8
+)
+
+type Event struct {
+	Type      EventType      `json:"type"`
+	Payload   map[string]any `json:"payload"`
+	Chain     []string       `json:"chain,omitempty"` // 产生该事件的工作流触发链（按触发顺序排列的工作流 ID），用于避免循环触发
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+func NewCertificateEvent(eventType EventType, certificate *Certificate) *Event {
+	return &Event{
+		Type: eventType,
+		Payload: map[string]any{
+			"certificateId":     certificate.Id,
+			"source":            string(certificate.Source),
+			"subjectAltNames":   certificate.SubjectAltNames,
+			"domains":           strings.Split(certificate.SubjectAltNames, ";"),
+			"serialNumber":      certificate.SerialNumber,
+			"issuerOrg":         certificate.IssuerOrg,
+			"keyAlgorithm":      string(certificate.KeyAlgorithm),
+			"validityNotBefore": certificate.ValidityNotBefore.Format(time.RFC3339),
+			"validityNotAfter":  certificate.ValidityNotAfter.Format(time.RFC3339),
+			"daysLeft":          int64(math.Floor(time.Until(certificate.ValidityNotAfter).Hours() / 24)),
+			"workflowId":        certificate.WorkflowId,
+			"workflowRunId":     certificate.WorkflowRunId,
+			"workflowNodeId":    certificate.WorkflowNodeId,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+func NewWorkflowRunEvent(eventType EventType, workflow *Workflow, workflowRun *WorkflowRun) *Event {
+	return &Event{
+		Type: eventType,
+		Payload: map[string]any{
+			"workflowId":    workflow.Id,
+			"workflowName":  workflow.Name,
+			"workflowRunId": workflowRun.Id,
+			"trigger":       string(workflowRun.Trigger),
+			"status":        string(workflowRun.Status),
+			"error":         workflowRun.Error,
+		},
+		Chain:     append(slices.Clone(workflowRun.GetTriggerChain()), workflow.Id),
+		Timestamp: time.Now(),
+	}
+}
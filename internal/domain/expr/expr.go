@@ -3,13 +3,17 @@ package expr
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type (
 	ExprType               string
 	ExprComparisonOperator string
 	ExprLogicalOperator    string
+	ExprArithmeticOperator string
 	ExprValueType          string
 )
 
@@ -20,20 +24,34 @@ const (
 	LessOrEqual    ExprComparisonOperator = "lte"
 	Equal          ExprComparisonOperator = "eq"
 	NotEqual       ExprComparisonOperator = "neq"
+	Is             ExprComparisonOperator = "is" // 同 eq，兼容旧版本
+	In             ExprComparisonOperator = "in"
+	NotIn          ExprComparisonOperator = "nin"
 
 	And ExprLogicalOperator = "and"
 	Or  ExprLogicalOperator = "or"
 	Not ExprLogicalOperator = "not"
 
-	Number  ExprValueType = "number"
-	String  ExprValueType = "string"
-	Boolean ExprValueType = "boolean"
+	Add      ExprArithmeticOperator = "add"
+	Subtract ExprArithmeticOperator = "sub"
+	Multiply ExprArithmeticOperator = "mul"
+	Divide   ExprArithmeticOperator = "div"
+	Modulo   ExprArithmeticOperator = "mod"
+
+	Number   ExprValueType = "number"
+	String   ExprValueType = "string"
+	Boolean  ExprValueType = "boolean"
+	Datetime ExprValueType = "datetime"
+	List     ExprValueType = "list"
 
 	ConstantExprType   ExprType = "const"
 	VariantExprType    ExprType = "var"
 	ComparisonExprType ExprType = "comparison"
 	LogicalExprType    ExprType = "logical"
 	NotExprType        ExprType = "not"
+	ArithmeticExprType ExprType = "arithmetic"
+	FunctionExprType   ExprType = "func"
+	ListExprType       ExprType = "list"
 )
 
 type EvalResult struct {
@@ -41,21 +59,102 @@ type EvalResult struct {
 	Value any
 }
 
+func newEvalResult(value any) *EvalResult {
+	switch v := value.(type) {
+	case bool:
+		return &EvalResult{Type: Boolean, Value: v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return &EvalResult{Type: Number, Value: v}
+	case time.Time:
+		return &EvalResult{Type: Datetime, Value: v}
+	case []string, []any, []*EvalResult:
+		return &EvalResult{Type: List, Value: v}
+	case string:
+		return &EvalResult{Type: String, Value: v}
+	default:
+		return &EvalResult{Type: String, Value: fmt.Sprintf("%v", v)}
+	}
+}
+
+// 将值转换为指定类型。
+// 未声明类型的值可转换为任意类型；字符串还可转换为日期时间或列表，其他情况视为类型不匹配。
+func (e *EvalResult) coerce(typ ExprValueType) (*EvalResult, error) {
+	if e.Type == typ {
+		return e, nil
+	}
+
+	switch {
+	case e.Type == "":
+	case e.Type == String && (typ == Datetime || typ == List):
+	default:
+		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, typ)
+	}
+
+	return &EvalResult{Type: typ, Value: e.Value}, nil
+}
+
+// 将两个值转换为相同的类型，以便进行比较。
+func unifyEvalResults(left, right *EvalResult) (*EvalResult, *EvalResult, error) {
+	if left.Type == right.Type {
+		if left.Type == "" {
+			return &EvalResult{Type: String, Value: left.Value}, &EvalResult{Type: String, Value: right.Value}, nil
+		}
+		return left, right, nil
+	}
+
+	if left.Type == "" || (left.Type == String && right.Type != "") {
+		if l, err := left.coerce(right.Type); err == nil {
+			return l, right, nil
+		}
+	}
+	if r, err := right.coerce(left.Type); err == nil {
+		return left, r, nil
+	}
+
+	return nil, nil, fmt.Errorf("type mismatch: %s vs %s", left.Type, right.Type)
+}
+
 func (e *EvalResult) GetFloat64() (float64, error) {
 	if e.Type != Number {
 		return 0, fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
-	stringValue, ok := e.Value.(string)
-	if !ok {
-		return 0, fmt.Errorf("value is not a string: %v", e.Value)
-	}
-
-	floatValue, err := strconv.ParseFloat(stringValue, 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse float64: %w", err)
+	switch v := e.Value.(type) {
+	case string:
+		floatValue, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse float64: %w", err)
+		}
+		return floatValue, nil
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("value is not a number: %v", e.Value)
 	}
-	return floatValue, nil
 }
 
 func (e *EvalResult) GetBool() (bool, error) {
@@ -81,302 +180,486 @@ func (e *EvalResult) GetBool() (bool, error) {
 	return boolValue, nil
 }
 
-func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) GetString() (string, error) {
+	if e.Type != String {
+		return "", fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) > other.Value.(string),
-		}, nil
+	if strValue, ok := e.Value.(string); ok {
+		return strValue, nil
+	}
 
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
+	return fmt.Sprintf("%v", e.Value), nil
+}
+
+func (e *EvalResult) GetTime() (time.Time, error) {
+	if e.Type != Datetime {
+		return time.Time{}, fmt.Errorf("type mismatch: %s", e.Type)
+	}
+
+	switch v := e.Value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" || v == "-" {
+			return time.Time{}, nil
 		}
 
-		right, err := other.GetFloat64()
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("value is not a datetime: %v", e.Value)
+	default:
+		return time.Time{}, fmt.Errorf("value is not a datetime: %v", e.Value)
+	}
+}
+
+// 获取列表值。
+// 字符串会被视为以分号分隔的列表（与 certificate.domains 等变量的格式一致）。
+func (e *EvalResult) GetList() ([]*EvalResult, error) {
+	switch e.Type {
+	case List:
+		switch v := e.Value.(type) {
+		case []*EvalResult:
+			return v, nil
+		case []string:
+			items := make([]*EvalResult, 0, len(v))
+			for _, item := range v {
+				items = append(items, &EvalResult{Type: String, Value: item})
+			}
+			return items, nil
+		case []any:
+			items := make([]*EvalResult, 0, len(v))
+			for _, item := range v {
+				items = append(items, newEvalResult(item))
+			}
+			return items, nil
+		case string:
+			return (&EvalResult{Type: String, Value: v}).GetList()
+		default:
+			return nil, fmt.Errorf("value is not a list: %v", e.Value)
+		}
+
+	case String:
+		strValue, err := e.GetString()
 		if err != nil {
 			return nil, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left > right,
-		}, nil
+		items := make([]*EvalResult, 0)
+		for _, item := range strings.Split(strValue, ";") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			items = append(items, &EvalResult{Type: String, Value: item})
+		}
+		return items, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return nil, fmt.Errorf("type mismatch: %s", e.Type)
 	}
 }
 
-func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+// 比较两个值的大小，返回 -1、0 或 1。
+func (e *EvalResult) compare(other *EvalResult) (int, error) {
+	untyped := e.Type == "" && other.Type == ""
+
+	left, right, err := unifyEvalResults(e, other)
+	if err != nil {
+		return 0, err
 	}
 
-	switch e.Type {
+	switch left.Type {
 	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) >= other.Value.(string),
-		}, nil
+		l, err := left.GetString()
+		if err != nil {
+			return 0, err
+		}
+
+		r, err := right.GetString()
+		if err != nil {
+			return 0, err
+		}
+
+		// 两侧均未声明类型且均能解析为数字时按数值比较，以免出现 "9" > "10" 的情况；
+		// 已声明为字符串的值始终按字典序比较
+		if untyped {
+			if lf, ok := parseNumericString(l); ok {
+				if rf, ok := parseNumericString(r); ok {
+					return (&EvalResult{Type: Number, Value: lf}).compare(&EvalResult{Type: Number, Value: rf})
+				}
+			}
+		}
+
+		return strings.Compare(l, r), nil
 
 	case Number:
-		left, err := e.GetFloat64()
+		l, err := left.GetFloat64()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		right, err := other.GetFloat64()
+		r, err := right.GetFloat64()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left >= right,
-		}, nil
+		switch {
+		case l < r:
+			return -1, nil
+		case l > r:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+
+	case Datetime:
+		l, err := left.GetTime()
+		if err != nil {
+			return 0, err
+		}
+
+		r, err := right.GetTime()
+		if err != nil {
+			return 0, err
+		}
+
+		return l.Compare(r), nil
 
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return 0, fmt.Errorf("unsupported value type: %s", left.Type)
 	}
 }
 
-func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func parseNumericString(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
 	}
+	return f, true
+}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) < other.Value.(string),
-		}, nil
+func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
+	}
 
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c > 0,
+	}, nil
+}
 
-		right, err := other.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
+	}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left < right,
-		}, nil
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c >= 0,
+	}, nil
+}
 
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
 	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c < 0,
+	}, nil
 }
 
 func (e *EvalResult) LessOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) <= other.Value.(string),
-		}, nil
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c <= 0,
+	}, nil
+}
 
-	case Number:
-		left, err := e.GetFloat64()
+func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
+	left, right, err := unifyEvalResults(e, other)
+	if err != nil {
+		return nil, err
+	}
+
+	switch left.Type {
+	case String:
+		// 字符串按字节比较是否相等，不作数值转换，以免 "0123" 与 "123" 被视为相等
+		l, err := left.GetString()
 		if err != nil {
 			return nil, err
 		}
 
-		right, err := other.GetFloat64()
+		r, err := right.GetString()
 		if err != nil {
 			return nil, err
 		}
 
 		return &EvalResult{
 			Type:  Boolean,
-			Value: left <= right,
+			Value: l == r,
 		}, nil
 
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
-	}
-}
-
-func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
-	}
+	case Number, Datetime:
+		c, err := left.compare(right)
+		if err != nil {
+			return nil, err
+		}
 
-	switch e.Type {
-	case String:
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) == other.Value.(string),
+			Value: c == 0,
 		}, nil
 
-	case Number:
-		left, err := e.GetFloat64()
+	case Boolean:
+		l, err := left.GetBool()
 		if err != nil {
 			return nil, err
 		}
 
-		right, err := other.GetFloat64()
+		r, err := right.GetBool()
 		if err != nil {
 			return nil, err
 		}
 
 		return &EvalResult{
 			Type:  Boolean,
-			Value: left == right,
+			Value: l == r,
 		}, nil
 
-	case Boolean:
-		left, err := e.GetBool()
+	case List:
+		l, err := left.GetList()
 		if err != nil {
 			return nil, err
 		}
 
-		right, err := other.GetBool()
+		r, err := right.GetList()
 		if err != nil {
 			return nil, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left == right,
-		}, nil
+		if len(l) != len(r) {
+			return &EvalResult{Type: Boolean, Value: false}, nil
+		}
+		for i := range l {
+			eq, err := l[i].Equal(r[i])
+			if err != nil {
+				return nil, err
+			}
+			if eq.Value == false {
+				return eq, nil
+			}
+		}
+		return &EvalResult{Type: Boolean, Value: true}, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return nil, fmt.Errorf("unsupported value type: %s", left.Type)
 	}
 }
 
 func (e *EvalResult) NotEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	eq, err := e.Equal(other)
+	if err != nil {
+		return nil, err
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) != other.Value.(string),
-		}, nil
+	return eq.Not()
+}
 
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
+// 判断值是否是列表中的元素。
+// 右侧不是列表，或元素与值的类型不匹配时，视为不包含，而不是返回错误。
+func (e *EvalResult) In(other *EvalResult) (*EvalResult, error) {
+	list, err := other.coerce(List)
+	if err != nil {
+		return &EvalResult{Type: Boolean, Value: false}, nil
+	}
+
+	items, err := list.GetList()
+	if err != nil {
+		return &EvalResult{Type: Boolean, Value: false}, nil
+	}
+
+	for _, item := range items {
+		if _, _, err := unifyEvalResults(e, item); err != nil {
+			continue
 		}
 
-		right, err := other.GetFloat64()
+		eq, err := e.Equal(item)
 		if err != nil {
 			return nil, err
 		}
+		if eq.Value == true {
+			return eq, nil
+		}
+	}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left != right,
-		}, nil
+	return &EvalResult{
+		Type:  Boolean,
+		Value: false,
+	}, nil
+}
 
-	case Boolean:
-		left, err := e.GetBool()
-		if err != nil {
-			return nil, err
-		}
+func (e *EvalResult) And(other *EvalResult) (*EvalResult, error) {
+	left, err := e.coerce(Boolean)
+	if err != nil {
+		return nil, err
+	}
 
-		right, err := other.GetBool()
-		if err != nil {
-			return nil, err
-		}
+	right, err := other.coerce(Boolean)
+	if err != nil {
+		return nil, err
+	}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left != right,
-		}, nil
+	l, err := left.GetBool()
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	r, err := right.GetBool()
+	if err != nil {
+		return nil, err
 	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: l && r,
+	}, nil
 }
 
-func (e *EvalResult) And(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) Or(other *EvalResult) (*EvalResult, error) {
+	left, err := e.coerce(Boolean)
+	if err != nil {
+		return nil, err
 	}
 
-	switch e.Type {
-	case Boolean:
-		left, err := e.GetBool()
-		if err != nil {
-			return nil, err
-		}
+	right, err := other.coerce(Boolean)
+	if err != nil {
+		return nil, err
+	}
 
-		right, err := other.GetBool()
-		if err != nil {
-			return nil, err
-		}
+	l, err := left.GetBool()
+	if err != nil {
+		return nil, err
+	}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left && right,
-		}, nil
+	r, err := right.GetBool()
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	return &EvalResult{
+		Type:  Boolean,
+		Value: l || r,
+	}, nil
+}
+
+func (e *EvalResult) Not() (*EvalResult, error) {
+	inner, err := e.coerce(Boolean)
+	if err != nil {
+		return nil, err
+	}
+
+	boolValue, err := inner.GetBool()
+	if err != nil {
+		return nil, err
 	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: !boolValue,
+	}, nil
 }
 
-func (e *EvalResult) Or(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) Arithmetic(operator ExprArithmeticOperator, other *EvalResult) (*EvalResult, error) {
+	left, right := e, other
+
+	// 两侧均未声明类型时，若都能解析为数字则按数字运算
+	if e.Type == "" && other.Type == "" {
+		l := &EvalResult{Type: Number, Value: e.Value}
+		r := &EvalResult{Type: Number, Value: other.Value}
+		if _, err := l.GetFloat64(); err == nil {
+			if _, err := r.GetFloat64(); err == nil {
+				left, right = l, r
+			}
+		}
 	}
 
-	switch e.Type {
-	case Boolean:
-		left, err := e.GetBool()
+	left, right, err := unifyEvalResults(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	if left.Type == String && operator == Add {
+		l, err := left.GetString()
 		if err != nil {
 			return nil, err
 		}
 
-		right, err := other.GetBool()
+		r, err := right.GetString()
 		if err != nil {
 			return nil, err
 		}
 
 		return &EvalResult{
-			Type:  Boolean,
-			Value: left || right,
+			Type:  String,
+			Value: l + r,
 		}, nil
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
-}
 
-func (e *EvalResult) Not() (*EvalResult, error) {
-	if e.Type != Boolean {
-		return nil, fmt.Errorf("type mismatch: %s", e.Type)
+	if left.Type != Number {
+		return nil, fmt.Errorf("unsupported value type: %s", left.Type)
+	}
+
+	l, err := left.GetFloat64()
+	if err != nil {
+		return nil, err
 	}
 
-	boolValue, err := e.GetBool()
+	r, err := right.GetFloat64()
 	if err != nil {
 		return nil, err
 	}
 
+	var value float64
+	switch operator {
+	case Add:
+		value = l + r
+	case Subtract:
+		value = l - r
+	case Multiply:
+		value = l * r
+	case Divide:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		value = l / r
+	case Modulo:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		value = math.Mod(l, r)
+	default:
+		return nil, fmt.Errorf("unknown expression operator: %s", operator)
+	}
+
 	return &EvalResult{
-		Type:  Boolean,
-		Value: !boolValue,
+		Type:  Number,
+		Value: value,
 	}, nil
 }
 
@@ -386,7 +669,7 @@ type Expr interface {
 }
 
 type ExprValueSelector struct {
-	Id   string        `json:"id"`
+	Id   string        `json:"id"` // 节点 ID（零值时表示全局变量）
 	Name string        `json:"name"`
 	Type ExprValueType `json:"type"`
 }
@@ -399,6 +682,32 @@ type ConstantExpr struct {
 
 func (c ConstantExpr) GetType() ExprType { return c.Type }
 
+func (c *ConstantExpr) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type      ExprType        `json:"type"`
+		Value     json.RawMessage `json:"value"`
+		ValueType ExprValueType   `json:"valueType"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c.Type = raw.Type
+	c.ValueType = raw.ValueType
+	c.Value = ""
+
+	// 兼容常量值为非字符串的情况，如 `true`、`2`
+	if len(raw.Value) > 0 && raw.Value[0] == '"' {
+		if err := json.Unmarshal(raw.Value, &c.Value); err != nil {
+			return err
+		}
+	} else if len(raw.Value) > 0 && string(raw.Value) != "null" {
+		c.Value = string(raw.Value)
+	}
+
+	return nil
+}
+
 func (c ConstantExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
 	return &EvalResult{
 		Type:  c.ValueType,
@@ -414,23 +723,35 @@ type VariantExpr struct {
 func (v VariantExpr) GetType() ExprType { return v.Type }
 
 func (v VariantExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
-	if v.Selector.Id == "" {
-		return nil, fmt.Errorf("node id is empty")
-	}
 	if v.Selector.Name == "" {
 		return nil, fmt.Errorf("name is empty")
 	}
 
 	if _, ok := variables[v.Selector.Id]; !ok {
+		if v.Selector.Id == "" {
+			return nil, fmt.Errorf("global variable %s not found", v.Selector.Name)
+		}
 		return nil, fmt.Errorf("node %s not found", v.Selector.Id)
 	}
 
-	if _, ok := variables[v.Selector.Id][v.Selector.Name]; !ok {
+	value, ok := variables[v.Selector.Id][v.Selector.Name]
+	if !ok {
+		if v.Selector.Id == "" {
+			return nil, fmt.Errorf("global variable %s not found", v.Selector.Name)
+		}
 		return nil, fmt.Errorf("variable %s not found in node %s", v.Selector.Name, v.Selector.Id)
 	}
+
+	// 未声明类型时，非字符串值按其实际类型处理，字符串值则在运算时再根据另一侧推断
+	if v.Selector.Type == "" {
+		if _, ok := value.(string); !ok {
+			return newEvalResult(value), nil
+		}
+	}
+
 	return &EvalResult{
 		Type:  v.Selector.Type,
-		Value: variables[v.Selector.Id][v.Selector.Name],
+		Value: value,
 	}, nil
 }
 
@@ -462,10 +783,18 @@ func (c ComparisonExpr) Eval(variables map[string]map[string]any) (*EvalResult,
 		return left.GreaterOrEqual(right)
 	case LessOrEqual:
 		return left.LessOrEqual(right)
-	case Equal:
+	case Equal, Is:
 		return left.Equal(right)
 	case NotEqual:
 		return left.NotEqual(right)
+	case In:
+		return left.In(right)
+	case NotIn:
+		rs, err := left.In(right)
+		if err != nil {
+			return nil, err
+		}
+		return rs.Not()
 	default:
 		return nil, fmt.Errorf("unknown expression operator: %s", c.Operator)
 	}
@@ -515,6 +844,81 @@ func (n NotExpr) Eval(variables map[string]map[string]any) (*EvalResult, error)
 	return inner.Not()
 }
 
+type ArithmeticExpr struct {
+	Type     ExprType               `json:"type"` // arithmetic
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     Expr                   `json:"left"`
+	Right    Expr                   `json:"right"`
+}
+
+func (a ArithmeticExpr) GetType() ExprType { return a.Type }
+
+func (a ArithmeticExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	left, err := a.Left.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.Right.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return left.Arithmetic(a.Operator, right)
+}
+
+type FunctionExpr struct {
+	Type ExprType `json:"type"` // func
+	Name string   `json:"name"`
+	Args []Expr   `json:"args"`
+}
+
+func (f FunctionExpr) GetType() ExprType { return f.Type }
+
+func (f FunctionExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	fn, err := lookupFunction(f.Name, len(f.Args))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]*EvalResult, 0, len(f.Args))
+	for _, arg := range f.Args {
+		rs, err := arg.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, rs)
+	}
+
+	rs, err := fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", f.Name, err)
+	}
+	return rs, nil
+}
+
+type ListExpr struct {
+	Type  ExprType `json:"type"` // list
+	Items []Expr   `json:"items"`
+}
+
+func (l ListExpr) GetType() ExprType { return l.Type }
+
+func (l ListExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	items := make([]*EvalResult, 0, len(l.Items))
+	for _, item := range l.Items {
+		rs, err := item.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rs)
+	}
+
+	return &EvalResult{
+		Type:  List,
+		Value: items,
+	}, nil
+}
+
 type rawExpr struct {
 	Type ExprType `json:"type"`
 }
@@ -524,6 +928,15 @@ func MarshalExpr(e Expr) ([]byte, error) {
 }
 
 func UnmarshalExpr(data []byte) (Expr, error) {
+	// 文本形式的表达式
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, `"`) {
+		var text string
+		if err := json.Unmarshal([]byte(trimmed), &text); err != nil {
+			return nil, err
+		}
+		return ParseExpr(text)
+	}
+
 	var typ rawExpr
 	if err := json.Unmarshal(data, &typ); err != nil {
 		return nil, err
@@ -560,6 +973,24 @@ func UnmarshalExpr(data []byte) (Expr, error) {
 			return nil, err
 		}
 		return e.ToNotExpr()
+	case ArithmeticExprType:
+		var e ArithmeticExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToArithmeticExpr()
+	case FunctionExprType:
+		var e FunctionExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToFunctionExpr()
+	case ListExprType:
+		var e ListExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToListExpr()
 	default:
 		return nil, fmt.Errorf("unknown expression type: %s", typ.Type)
 	}
@@ -628,3 +1059,119 @@ func (r NotExprRaw) ToNotExpr() (NotExpr, error) {
 		Expr: inner,
 	}, nil
 }
+
+type ArithmeticExprRaw struct {
+	Type     ExprType               `json:"type"`
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     json.RawMessage        `json:"left"`
+	Right    json.RawMessage        `json:"right"`
+}
+
+func (r ArithmeticExprRaw) ToArithmeticExpr() (ArithmeticExpr, error) {
+	left, err := UnmarshalExpr(r.Left)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	right, err := UnmarshalExpr(r.Right)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	return ArithmeticExpr{
+		Type:     r.Type,
+		Operator: r.Operator,
+		Left:     left,
+		Right:    right,
+	}, nil
+}
+
+type FunctionExprRaw struct {
+	Type ExprType          `json:"type"`
+	Name string            `json:"name"`
+	Args []json.RawMessage `json:"args"`
+}
+
+func (r FunctionExprRaw) ToFunctionExpr() (FunctionExpr, error) {
+	if _, err := lookupFunction(r.Name, len(r.Args)); err != nil {
+		return FunctionExpr{}, err
+	}
+
+	args := make([]Expr, 0, len(r.Args))
+	for _, argRaw := range r.Args {
+		arg, err := UnmarshalExpr(argRaw)
+		if err != nil {
+			return FunctionExpr{}, err
+		}
+		args = append(args, arg)
+	}
+	return FunctionExpr{
+		Type: r.Type,
+		Name: r.Name,
+		Args: args,
+	}, nil
+}
+
+type ListExprRaw struct {
+	Type  ExprType          `json:"type"`
+	Items []json.RawMessage `json:"items"`
+}
+
+func (r ListExprRaw) ToListExpr() (ListExpr, error) {
+	items := make([]Expr, 0, len(r.Items))
+	for _, itemRaw := range r.Items {
+		item, err := UnmarshalExpr(itemRaw)
+		if err != nil {
+			return ListExpr{}, err
+		}
+		items = append(items, item)
+	}
+	return ListExpr{
+		Type:  r.Type,
+		Items: items,
+	}, nil
+}
+
+// 获取表达式中引用的全部变量选择器。
+func GetSelectors(e Expr) []ExprValueSelector {
+	selectors := make([]ExprValueSelector, 0)
+
+	switch v := e.(type) {
+	case VariantExpr:
+		selectors = append(selectors, v.Selector)
+	case ComparisonExpr:
+		selectors = append(selectors, GetSelectors(v.Left)...)
+		selectors = append(selectors, GetSelectors(v.Right)...)
+	case LogicalExpr:
+		selectors = append(selectors, GetSelectors(v.Left)...)
+		selectors = append(selectors, GetSelectors(v.Right)...)
+	case NotExpr:
+		selectors = append(selectors, GetSelectors(v.Expr)...)
+	case ArithmeticExpr:
+		selectors = append(selectors, GetSelectors(v.Left)...)
+		selectors = append(selectors, GetSelectors(v.Right)...)
+	case FunctionExpr:
+		for _, arg := range v.Args {
+			selectors = append(selectors, GetSelectors(arg)...)
+		}
+	case ListExpr:
+		for _, item := range v.Items {
+			selectors = append(selectors, GetSelectors(item)...)
+		}
+	}
+
+	return selectors
+}
+
+// 对表达式求值，并将结果转换为布尔值。
+func EvalBool(e Expr, variables map[string]map[string]any) (bool, error) {
+	rs, err := e.Eval(variables)
+	if err != nil {
+		return false, err
+	}
+
+	rs, err = rs.coerce(Boolean)
+	if err != nil {
+		return false, fmt.Errorf("expression result is not a boolean: %w", err)
+	}
+
+	return rs.GetBool()
+}
@@ -0,0 +1,234 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type exprFunction struct {
+	minArgs int
+	maxArgs int
+	call    func(args []*EvalResult) (*EvalResult, error)
+}
+
+var exprFunctions = map[string]exprFunction{
+	// 字符串或列表的长度
+	"len": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		if args[0].Type == List {
+			items, err := args[0].GetList()
+			if err != nil {
+				return nil, err
+			}
+			return &EvalResult{Type: Number, Value: float64(len(items))}, nil
+		}
+
+		s, err := funcArgString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &EvalResult{Type: Number, Value: float64(len([]rune(s)))}, nil
+	}},
+
+	// 列表是否包含元素，字符串视为以分号分隔的列表（如 certificate.domains），按元素而非子串匹配；如需匹配子串，请使用 matches
+	"contains": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		return args[1].In(args[0])
+	}},
+
+	"hasPrefix": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		return funcStringPredicate(args, strings.HasPrefix)
+	}},
+
+	"hasSuffix": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		return funcStringPredicate(args, strings.HasSuffix)
+	}},
+
+	// 正则表达式匹配
+	"matches": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		s, err := funcArgString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := funcArgString(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return &EvalResult{Type: Boolean, Value: re.MatchString(s)}, nil
+	}},
+
+	// 按分隔符拆分字符串为列表，分隔符缺省为分号
+	"split": {1, 2, func(args []*EvalResult) (*EvalResult, error) {
+		s, err := funcArgString(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		sep := ";"
+		if len(args) > 1 {
+			if sep, err = funcArgString(args[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		items := make([]*EvalResult, 0)
+		if s != "" {
+			for _, item := range strings.Split(s, sep) {
+				items = append(items, &EvalResult{Type: String, Value: item})
+			}
+		}
+		return &EvalResult{Type: List, Value: items}, nil
+	}},
+
+	"replace": {3, 3, func(args []*EvalResult) (*EvalResult, error) {
+		s, err := funcArgString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		old, err := funcArgString(args[1])
+		if err != nil {
+			return nil, err
+		}
+		new, err := funcArgString(args[2])
+		if err != nil {
+			return nil, err
+		}
+		return &EvalResult{Type: String, Value: strings.ReplaceAll(s, old, new)}, nil
+	}},
+
+	"lower": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		return funcStringTransform(args, strings.ToLower)
+	}},
+
+	"upper": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		return funcStringTransform(args, strings.ToUpper)
+	}},
+
+	"trim": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		return funcStringTransform(args, strings.TrimSpace)
+	}},
+
+	// 当前时间
+	"now": {0, 0, func(args []*EvalResult) (*EvalResult, error) {
+		return &EvalResult{Type: Datetime, Value: time.Now()}, nil
+	}},
+
+	// 将字符串解析为日期时间
+	"datetime": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		t, err := funcArgTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &EvalResult{Type: Datetime, Value: t}, nil
+	}},
+
+	"addDays": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		return funcTimeAdd(args, 24*time.Hour)
+	}},
+
+	"addHours": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		return funcTimeAdd(args, time.Hour)
+	}},
+
+	// 距离指定时间的天数（向下取整，已过去时为负数）
+	"daysUntil": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		return funcTimeUntil(args, 24*time.Hour)
+	}},
+
+	// 距离指定时间的小时数（向下取整，已过去时为负数）
+	"hoursUntil": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		return funcTimeUntil(args, time.Hour)
+	}},
+}
+
+func lookupFunction(name string, argc int) (exprFunction, error) {
+	fn, ok := exprFunctions[name]
+	if !ok {
+		return exprFunction{}, fmt.Errorf("unknown function: %s", name)
+	}
+
+	if argc < fn.minArgs || argc > fn.maxArgs {
+		if fn.minArgs == fn.maxArgs {
+			return exprFunction{}, fmt.Errorf("function %s expects %d argument(s), got %d", name, fn.minArgs, argc)
+		}
+		return exprFunction{}, fmt.Errorf("function %s expects %d to %d arguments, got %d", name, fn.minArgs, fn.maxArgs, argc)
+	}
+
+	return fn, nil
+}
+
+func funcArgString(arg *EvalResult) (string, error) {
+	rs, err := arg.coerce(String)
+	if err != nil {
+		return "", err
+	}
+	return rs.GetString()
+}
+
+func funcArgNumber(arg *EvalResult) (float64, error) {
+	rs, err := arg.coerce(Number)
+	if err != nil {
+		return 0, err
+	}
+	return rs.GetFloat64()
+}
+
+func funcArgTime(arg *EvalResult) (time.Time, error) {
+	rs, err := arg.coerce(Datetime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return rs.GetTime()
+}
+
+func funcStringPredicate(args []*EvalResult, predicate func(s, t string) bool) (*EvalResult, error) {
+	s, err := funcArgString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := funcArgString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{Type: Boolean, Value: predicate(s, t)}, nil
+}
+
+func funcStringTransform(args []*EvalResult, transform func(s string) string) (*EvalResult, error) {
+	s, err := funcArgString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{Type: String, Value: transform(s)}, nil
+}
+
+func funcTimeAdd(args []*EvalResult, unit time.Duration) (*EvalResult, error) {
+	t, err := funcArgTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := funcArgNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{Type: Datetime, Value: t.Add(time.Duration(n * float64(unit)))}, nil
+}
+
+func funcTimeUntil(args []*EvalResult, unit time.Duration) (*EvalResult, error) {
+	t, err := funcArgTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{Type: Number, Value: math.Floor(float64(time.Until(t)) / float64(unit))}, nil
+}
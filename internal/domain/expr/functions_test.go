@@ -0,0 +1,71 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestExprFunctions(t *testing.T) {
+	variables := map[string]map[string]any{
+		"node1": {
+			"certificate.domains":  "example.com;www.example.com",
+			"certificate.notAfter": "2000-01-01 00:00:00",
+			"node.name":            " Deploy-Prod ",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "len of string", text: `len("héllo") == 5`, want: true},
+		{name: "len of list", text: `len(["a", "b"]) == 2`, want: true},
+		{name: "len of split", text: `len(split(${node1.certificate.domains})) == 2`, want: true},
+
+		{name: "contains in list", text: `contains(["a", "b"], "b")`, want: true},
+		{name: "contains element", text: `contains(${node1.certificate.domains}, "www.example.com")`, want: true},
+		{name: "contains is not substring", text: `contains(${node1.certificate.domains}, "example")`, want: false},
+		{name: "contains in single element string", text: `contains("www.example.com", "example.com")`, want: false},
+		{name: "contains number", text: `contains([1, 2], 2)`, want: true},
+
+		{name: "hasPrefix", text: `hasPrefix("www.example.com", "www.")`, want: true},
+		{name: "hasSuffix", text: `hasSuffix("www.example.com", ".org")`, want: false},
+		{name: "matches", text: `matches("www.example.com", "^[a-z]+\\.example\\.com$")`, want: true},
+		{name: "matches invalid pattern", text: `matches("a", "(")`, wantErr: true},
+
+		{name: "split with separator", text: `split("a,b,c", ",") == ["a", "b", "c"]`, want: true},
+		{name: "split empty", text: `len(split("")) == 0`, want: true},
+		{name: "replace", text: `replace("a-b-c", "-", ".") == "a.b.c"`, want: true},
+		{name: "lower", text: `lower("ABC") == "abc"`, want: true},
+		{name: "upper", text: `upper("abc") == "ABC"`, want: true},
+		{name: "trim", text: `lower(trim(${node1.node.name})) == "deploy-prod"`, want: true},
+
+		{name: "datetime", text: `datetime("2000-01-02") > datetime("2000-01-01")`, want: true},
+		{name: "addDays", text: `addDays(datetime("2000-01-01"), 1) == datetime("2000-01-02")`, want: true},
+		{name: "addHours", text: `addHours(datetime("2000-01-01"), 24) == datetime("2000-01-02")`, want: true},
+		{name: "now", text: `now() > datetime("2000-01-01")`, want: true},
+		{name: "daysUntil future", text: `daysUntil(addDays(now(), 3)) == 2`, want: true},
+		{name: "daysUntil past", text: `daysUntil(${node1.certificate.notAfter}) < 0`, want: true},
+		{name: "hoursUntil", text: `hoursUntil(addHours(now(), 5)) == 4`, want: true},
+		{name: "invalid datetime", text: `datetime("not a time") > now()`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseExpr(tt.text)
+			if err != nil {
+				t.Errorf("ParseExpr() error = %v", err)
+				return
+			}
+
+			got, err := EvalBool(e, variables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvalBool() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("EvalBool() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
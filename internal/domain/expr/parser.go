@@ -0,0 +1,480 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 将文本形式的表达式解析为表达式树。
+//
+// 语法示例：
+//
+//	${nodeId.certificate.daysLeft:number} <= 30 && !${nodeId.certificate.validity:boolean}
+//	"example.com" in ${nodeId.certificate.domains:list} or hasSuffix(${nodeId.node.name}, "-prod")
+//	${nodeId.certificate.notAfter:datetime} < addDays(now(), 7)
+//
+// 其中 `${nodeId.name:type}` 表示引用节点作用域变量，`${.name:type}` 表示引用全局变量，类型可省略（省略时将在运算时根据另一侧推断）；
+// 支持的运算符按优先级从低到高依次为：
+// `or`/`||`、`and`/`&&`、`not`/`!`、比较运算符（`==`、`!=`、`>`、`>=`、`<`、`<=`、`in`、`not in`）、`+`/`-`、`*`/`/`/`%`。
+func ParseExpr(text string) (Expr, error) {
+	tokens, err := tokenizeExpr(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("syntax error at position %d: unexpected '%s'", tok.pos, tok.text)
+	}
+
+	return e, nil
+}
+
+type exprTokenKind int
+
+const (
+	tokenEOF exprTokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenVariable
+	tokenPunct
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+func tokenizeExpr(text string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+
+		case r == '"' || r == '\'':
+			start := i
+			sb := strings.Builder{}
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("syntax error at position %d: unterminated string", start)
+				}
+				if runes[i] == r {
+					i++
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, text: sb.String(), pos: start})
+
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '{':
+			start := i
+			end := i + 2
+			for end < len(runes) && runes[end] != '}' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("syntax error at position %d: unterminated variable reference", start)
+			}
+			tokens = append(tokens, exprToken{kind: tokenVariable, text: string(runes[i+2 : end]), pos: start})
+			i = end + 1
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+
+		default:
+			start := i
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					tokens = append(tokens, exprToken{kind: tokenPunct, text: two, pos: start})
+					i += 2
+					continue
+				}
+			}
+
+			if !strings.ContainsRune("()[],+-*/%<>!", r) {
+				return nil, fmt.Errorf("syntax error at position %d: unexpected character '%c'", start, r)
+			}
+			tokens = append(tokens, exprToken{kind: tokenPunct, text: string(r), pos: start})
+			i++
+		}
+	}
+
+	tokens = append(tokens, exprToken{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) peekAt(offset int) exprToken {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// 判断当前记号是否为指定的运算符或关键字（关键字不区分大小写）。
+func (p *exprParser) match(texts ...string) (string, bool) {
+	tok := p.peek()
+	for _, text := range texts {
+		if tok.kind == tokenPunct && tok.text == text {
+			return text, true
+		}
+		if tok.kind == tokenIdent && strings.EqualFold(tok.text, text) {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.match(text); !ok {
+		tok := p.peek()
+		if tok.kind == tokenEOF {
+			return fmt.Errorf("syntax error at position %d: expected '%s', got end of expression", tok.pos, text)
+		}
+		return fmt.Errorf("syntax error at position %d: expected '%s', got '%s'", tok.pos, text, tok.text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.match("||", "or"); !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Type: LogicalExprType, Operator: Or, Left: left, Right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.match("&&", "and"); !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Type: LogicalExprType, Operator: And, Left: left, Right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (Expr, error) {
+	if _, ok := p.match("!", "not"); ok {
+		p.next()
+
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return NotExpr{Type: NotExprType, Expr: inner}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	var operator ExprComparisonOperator
+	if op, ok := p.match("==", "!=", ">", ">=", "<", "<=", "in"); ok {
+		p.next()
+		operator = map[string]ExprComparisonOperator{
+			"==": Equal,
+			"!=": NotEqual,
+			">":  GreaterThan,
+			">=": GreaterOrEqual,
+			"<":  LessThan,
+			"<=": LessOrEqual,
+			"in": In,
+		}[op]
+	} else if _, ok := p.match("not"); ok && strings.EqualFold(p.peekAt(1).text, "in") && p.peekAt(1).kind == tokenIdent {
+		p.next()
+		p.next()
+		operator = NotIn
+	} else {
+		return left, nil
+	}
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComparisonExpr{Type: ComparisonExprType, Operator: operator, Left: left, Right: right}, nil
+}
+
+func (p *exprParser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.match("+", "-")
+		if !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		operator := Add
+		if op == "-" {
+			operator = Subtract
+		}
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.match("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		operator := map[string]ExprArithmeticOperator{"*": Multiply, "/": Divide, "%": Modulo}[op]
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (Expr, error) {
+	if _, ok := p.match("-"); ok {
+		p.next()
+
+		if tok := p.peek(); tok.kind == tokenNumber {
+			p.next()
+			return p.newNumberConstant("-"+tok.text, tok.pos)
+		}
+
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return ArithmeticExpr{
+			Type:     ArithmeticExprType,
+			Operator: Subtract,
+			Left:     ConstantExpr{Type: ConstantExprType, Value: "0", ValueType: Number},
+			Right:    inner,
+		}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNumber:
+		return p.newNumberConstant(tok.text, tok.pos)
+
+	case tokenString:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: String}, nil
+
+	case tokenVariable:
+		return p.newVariant(tok)
+
+	case tokenIdent:
+		switch strings.ToLower(tok.text) {
+		case "true", "false":
+			return ConstantExpr{Type: ConstantExprType, Value: strings.ToLower(tok.text), ValueType: Boolean}, nil
+		}
+
+		if _, ok := p.match("("); !ok {
+			return nil, fmt.Errorf("syntax error at position %d: unexpected identifier '%s'", tok.pos, tok.text)
+		}
+		p.next()
+
+		args, err := p.parseExprList(")")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := lookupFunction(tok.text, len(args)); err != nil {
+			return nil, fmt.Errorf("syntax error at position %d: %w", tok.pos, err)
+		}
+		return FunctionExpr{Type: FunctionExprType, Name: tok.text, Args: args}, nil
+
+	case tokenPunct:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+
+		case "[":
+			items, err := p.parseExprList("]")
+			if err != nil {
+				return nil, err
+			}
+			return ListExpr{Type: ListExprType, Items: items}, nil
+		}
+
+		return nil, fmt.Errorf("syntax error at position %d: unexpected '%s'", tok.pos, tok.text)
+
+	default:
+		return nil, fmt.Errorf("syntax error at position %d: unexpected end of expression", tok.pos)
+	}
+}
+
+// 解析以逗号分隔的表达式列表，直至遇到结束符。
+func (p *exprParser) parseExprList(closing string) ([]Expr, error) {
+	exprs := make([]Expr, 0)
+
+	if _, ok := p.match(closing); ok {
+		p.next()
+		return exprs, nil
+	}
+
+	for {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+
+		if _, ok := p.match(","); ok {
+			p.next()
+			continue
+		}
+
+		if err := p.expect(closing); err != nil {
+			return nil, err
+		}
+		return exprs, nil
+	}
+}
+
+func (p *exprParser) newNumberConstant(text string, pos int) (Expr, error) {
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return nil, fmt.Errorf("syntax error at position %d: invalid number '%s'", pos, text)
+	}
+
+	return ConstantExpr{Type: ConstantExprType, Value: text, ValueType: Number}, nil
+}
+
+// 解析变量引用，格式为 `nodeId.name` 或 `nodeId.name:type`。
+func (p *exprParser) newVariant(tok exprToken) (Expr, error) {
+	ref, typ, _ := strings.Cut(tok.text, ":")
+	id, name, _ := strings.Cut(strings.TrimSpace(ref), ".")
+	id = strings.TrimSpace(id)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("syntax error at position %d: invalid variable reference '%s', expected '${nodeId.name}' or '${.name}'", tok.pos, tok.text)
+	}
+
+	valueType := ExprValueType(strings.TrimSpace(typ))
+	switch valueType {
+	case "", Number, String, Boolean, Datetime, List:
+	default:
+		return nil, fmt.Errorf("syntax error at position %d: unknown value type '%s'", tok.pos, valueType)
+	}
+
+	return VariantExpr{
+		Type: VariantExprType,
+		Selector: ExprValueSelector{
+			Id:   id,
+			Name: name,
+			Type: valueType,
+		},
+	}, nil
+}
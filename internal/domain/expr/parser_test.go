@@ -0,0 +1,128 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "comparison", text: `${node1.certificate.daysLeft:number} <= 30`},
+		{name: "logical", text: `${node1.a:boolean} && !${node1.b:boolean} || ${.c:boolean}`},
+		{name: "keywords", text: `not ${node1.a:boolean} and ${node1.b:boolean} or ${node1.c:boolean}`},
+		{name: "in", text: `"example.com" in ${node1.certificate.domains:list}`},
+		{name: "not in", text: `"example.com" not in ["a.com", "b.com"]`},
+		{name: "function", text: `hasSuffix(${node1.node.name}, "-prod")`},
+		{name: "nested function", text: `${node1.certificate.notAfter:datetime} < addDays(now(), 7)`},
+		{name: "arithmetic", text: `(1 + 2) * 3 - -4 % 2 == 9`},
+		{name: "single quotes", text: `'a' == "a"`},
+		{name: "unknown function", text: `foo(1)`, wantErr: true},
+		{name: "wrong arity", text: `len(1, 2)`, wantErr: true},
+		{name: "bare identifier", text: `foo`, wantErr: true},
+		{name: "unknown value type", text: `${node1.a:object} == 1`, wantErr: true},
+		{name: "invalid variable reference", text: `${node1} == 1`, wantErr: true},
+		{name: "invalid number", text: `1.2.3 == 1`, wantErr: true},
+		{name: "unclosed parenthesis", text: `(1 + 2`, wantErr: true},
+		{name: "unclosed string", text: `"abc == 1`, wantErr: true},
+		{name: "trailing tokens", text: `1 == 1 1`, wantErr: true},
+		{name: "empty", text: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpr(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseExpr_Eval(t *testing.T) {
+	variables := map[string]map[string]any{
+		"": {
+			"env": "prod",
+		},
+		"node1": {
+			"certificate.daysLeft": 9,
+			"certificate.domains":  "example.com;www.example.com",
+			"certificate.validity": true,
+			"node.name":            "deploy-prod",
+			"text.small":           "9",
+			"text.large":           "10",
+			"text.padded":          "09",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    bool
+		wantErr bool
+	}{
+		// 比较
+		{name: "number lte", text: `${node1.certificate.daysLeft:number} <= 30`, want: true},
+		{name: "number gt", text: `${node1.certificate.daysLeft} > 10`, want: false},
+		{name: "numeric strings", text: `${node1.text.small} < ${node1.text.large}`, want: true},
+		{name: "typed string constants", text: `"9" > "10"`, want: true},
+		{name: "typed string variables", text: `${node1.text.small:string} > ${node1.text.large:string}`, want: true},
+		{name: "string equality", text: `"0123" == "123"`, want: false},
+		{name: "string equality with exponent", text: `"1e3" == "1000"`, want: false},
+		{name: "untyped string equality", text: `${node1.text.padded} == ${node1.text.small}`, want: false},
+		{name: "non-numeric strings", text: `"b" > "a"`, want: true},
+		{name: "boolean", text: `${node1.certificate.validity:boolean} == true`, want: true},
+		{name: "global variable", text: `${.env} == "prod"`, want: true},
+		{name: "type mismatch", text: `${node1.certificate.validity:boolean} > 1`, wantErr: true},
+		{name: "missing variable", text: `${node1.missing} == 1`, wantErr: true},
+
+		// 逻辑
+		{name: "and", text: `true && false`, want: false},
+		{name: "or", text: `true || false`, want: true},
+		{name: "not", text: `!false`, want: true},
+		{name: "precedence", text: `true || false && false`, want: true},
+
+		// 算术
+		{name: "add", text: `1 + 2 == 3`, want: true},
+		{name: "precedence of arithmetic", text: `1 + 2 * 3 == 7`, want: true},
+		{name: "parentheses", text: `(1 + 2) * 3 == 9`, want: true},
+		{name: "negative", text: `-3 + 1 == -2`, want: true},
+		{name: "modulo", text: `7 % 3 == 1`, want: true},
+		{name: "divide", text: `7 / 2 == 3.5`, want: true},
+		{name: "concat", text: `"a" + "b" == "ab"`, want: true},
+		{name: "variable arithmetic", text: `${node1.certificate.daysLeft} + 1 == 10`, want: true},
+		{name: "division by zero", text: `1 / 0 == 1`, wantErr: true},
+		{name: "modulo by zero", text: `1 % 0 == 1`, wantErr: true},
+
+		// 列表
+		{name: "in list", text: `"b" in ["a", "b"]`, want: true},
+		{name: "not in list", text: `"c" not in ["a", "b"]`, want: true},
+		{name: "in semicolon separated string", text: `"www.example.com" in ${node1.certificate.domains:list}`, want: true},
+		{name: "in semicolon separated string by element", text: `"example" in ${node1.certificate.domains:list}`, want: false},
+		{name: "number in list", text: `2 in [1, 2, 3]`, want: true},
+		{name: "type mismatch in list", text: `2 in ["a", "b"]`, want: false},
+		{name: "type mismatch not in list", text: `true not in [1, 2]`, want: true},
+		{name: "in non-list", text: `2 in true`, want: false},
+		{name: "list equal", text: `["a", "b"] == ["a", "b"]`, want: true},
+		{name: "list not equal", text: `["a", "b"] == ["a"]`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseExpr(tt.text)
+			if err != nil {
+				t.Errorf("ParseExpr() error = %v", err)
+				return
+			}
+
+			got, err := EvalBool(e, variables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvalBool() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("EvalBool() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+const CollectionNameInstance = "instance"
+
+// 多实例部署时的服务实例，各实例定期上报心跳。
+type Instance struct {
+	Meta
+	Hostname    string    `json:"hostname" db:"hostname"`
+	HeartbeatAt time.Time `json:"heartbeatAt" db:"heartbeatAt"`
+}
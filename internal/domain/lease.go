@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+const CollectionNameLease = "lease"
+
+// 多实例部署时的分布式租约，同一时刻仅有一个实例可持有同名租约。
+type Lease struct {
+	Meta
+	Name      string    `json:"name" db:"name"`
+	Holder    string    `json:"holder" db:"holder"`
+	ExpiresAt time.Time `json:"expiresAt" db:"expiresAt"`
+}
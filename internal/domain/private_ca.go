@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+const CollectionNamePrivateCA = "private_ca"
+
+type PrivateCA struct {
+	Meta
+	Name              string                      `json:"name" db:"name"`
+	ParentId          string                      `json:"parentId" db:"parentRef"` // 上级 CA 记录 ID（零值时表示根 CA 或外部签发的中间 CA）
+	CommonName        string                      `json:"commonName" db:"commonName"`
+	SerialNumber      string                      `json:"serialNumber" db:"serialNumber"`
+	Certificate       string                      `json:"certificate" db:"certificate"`
+	PrivateKey        string                      `json:"-" db:"privateKey"` // 私钥明文，仅在仓储层加密后落库
+	KeyAlgorithm      CertificateKeyAlgorithmType `json:"keyAlgorithm" db:"keyAlgorithm"`
+	ValidityNotBefore time.Time                   `json:"validityNotBefore" db:"validityNotBefore"`
+	ValidityNotAfter  time.Time                   `json:"validityNotAfter" db:"validityNotAfter"`
+}
@@ -70,11 +70,14 @@ const (
 	AccessProviderTypeNS1                 = AccessProviderType("ns1")
 	AccessProviderTypePorkbun             = AccessProviderType("porkbun")
 	AccessProviderTypePowerDNS            = AccessProviderType("powerdns")
+	AccessProviderTypePrivateCA           = AccessProviderType("privateca")
 	AccessProviderTypeProxmoxVE           = AccessProviderType("proxmoxve")
 	AccessProviderTypeQiniu               = AccessProviderType("qiniu")
 	AccessProviderTypeQingCloud           = AccessProviderType("qingcloud") // 青云（预留）
 	AccessProviderTypeRainYun             = AccessProviderType("rainyun")
 	AccessProviderTypeRatPanel            = AccessProviderType("ratpanel")
+	AccessProviderTypeRFC2136             = AccessProviderType("rfc2136")
+	AccessProviderTypeS3                  = AccessProviderType("s3")
 	AccessProviderTypeSafeLine            = AccessProviderType("safeline")
 	AccessProviderTypeSectigo             = AccessProviderType("sectigo")
 	AccessProviderTypeSlackBot            = AccessProviderType("slackbot")
@@ -112,6 +115,7 @@ const (
 	CAProviderTypeGoogleTrustServices = CAProviderType(AccessProviderTypeGoogleTrustServices)
 	CAProviderTypeLetsEncrypt         = CAProviderType(AccessProviderTypeLetsEncrypt)
 	CAProviderTypeLetsEncryptStaging  = CAProviderType(AccessProviderTypeLetsEncryptStaging)
+	CAProviderTypePrivateCA           = CAProviderType(AccessProviderTypePrivateCA)
 	CAProviderTypeSectigo             = CAProviderType(AccessProviderTypeSectigo)
 	CAProviderTypeSSLCom              = CAProviderType(AccessProviderTypeSSLCOM)
 	CAProviderTypeZeroSSL             = CAProviderType(AccessProviderTypeZeroSSL)
@@ -168,6 +172,7 @@ const (
 	ACMEDns01ProviderTypePorkbun           = ACMEDns01ProviderType(AccessProviderTypePorkbun)
 	ACMEDns01ProviderTypePowerDNS          = ACMEDns01ProviderType(AccessProviderTypePowerDNS)
 	ACMEDns01ProviderTypeRainYun           = ACMEDns01ProviderType(AccessProviderTypeRainYun)
+	ACMEDns01ProviderTypeRFC2136           = ACMEDns01ProviderType(AccessProviderTypeRFC2136)
 	ACMEDns01ProviderTypeSpaceship         = ACMEDns01ProviderType(AccessProviderTypeSpaceship)
 	ACMEDns01ProviderTypeTencentCloud      = ACMEDns01ProviderType(AccessProviderTypeTencentCloud) // 兼容旧值，等同于 [ACMEDns01ProviderTypeTencentCloudDNS]
 	ACMEDns01ProviderTypeTencentCloudDNS   = ACMEDns01ProviderType(AccessProviderTypeTencentCloud + "-dns")
@@ -190,8 +195,28 @@ ACME HTTP-01 提供商常量值。
 NOTICE: If you add new constant, please keep ASCII order.
 */
 const (
-	ACMEHttp01ProviderTypeLocal = ACMEHttp01ProviderType(AccessProviderTypeLocal)
-	ACMEHttp01ProviderTypeSSH   = ACMEHttp01ProviderType(AccessProviderTypeSSH)
+	ACMEHttp01ProviderTypeAliyunOSS       = ACMEHttp01ProviderType(AccessProviderTypeAliyun + "-oss")
+	ACMEHttp01ProviderTypeAWSS3           = ACMEHttp01ProviderType(AccessProviderTypeAWS + "-s3")
+	ACMEHttp01ProviderTypeLocal           = ACMEHttp01ProviderType(AccessProviderTypeLocal)
+	ACMEHttp01ProviderTypeLocalStandalone = ACMEHttp01ProviderType(AccessProviderTypeLocal + "-standalone")
+	ACMEHttp01ProviderTypeQiniuKodo       = ACMEHttp01ProviderType(AccessProviderTypeQiniu + "-kodo")
+	ACMEHttp01ProviderTypeS3              = ACMEHttp01ProviderType(AccessProviderTypeS3)
+	ACMEHttp01ProviderTypeSSH             = ACMEHttp01ProviderType(AccessProviderTypeSSH)
+	ACMEHttp01ProviderTypeTencentCloudCOS = ACMEHttp01ProviderType(AccessProviderTypeTencentCloud + "-cos")
+)
+
+type ACMETlsAlpn01ProviderType string
+
+/*
+ACME TLS-ALPN-01 提供商常量值。
+短横线前的部分始终等于授权提供商类型。
+
+注意：如果追加新的常量值，请保持以 ASCII 排序。
+NOTICE: If you add new constant, please keep ASCII order.
+*/
+const (
+	ACMETlsAlpn01ProviderTypeLocal = ACMETlsAlpn01ProviderType(AccessProviderTypeLocal)
+	ACMETlsAlpn01ProviderTypeSSH   = ACMETlsAlpn01ProviderType(AccessProviderTypeSSH)
 )
 
 type DeploymentProviderType string
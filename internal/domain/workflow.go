@@ -3,6 +3,7 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,18 +17,33 @@ const CollectionNameWorkflow = "workflow"
 
 type Workflow struct {
 	Meta
-	Name          string                `json:"name" db:"name"`
-	Description   string                `json:"description" db:"description"`
-	Trigger       WorkflowTriggerType   `json:"trigger" db:"trigger"`
-	TriggerCron   string                `json:"triggerCron" db:"triggerCron"`
-	Enabled       bool                  `json:"enabled" db:"enabled"`
-	GraphDraft    *WorkflowGraph        `json:"graphDraft" db:"graphDraft"`
-	GraphContent  *WorkflowGraph        `json:"graphContent" db:"graphContent"`
-	HasDraft      bool                  `json:"hasDraft" db:"hasDraft"`
-	HasContent    bool                  `json:"hasContent" db:"hasContent"`
-	LastRunId     string                `json:"lastRunId" db:"lastRunRef"`
-	LastRunStatus WorkflowRunStatusType `json:"lastRunStatus" db:"lastRunStatus"`
-	LastRunTime   time.Time             `json:"lastRunTime" db:"lastRunTime"`
+	Name              string                `json:"name" db:"name"`
+	Description       string                `json:"description" db:"description"`
+	Trigger           WorkflowTriggerType   `json:"trigger" db:"trigger"`
+	TriggerCron       string                `json:"triggerCron" db:"triggerCron"`
+	TriggerEvent      *WorkflowTriggerEvent `json:"triggerEvent" db:"triggerEvent"`
+	WebhookKey        string                `json:"webhookKey" db:"webhookKey"`
+	WebhookSecret     string                `json:"-" db:"webhookSecret"`                     // Webhook 签名密钥（仅在生成时返回）
+	Priority          int32                 `json:"priority" db:"priority"`                   // 运行优先级，值越大越优先派发
+	MaxConcurrentRuns int32                 `json:"maxConcurrentRuns" db:"maxConcurrentRuns"` // 最大并发运行数，暂停等待中的运行不计入（零值时默认值 1）
+	Enabled           bool                  `json:"enabled" db:"enabled"`
+	GraphDraft        *WorkflowGraph        `json:"graphDraft" db:"graphDraft"`
+	GraphContent      *WorkflowGraph        `json:"graphContent" db:"graphContent"`
+	HasDraft          bool                  `json:"hasDraft" db:"hasDraft"`
+	HasContent        bool                  `json:"hasContent" db:"hasContent"`
+	LastRunId         string                `json:"lastRunId" db:"lastRunRef"`
+	LastRunStatus     WorkflowRunStatusType `json:"lastRunStatus" db:"lastRunStatus"`
+	LastRunTime       time.Time             `json:"lastRunTime" db:"lastRunTime"`
+	GitOpsRef         string                `json:"gitopsRef" db:"gitopsRef"`           // GitOps 清单中的引用名（零值时表示不受 GitOps 管理）
+	GitOpsChecksum    string                `json:"gitopsChecksum" db:"gitopsChecksum"` // 最近一次同步时的清单校验和
+	GitOpsDrifted     bool                  `json:"gitopsDrifted" db:"gitopsDrifted"`   // 是否已偏离 GitOps 清单
+}
+
+func (w *Workflow) GetMaxConcurrentRuns() int {
+	if w.MaxConcurrentRuns <= 0 {
+		return 1
+	}
+	return int(w.MaxConcurrentRuns)
 }
 
 type WorkflowGraph struct {
@@ -77,8 +93,72 @@ type WorkflowTriggerType string
 const (
 	WorkflowTriggerTypeScheduled = WorkflowTriggerType("scheduled")
 	WorkflowTriggerTypeManual    = WorkflowTriggerType("manual")
+	WorkflowTriggerTypeWebhook   = WorkflowTriggerType("webhook")
+	WorkflowTriggerTypeEvent     = WorkflowTriggerType("event")
 )
 
+type WorkflowTriggerEvent struct {
+	Type     EventType         `json:"type"`               // 订阅的事件类型
+	Filters  map[string]string `json:"filters,omitempty"`  // 事件负载过滤条件，键为负载字段名，值为期望值（全部满足时才触发；均为数值时按数值比较）
+	DaysLeft int32             `json:"daysLeft,omitempty"` // 证书剩余有效天数阈值，仅适用于证书即将过期事件，剩余天数不超过该值时触发（零值时默认值 20）
+}
+
+func (t *WorkflowTriggerEvent) GetDaysLeft() int {
+	if t.DaysLeft <= 0 {
+		return 20
+	}
+	return int(t.DaysLeft)
+}
+
+func (t *WorkflowTriggerEvent) Match(event *Event) bool {
+	if t == nil || event == nil || t.Type != event.Type {
+		return false
+	}
+
+	if event.Type == EventTypeCertificateExpiring {
+		daysLeft, ok := parseEventPayloadNumber(event.Payload["daysLeft"])
+		if !ok || daysLeft > float64(t.GetDaysLeft()) {
+			return false
+		}
+	}
+
+	for key, expected := range t.Filters {
+		actual, ok := event.Payload[key]
+		if !ok {
+			return false
+		}
+
+		actualNumber, ok1 := parseEventPayloadNumber(actual)
+		expectedNumber, ok2 := parseEventPayloadNumber(expected)
+		if ok1 && ok2 {
+			if actualNumber != expectedNumber {
+				return false
+			}
+		} else if fmt.Sprintf("%v", actual) != expected {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseEventPayloadNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 type WorkflowNode struct {
 	Id     string           `json:"id"` // 节点 ID 只在该工作流中唯一，在全局中不保证唯一性
 	Type   WorkflowNodeType `json:"type"`
@@ -89,25 +169,43 @@ type WorkflowNode struct {
 type WorkflowNodeType string
 
 const (
-	WorkflowNodeTypeStart       = WorkflowNodeType("start")
-	WorkflowNodeTypeEnd         = WorkflowNodeType("end")
-	WorkflowNodeTypeCondition   = WorkflowNodeType("condition")
-	WorkflowNodeTypeBranchBlock = WorkflowNodeType("branchBlock")
-	WorkflowNodeTypeTryCatch    = WorkflowNodeType("tryCatch")
-	WorkflowNodeTypeTryBlock    = WorkflowNodeType("tryBlock")
-	WorkflowNodeTypeCatchBlock  = WorkflowNodeType("catchBlock")
-	WorkflowNodeTypeDelay       = WorkflowNodeType("delay")
-	WorkflowNodeTypeBizApply    = WorkflowNodeType("bizApply")
-	WorkflowNodeTypeBizUpload   = WorkflowNodeType("bizUpload")
-	WorkflowNodeTypeBizMonitor  = WorkflowNodeType("bizMonitor")
-	WorkflowNodeTypeBizDeploy   = WorkflowNodeType("bizDeploy")
-	WorkflowNodeTypeBizNotify   = WorkflowNodeType("bizNotify")
+	WorkflowNodeTypeStart         = WorkflowNodeType("start")
+	WorkflowNodeTypeEnd           = WorkflowNodeType("end")
+	WorkflowNodeTypeCondition     = WorkflowNodeType("condition")
+	WorkflowNodeTypeBranchBlock   = WorkflowNodeType("branchBlock")
+	WorkflowNodeTypeTryCatch      = WorkflowNodeType("tryCatch")
+	WorkflowNodeTypeTryBlock      = WorkflowNodeType("tryBlock")
+	WorkflowNodeTypeCatchBlock    = WorkflowNodeType("catchBlock")
+	WorkflowNodeTypeParallel      = WorkflowNodeType("parallel")
+	WorkflowNodeTypeParallelBlock = WorkflowNodeType("parallelBlock")
+	WorkflowNodeTypeDelay         = WorkflowNodeType("delay")
+	WorkflowNodeTypeSubWorkflow   = WorkflowNodeType("subWorkflow")
+	WorkflowNodeTypeSetVariables  = WorkflowNodeType("setVariables")
+	WorkflowNodeTypeScript        = WorkflowNodeType("script")
+	WorkflowNodeTypeHttpRequest   = WorkflowNodeType("httpRequest")
+	WorkflowNodeTypeApproval      = WorkflowNodeType("approval")
+	WorkflowNodeTypeBizApply      = WorkflowNodeType("bizApply")
+	WorkflowNodeTypeBizUpload     = WorkflowNodeType("bizUpload")
+	WorkflowNodeTypeBizMonitor    = WorkflowNodeType("bizMonitor")
+	WorkflowNodeTypeBizDeploy     = WorkflowNodeType("bizDeploy")
+	WorkflowNodeTypeBizNotify     = WorkflowNodeType("bizNotify")
 )
 
 type WorkflowNodeData struct {
-	Name     string             `json:"name"`
-	Disabled bool               `json:"disabled,omitempty,omitzero"`
-	Config   WorkflowNodeConfig `json:"config,omitempty,omitzero"`
+	Name     string                   `json:"name"`
+	Disabled bool                     `json:"disabled,omitempty,omitzero"`
+	Timeout  int32                    `json:"timeout,omitempty,omitzero"` // 执行超时时间，单位：秒（零值时不限制）
+	Retry    *WorkflowNodeRetryPolicy `json:"retry,omitempty,omitzero"`
+	Config   WorkflowNodeConfig       `json:"config,omitempty,omitzero"`
+}
+
+type WorkflowNodeRetryPolicy struct {
+	MaxAttempts     int32    `json:"maxAttempts"`               // 最大尝试次数（含首次执行）
+	InitialInterval int32    `json:"initialInterval,omitempty"` // 首次重试前的等待时间，单位：秒
+	MaxInterval     int32    `json:"maxInterval,omitempty"`     // 重试等待时间上限，单位：秒（零值时不限制）
+	Multiplier      float64  `json:"multiplier,omitempty"`      // 指数退避系数（零值时默认值 2）
+	Jitter          float64  `json:"jitter,omitempty"`          // 随机抖动比例，取值范围 [0, 1]
+	RetryableErrors []string `json:"retryableErrors,omitempty"` // 可重试的错误信息正则表达式列表（零值时所有错误均可重试）
 }
 
 type WorkflowNodeConfig map[string]any
@@ -135,6 +233,82 @@ func (c WorkflowNodeConfig) AsBranchBlock() WorkflowNodeConfigForBranchBlock {
 	}
 }
 
+func (c WorkflowNodeConfig) AsParallel() WorkflowNodeConfigForParallel {
+	return WorkflowNodeConfigForParallel{
+		MaxConcurrency: xmaps.GetInt32(c, "maxConcurrency"),
+		FailFast:       xmaps.GetBool(c, "failFast"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsSubWorkflow() WorkflowNodeConfigForSubWorkflow {
+	variables := make(map[string]string)
+	for k, v := range xmaps.GetKVMapAny(c, "variables") {
+		variables[k] = fmt.Sprintf("%v", v)
+	}
+
+	return WorkflowNodeConfigForSubWorkflow{
+		WorkflowId:              xmaps.GetString(c, "workflowId"),
+		CertificateOutputNodeId: xmaps.GetString(c, "certificateOutputNodeId"),
+		Variables:               variables,
+		InheritVariables:        xmaps.GetBool(c, "inheritVariables"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsSetVariables() WorkflowNodeConfigForSetVariables {
+	assignments := make([]WorkflowNodeSetVariablesAssignment, 0)
+	if raw, ok := c["assignments"]; ok && raw != nil {
+		rawJson, _ := json.Marshal(raw)
+		json.Unmarshal(rawJson, &assignments)
+	}
+
+	return WorkflowNodeConfigForSetVariables{
+		Assignments: assignments,
+	}
+}
+
+func (c WorkflowNodeConfig) AsScript() WorkflowNodeConfigForScript {
+	return WorkflowNodeConfigForScript{
+		Script: xmaps.GetString(c, "script"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsHttpRequest() WorkflowNodeConfigForHttpRequest {
+	headers := make(map[string]string)
+	for k, v := range xmaps.GetKVMapAny(c, "headers") {
+		headers[k] = fmt.Sprintf("%v", v)
+	}
+
+	responseFields := make(map[string]string)
+	for k, v := range xmaps.GetKVMapAny(c, "responseFields") {
+		responseFields[k] = fmt.Sprintf("%v", v)
+	}
+
+	return WorkflowNodeConfigForHttpRequest{
+		Method:                   xmaps.GetOrDefaultString(c, "method", "GET"),
+		Url:                      xmaps.GetString(c, "url"),
+		Headers:                  headers,
+		Body:                     xmaps.GetString(c, "body"),
+		Timeout:                  xmaps.GetInt32(c, "timeout"),
+		AllowInsecureConnections: xmaps.GetBool(c, "allowInsecureConnections"),
+		CACertificate:            xmaps.GetString(c, "caCertificate"),
+		ClientCertificate:        xmaps.GetString(c, "clientCertificate"),
+		ClientPrivateKey:         xmaps.GetString(c, "clientPrivateKey"),
+		ResponseFields:           responseFields,
+		IgnoreErrorStatus:        xmaps.GetBool(c, "ignoreErrorStatus"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsApproval() WorkflowNodeConfigForApproval {
+	return WorkflowNodeConfigForApproval{
+		Provider:         xmaps.GetString(c, "provider"),
+		ProviderAccessId: xmaps.GetString(c, "providerAccessId"),
+		ProviderConfig:   xmaps.GetKVMapAny(c, "providerConfig"),
+		Subject:          xmaps.GetString(c, "subject"),
+		Message:          xmaps.GetString(c, "message"),
+		WaitTimeout:      xmaps.GetInt32(c, "waitTimeout"),
+	}
+}
+
 func (c WorkflowNodeConfig) AsBizApply() WorkflowNodeConfigForBizApply {
 	domains := lo.Filter(strings.Split(xmaps.GetString(c, "domains"), ";"), func(s string, _ int) bool { return s != "" })
 	nameservers := lo.Filter(strings.Split(xmaps.GetString(c, "nameservers"), ";"), func(s string, _ int) bool { return s != "" })
@@ -210,6 +384,57 @@ type WorkflowNodeConfigForBranchBlock struct {
 	Expression expr.Expr `json:"expression"` // 条件表达式
 }
 
+type WorkflowNodeConfigForParallel struct {
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"` // 最大并发分支数（零值时不限制）
+	FailFast       bool  `json:"failFast,omitempty"`       // 任一分支失败时是否立即取消其余分支
+}
+
+type WorkflowNodeConfigForSubWorkflow struct {
+	WorkflowId              string            `json:"workflowId"`                        // 子工作流 ID
+	CertificateOutputNodeId string            `json:"certificateOutputNodeId,omitempty"` // 传入子工作流的证书来源节点 ID
+	Variables               map[string]string `json:"variables,omitempty"`               // 传入子工作流的变量
+	InheritVariables        bool              `json:"inheritVariables,omitempty"`        // 是否将当前工作流的全局变量传入子工作流
+}
+
+type WorkflowNodeConfigForSetVariables struct {
+	Assignments []WorkflowNodeSetVariablesAssignment `json:"assignments"` // 变量赋值列表，按顺序求值
+}
+
+type WorkflowNodeSetVariablesAssignment struct {
+	Name           string            `json:"name"`                     // 变量名
+	Scoped         bool              `json:"scoped,omitempty"`         // 是否写入为当前节点的作用域变量（否则写入为全局变量）
+	Expression     json.RawMessage   `json:"expression"`               // 表达式，可以是文本形式或 JSON 形式
+	Mapping        map[string]string `json:"mapping,omitempty"`        // 映射表，以表达式的结果为键查找最终的值
+	MappingDefault *string           `json:"mappingDefault,omitempty"` // 映射表中不存在对应键时的默认值（零值时视为错误）
+}
+
+type WorkflowNodeConfigForScript struct {
+	Script string `json:"script"` // Starlark 脚本
+}
+
+type WorkflowNodeConfigForHttpRequest struct {
+	Method                   string            `json:"method"`                             // 请求谓词（零值时默认值 "GET"）
+	Url                      string            `json:"url"`                                // 请求地址，支持模板
+	Headers                  map[string]string `json:"headers,omitempty"`                  // 请求标头，值支持模板
+	Body                     string            `json:"body,omitempty"`                     // 请求内容，支持模板
+	Timeout                  int32             `json:"timeout,omitempty"`                  // 请求超时，单位：秒（零值时默认值 30）
+	AllowInsecureConnections bool              `json:"allowInsecureConnections,omitempty"` // 是否允许不安全的连接
+	CACertificate            string            `json:"caCertificate,omitempty"`            // 用于校验服务端证书的 CA 证书（PEM 格式）
+	ClientCertificate        string            `json:"clientCertificate,omitempty"`        // 双向 TLS 认证的客户端证书（PEM 格式）
+	ClientPrivateKey         string            `json:"clientPrivateKey,omitempty"`         // 双向 TLS 认证的客户端私钥（PEM 格式）
+	ResponseFields           map[string]string `json:"responseFields,omitempty"`           // 需要从 JSON 响应中提取的字段，键为变量名、值为以半角句点分隔的字段路径
+	IgnoreErrorStatus        bool              `json:"ignoreErrorStatus,omitempty"`        // 是否忽略非 2xx 的响应状态码（否则视为执行失败）
+}
+
+type WorkflowNodeConfigForApproval struct {
+	Provider         string         `json:"provider"`                   // 通知提供商
+	ProviderAccessId string         `json:"providerAccessId,omitempty"` // 通知提供商授权记录 ID
+	ProviderConfig   map[string]any `json:"providerConfig,omitempty"`   // 通知提供商额外配置
+	Subject          string         `json:"subject,omitempty"`          // 通知主题模板（零值时使用默认模板）
+	Message          string         `json:"message,omitempty"`          // 通知内容模板（零值时使用默认模板）
+	WaitTimeout      int32          `json:"waitTimeout,omitempty"`      // 等待审批的超时时间，单位：秒（零值时不限制），超时后视为拒绝
+}
+
 type WorkflowNodeConfigForBizApply struct {
 	Domains               []string       `json:"domains"`                         // 域名列表，以半角分号分隔
 	ContactEmail          string         `json:"contactEmail"`                    // 联系邮箱
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const WorkflowBundleVersion = "v1"
+
+const (
+	WorkflowBundleFormatYAML = "yaml"
+	WorkflowBundleFormatJSON = "json"
+)
+
+// 工作流导入导出的可移植包。
+// 流程图中引用的授权记录及子工作流均以占位符表示，导入时再映射为本地记录。
+type WorkflowBundle struct {
+	Version    string                    `json:"version"`
+	ExportedAt time.Time                 `json:"exportedAt"`
+	Workflows  []*WorkflowBundleWorkflow `json:"workflows"`
+	Accesses   []*WorkflowBundleAccess   `json:"accesses,omitempty"`
+}
+
+type WorkflowBundleWorkflow struct {
+	Ref               string                `json:"ref"`
+	Name              string                `json:"name"`
+	Description       string                `json:"description,omitempty"`
+	Trigger           WorkflowTriggerType   `json:"trigger"`
+	TriggerCron       string                `json:"triggerCron,omitempty"`
+	TriggerEvent      *WorkflowTriggerEvent `json:"triggerEvent,omitempty"`
+	Priority          int32                 `json:"priority,omitempty"`
+	MaxConcurrentRuns int32                 `json:"maxConcurrentRuns,omitempty"`
+	Graph             *WorkflowGraph        `json:"graph"`
+}
+
+type WorkflowBundleAccess struct {
+	Ref      string `json:"ref"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+}
+
+const (
+	workflowBundlePlaceholderAccess   = "access"
+	workflowBundlePlaceholderWorkflow = "workflow"
+)
+
+func NewWorkflowBundleAccessPlaceholder(ref string) string {
+	return fmt.Sprintf("${%s.%s}", workflowBundlePlaceholderAccess, ref)
+}
+
+func NewWorkflowBundleWorkflowPlaceholder(ref string) string {
+	return fmt.Sprintf("${%s.%s}", workflowBundlePlaceholderWorkflow, ref)
+}
+
+// 解析授权记录占位符，返回其引用名。
+func ParseWorkflowBundleAccessPlaceholder(s string) (string, bool) {
+	return parseWorkflowBundlePlaceholder(s, workflowBundlePlaceholderAccess)
+}
+
+// 解析子工作流占位符，返回其引用名。
+func ParseWorkflowBundleWorkflowPlaceholder(s string) (string, bool) {
+	return parseWorkflowBundlePlaceholder(s, workflowBundlePlaceholderWorkflow)
+}
+
+func parseWorkflowBundlePlaceholder(s string, kind string) (string, bool) {
+	prefix := "${" + kind + "."
+	if !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+
+	ref := strings.TrimSuffix(strings.TrimPrefix(s, prefix), "}")
+	return ref, ref != ""
+}
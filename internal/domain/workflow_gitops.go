@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type WorkflowGitOpsModeType string
+
+const (
+	// 仅标记偏离清单的工作流，保留界面上的修改。
+	WorkflowGitOpsModeTypeFlag = WorkflowGitOpsModeType("flag")
+	// 禁止在界面上修改受管理的工作流，偏离清单时以清单为准还原。
+	WorkflowGitOpsModeTypeBlock = WorkflowGitOpsModeType("block")
+)
+
+type WorkflowGitOpsSyncStatusType string
+
+const (
+	WorkflowGitOpsSyncStatusTypeSynced   = WorkflowGitOpsSyncStatusType("synced")
+	WorkflowGitOpsSyncStatusTypeCreated  = WorkflowGitOpsSyncStatusType("created")
+	WorkflowGitOpsSyncStatusTypeUpdated  = WorkflowGitOpsSyncStatusType("updated")
+	WorkflowGitOpsSyncStatusTypeDrifted  = WorkflowGitOpsSyncStatusType("drifted")
+	WorkflowGitOpsSyncStatusTypeReverted = WorkflowGitOpsSyncStatusType("reverted")
+	WorkflowGitOpsSyncStatusTypeOrphaned = WorkflowGitOpsSyncStatusType("orphaned")
+	WorkflowGitOpsSyncStatusTypePruned   = WorkflowGitOpsSyncStatusType("pruned")
+	WorkflowGitOpsSyncStatusTypeFailed   = WorkflowGitOpsSyncStatusType("failed")
+)
+
+// GitOps 同步报告。
+type WorkflowGitOpsReport struct {
+	SyncedAt time.Time                   `json:"syncedAt"`
+	Error    string                      `json:"error,omitempty"`
+	Items    []*WorkflowGitOpsReportItem `json:"items"`
+}
+
+type WorkflowGitOpsReportItem struct {
+	Ref        string                       `json:"ref"`
+	File       string                       `json:"file,omitempty"`
+	WorkflowId string                       `json:"workflowId,omitempty"`
+	Name       string                       `json:"name"`
+	Status     WorkflowGitOpsSyncStatusType `json:"status"`
+	Changes    []string                     `json:"changes,omitempty"` // 偏离清单的字段
+	Error      string                       `json:"error,omitempty"`
+}
@@ -1,28 +1,110 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
 const CollectionNameWorkflowRun = "workflow_run"
 
+// 触发参数的最大字节数，需与 workflow_run 集合中 `triggerPayload` 字段的 maxSize 保持一致。
+const WorkflowRunTriggerPayloadMaxSize = 1000000
+
 type WorkflowRun struct {
 	Meta
-	WorkflowId string                `json:"workflowId" db:"workflowRef"`
-	Status     WorkflowRunStatusType `json:"status" db:"status"`
-	Trigger    WorkflowTriggerType   `json:"trigger" db:"trigger"`
-	StartedAt  time.Time             `json:"startedAt" db:"startedAt"`
-	EndedAt    time.Time             `json:"endedAt" db:"endedAt"`
-	Graph      *WorkflowGraph        `json:"graph" db:"graph"`
-	Error      string                `json:"error" db:"error"`
+	WorkflowId     string                `json:"workflowId" db:"workflowRef"`
+	ParentRunId    string                `json:"parentRunId" db:"parentRunRef"`
+	ResumedFromId  string                `json:"resumedFromId" db:"resumedFromRef"`
+	Status         WorkflowRunStatusType `json:"status" db:"status"`
+	Trigger        WorkflowTriggerType   `json:"trigger" db:"trigger"`
+	TriggerPayload map[string]any        `json:"triggerPayload" db:"triggerPayload"`
+	StartedAt      time.Time             `json:"startedAt" db:"startedAt"`
+	EndedAt        time.Time             `json:"endedAt" db:"endedAt"`
+	Graph          *WorkflowGraph        `json:"graph" db:"graph"`
+	Error          string                `json:"error" db:"error"`
+	Snapshot       *WorkflowRunSnapshot  `json:"snapshot" db:"snapshot"`
+	DryRun         bool                  `json:"dryRun" db:"dryRun"`
+	Plan           *WorkflowRunPlan      `json:"plan" db:"plan"`
+	ClaimedBy      string                `json:"claimedBy" db:"claimedBy"`
+	Priority       int32                 `json:"priority" db:"priority"`
+}
+
+// 获取触发本次运行的事件所经过的工作流触发链（仅适用于由事件触发的运行）。
+func (r *WorkflowRun) GetTriggerChain() []string {
+	if r.Trigger != WorkflowTriggerTypeEvent || r.TriggerPayload == nil {
+		return nil
+	}
+
+	chain, _ := r.TriggerPayload["triggerChain"].(string)
+	if chain == "" {
+		return nil
+	}
+	return strings.Split(chain, ";")
+}
+
+// 工作流运行结束（或暂停）时的状态快照，用于从失败节点恢复运行、从等待节点继续运行，或基于历史运行预览通知模板。
+type WorkflowRunSnapshot struct {
+	ErrorNodeId   string                         `json:"errorNodeId"`
+	WaitingNodeId string                         `json:"waitingNodeId,omitempty"`
+	Variables     []*WorkflowRunSnapshotVariable `json:"variables"`
+	InOuts        []*WorkflowRunSnapshotInOut    `json:"inouts"`
+}
+
+type WorkflowRunSnapshotVariable struct {
+	Scope     string `json:"scope,omitempty"`
+	Key       string `json:"key"`
+	Value     any    `json:"value"`
+	ValueType string `json:"valueType"`
+}
+
+type WorkflowRunSnapshotInOut struct {
+	NodeId     string `json:"nodeId"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Value      any    `json:"value"`
+	ValueType  string `json:"valueType"`
+	Persistent bool   `json:"persistent,omitempty"`
+}
+
+// 工作流试运行时生成的执行计划报告。
+type WorkflowRunPlan struct {
+	Steps []*WorkflowRunPlanStep `json:"steps"`
+}
+
+type WorkflowRunPlanStep struct {
+	WorkflowId string                `json:"workflowId"` // 节点所属的工作流 ID（子工作流中的节点与当前工作流不同）
+	NodeId     string                `json:"nodeId"`
+	NodeName   string                `json:"nodeName"`
+	NodeType   WorkflowNodeType      `json:"nodeType"`
+	Action     WorkflowRunPlanAction `json:"action"`
+	Reason     string                `json:"reason,omitempty"`
+	Details    map[string]any        `json:"details,omitempty"`
 }
 
+type WorkflowRunPlanAction string
+
+const (
+	WorkflowRunPlanActionExecute WorkflowRunPlanAction = "execute"
+	WorkflowRunPlanActionSkip    WorkflowRunPlanAction = "skip"
+	WorkflowRunPlanActionFail    WorkflowRunPlanAction = "fail"
+)
+
 type WorkflowRunStatusType string
 
 const (
 	WorkflowRunStatusTypePending    WorkflowRunStatusType = "pending"
 	WorkflowRunStatusTypeProcessing WorkflowRunStatusType = "processing"
+	WorkflowRunStatusTypeWaiting    WorkflowRunStatusType = "waiting"
 	WorkflowRunStatusTypeSucceeded  WorkflowRunStatusType = "succeeded"
 	WorkflowRunStatusTypeFailed     WorkflowRunStatusType = "failed"
 	WorkflowRunStatusTypeCanceled   WorkflowRunStatusType = "canceled"
 )
+
+type WorkflowRunApprovalStatusType string
+
+const (
+	WorkflowRunApprovalStatusTypePending  WorkflowRunApprovalStatusType = "pending"
+	WorkflowRunApprovalStatusTypeApproved WorkflowRunApprovalStatusType = "approved"
+	WorkflowRunApprovalStatusTypeRejected WorkflowRunApprovalStatusType = "rejected"
+	WorkflowRunApprovalStatusTypeExpired  WorkflowRunApprovalStatusType = "expired"
+)
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+type WorkflowGraphIssueLevelType string
+
+const (
+	WorkflowGraphIssueLevelTypeError   = WorkflowGraphIssueLevelType("error")
+	WorkflowGraphIssueLevelTypeWarning = WorkflowGraphIssueLevelType("warning")
+)
+
+// 流程图静态校验发现的问题。
+type WorkflowGraphIssue struct {
+	NodeId   string                      `json:"nodeId,omitempty"` // 零值时表示工作流级别的问题
+	NodeName string                      `json:"nodeName,omitempty"`
+	Level    WorkflowGraphIssueLevelType `json:"level"`
+	Field    string                      `json:"field,omitempty"`
+	Message  string                      `json:"message"`
+}
+
+func (i *WorkflowGraphIssue) String() string {
+	var sb strings.Builder
+	if i.NodeId != "" {
+		sb.WriteString(fmt.Sprintf("node #%s: ", i.NodeId))
+	}
+	if i.Field != "" {
+		sb.WriteString(fmt.Sprintf("%s: ", i.Field))
+	}
+	sb.WriteString(i.Message)
+	return sb.String()
+}
+
+type WorkflowGraphIssues []*WorkflowGraphIssue
+
+func (issues WorkflowGraphIssues) Errors() WorkflowGraphIssues {
+	errs := make(WorkflowGraphIssues, 0)
+	for _, issue := range issues {
+		if issue.Level == WorkflowGraphIssueLevelTypeError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+func (issues WorkflowGraphIssues) HasErrors() bool {
+	return len(issues.Errors()) > 0
+}
+
+// 将全部错误级别的问题合并为一个 error。不存在错误时返回 nil。
+func (issues WorkflowGraphIssues) Err() error {
+	errs := issues.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, issue := range errs {
+		messages = append(messages, issue.String())
+	}
+	return fmt.Errorf("workflow is invalid: %s", strings.Join(messages, "; "))
+}
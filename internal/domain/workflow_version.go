@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+const CollectionNameWorkflowVersion = "workflow_version"
+
+type WorkflowVersion struct {
+	Meta
+	WorkflowId string         `json:"workflowId" db:"workflowRef"`
+	Version    int32          `json:"version" db:"version"`
+	Graph      *WorkflowGraph `json:"graph" db:"graph"`
+	Author     string         `json:"author" db:"author"`
+	Note       string         `json:"note" db:"note"`
+}
+
+type WorkflowGraphDiffAction string
+
+const (
+	WorkflowGraphDiffActionAdded    = WorkflowGraphDiffAction("added")
+	WorkflowGraphDiffActionRemoved  = WorkflowGraphDiffAction("removed")
+	WorkflowGraphDiffActionModified = WorkflowGraphDiffAction("modified")
+)
+
+type WorkflowGraphNodeDiff struct {
+	NodeId   string                    `json:"nodeId"`
+	NodeName string                    `json:"nodeName"`
+	NodeType WorkflowNodeType          `json:"nodeType"`
+	Action   WorkflowGraphDiffAction   `json:"action"`
+	Changes  []*WorkflowGraphFieldDiff `json:"changes,omitempty"`
+}
+
+type WorkflowGraphFieldDiff struct {
+	Field    string `json:"field"` // 字段路径，如 "name"、"config.provider"
+	OldValue any    `json:"oldValue"`
+	NewValue any    `json:"newValue"`
+}
+
+// 逐节点比较两个流程图的差异，包括节点的增删、位置变化及配置变化。
+// 容器节点的子节点单独比较，其变化不计入容器节点本身。
+func DiffWorkflowGraph(from, to *WorkflowGraph) []*WorkflowGraphNodeDiff {
+	fromNodes := flattenWorkflowGraph(from)
+	toNodes := flattenWorkflowGraph(to)
+
+	diffs := make([]*WorkflowGraphNodeDiff, 0)
+	for _, toNode := range toNodes {
+		fromNode, ok := lo.Find(fromNodes, func(n *workflowGraphFlatNode) bool { return n.Node.Id == toNode.Node.Id })
+		if !ok {
+			diffs = append(diffs, &WorkflowGraphNodeDiff{
+				NodeId:   toNode.Node.Id,
+				NodeName: toNode.Node.Data.Name,
+				NodeType: toNode.Node.Type,
+				Action:   WorkflowGraphDiffActionAdded,
+			})
+			continue
+		}
+
+		changes := make([]*WorkflowGraphFieldDiff, 0)
+		compare := func(field string, oldValue, newValue any) {
+			if !reflect.DeepEqual(oldValue, newValue) {
+				changes = append(changes, &WorkflowGraphFieldDiff{Field: field, OldValue: oldValue, NewValue: newValue})
+			}
+		}
+		compare("type", fromNode.Node.Type, toNode.Node.Type)
+		compare("parentId", fromNode.ParentId, toNode.ParentId)
+		compare("index", fromNode.Index, toNode.Index)
+		compare("name", fromNode.Node.Data.Name, toNode.Node.Data.Name)
+		compare("disabled", fromNode.Node.Data.Disabled, toNode.Node.Data.Disabled)
+		compare("timeout", fromNode.Node.Data.Timeout, toNode.Node.Data.Timeout)
+		compare("retry", fromNode.Node.Data.Retry, toNode.Node.Data.Retry)
+
+		configKeys := lo.Uniq(append(lo.Keys(fromNode.Node.Data.Config), lo.Keys(toNode.Node.Data.Config)...))
+		slices.Sort(configKeys)
+		for _, key := range configKeys {
+			compare("config."+key, fromNode.Node.Data.Config[key], toNode.Node.Data.Config[key])
+		}
+
+		if len(changes) > 0 {
+			diffs = append(diffs, &WorkflowGraphNodeDiff{
+				NodeId:   toNode.Node.Id,
+				NodeName: toNode.Node.Data.Name,
+				NodeType: toNode.Node.Type,
+				Action:   WorkflowGraphDiffActionModified,
+				Changes:  changes,
+			})
+		}
+	}
+
+	for _, fromNode := range fromNodes {
+		if !lo.ContainsBy(toNodes, func(n *workflowGraphFlatNode) bool { return n.Node.Id == fromNode.Node.Id }) {
+			diffs = append(diffs, &WorkflowGraphNodeDiff{
+				NodeId:   fromNode.Node.Id,
+				NodeName: fromNode.Node.Data.Name,
+				NodeType: fromNode.Node.Type,
+				Action:   WorkflowGraphDiffActionRemoved,
+			})
+		}
+	}
+
+	return diffs
+}
+
+type workflowGraphFlatNode struct {
+	Node     *WorkflowNode
+	ParentId string
+	Index    int
+}
+
+func flattenWorkflowGraph(graph *WorkflowGraph) []*workflowGraphFlatNode {
+	flatNodes := make([]*workflowGraphFlatNode, 0)
+	if graph == nil {
+		return flatNodes
+	}
+
+	var walk func(nodes []*WorkflowNode, parentId string)
+	walk = func(nodes []*WorkflowNode, parentId string) {
+		for i, node := range nodes {
+			flatNodes = append(flatNodes, &workflowGraphFlatNode{Node: node, ParentId: parentId, Index: i})
+			walk(node.Blocks, node.Id)
+		}
+	}
+	walk(graph.Nodes, "")
+
+	return flatNodes
+}
@@ -0,0 +1,84 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type EventHandler func(ctx context.Context, event *domain.Event)
+
+type EventBus interface {
+	Publish(ctx context.Context, event *domain.Event)
+	Subscribe(handler EventHandler) (unsubscribe func())
+}
+
+type eventBus struct {
+	mtx      sync.RWMutex
+	nextId   int
+	handlers map[int]EventHandler
+
+	syslog *slog.Logger
+}
+
+var _ EventBus = (*eventBus)(nil)
+
+func (eb *eventBus) Publish(ctx context.Context, event *domain.Event) {
+	if event == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	eb.mtx.RLock()
+	handlers := make([]EventHandler, 0, len(eb.handlers))
+	for _, handler := range eb.handlers {
+		handlers = append(handlers, handler)
+	}
+	eb.mtx.RUnlock()
+
+	// 异步分发事件，避免订阅者阻塞发布者（如工作流节点执行）
+	for _, handler := range handlers {
+		go func(handler EventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					eb.syslog.Error(fmt.Sprintf("event handler panic: %v", r), slog.String("eventType", string(event.Type)))
+					slog.Default().Error(fmt.Sprintf("event handler panic: %v, stack trace: %s", r, string(debug.Stack())), slog.String("eventType", string(event.Type)))
+				}
+			}()
+
+			handler(context.WithoutCancel(ctx), event)
+		}(handler)
+	}
+}
+
+func (eb *eventBus) Subscribe(handler EventHandler) func() {
+	eb.mtx.Lock()
+	defer eb.mtx.Unlock()
+
+	id := eb.nextId
+	eb.nextId++
+	eb.handlers[id] = handler
+
+	return func() {
+		eb.mtx.Lock()
+		defer eb.mtx.Unlock()
+
+		delete(eb.handlers, id)
+	}
+}
+
+func newEventBus() EventBus {
+	return &eventBus{
+		handlers: make(map[int]EventHandler),
+
+		syslog: app.GetLogger(),
+	}
+}
@@ -0,0 +1,17 @@
+package eventbus
+
+import (
+	"sync"
+)
+
+var (
+	instance    EventBus
+	intanceOnce sync.Once
+)
+
+func GetSingletonEventBus() EventBus {
+	intanceOnce.Do(func() {
+		instance = newEventBus()
+	})
+	return instance
+}
@@ -0,0 +1,121 @@
+package privateca
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/cluster"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/repository"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+// 仅允许由 [PrivateCAService] 写入的字段，通过记录接口修改会破坏 CA 的密钥、证书与层级关系。
+var privateCAProtectedFields = []string{
+	"parentRef",
+	"commonName",
+	"serialNumber",
+	"certificate",
+	"privateKey",
+	"keyAlgorithm",
+	"validityNotBefore",
+	"validityNotAfter",
+}
+
+func Register() error {
+	// 多实例模式下各实例须使用相同的加密密钥，不能在各自的数据目录下自动生成
+	if cluster.GetSingletonCluster().IsHAEnabled() && !repository.IsPrivateCASecretConfigured() {
+		return errors.New("the environment variable 'CERTIMATE_PRIVATECA_SECRET' is required when HA mode is enabled")
+	}
+
+	pb := app.GetApp()
+	pb.OnRecordCreateRequest(domain.CollectionNamePrivateCA).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := onPrivateCARecordBeforeCreateRequest(e); err != nil {
+			return err
+		}
+
+		return e.Next()
+	})
+	pb.OnRecordUpdateRequest(domain.CollectionNamePrivateCA).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := onPrivateCARecordBeforeUpdateRequest(e); err != nil {
+			return err
+		}
+
+		return e.Next()
+	})
+	pb.OnRecordDeleteRequest(domain.CollectionNamePrivateCA).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := onPrivateCARecordBeforeDeleteRequest(e); err != nil {
+			return err
+		}
+
+		return e.Next()
+	})
+
+	return nil
+}
+
+func onPrivateCARecordBeforeCreateRequest(e *core.RecordRequestEvent) error {
+	// 私有 CA 须通过创建或导入接口生成，以确保密钥经过加密、证书与密钥匹配
+	return router.NewBadRequestError("Private CAs can only be created or imported via the private CA APIs.", nil)
+}
+
+func onPrivateCARecordBeforeUpdateRequest(e *core.RecordRequestEvent) error {
+	original := e.Record.Original()
+	for _, field := range privateCAProtectedFields {
+		if original.GetString(field) != e.Record.GetString(field) {
+			return router.NewBadRequestError(fmt.Sprintf("Field '%s' of private CA cannot be modified.", field), nil)
+		}
+	}
+
+	return nil
+}
+
+func onPrivateCARecordBeforeDeleteRequest(e *core.RecordRequestEvent) error {
+	// 删除仍有下级 CA 的私有 CA 会使下级 CA 的证书链断裂，须先删除下级 CA
+	total, err := e.App.CountRecords(domain.CollectionNamePrivateCA, dbx.HashExp{"parentRef": e.Record.Id})
+	if err != nil {
+		return err
+	} else if total > 0 {
+		return router.NewBadRequestError("The private CA still has subordinate CAs, please delete them first.", nil)
+	}
+
+	// 删除仍被工作流引用的私有 CA 会使其申请节点无法签发证书，须先修改工作流
+	workflowRecords, err := e.App.FindAllRecords(domain.CollectionNameWorkflow, dbx.Like("graphContent", e.Record.Id))
+	if err != nil {
+		return err
+	}
+	for _, workflowRecord := range workflowRecords {
+		graph := &domain.WorkflowGraph{}
+		if err := workflowRecord.UnmarshalJSONField("graphContent", graph); err != nil {
+			return err
+		}
+
+		if isPrivateCAReferencedByNodes(graph.Nodes, e.Record.Id) {
+			return router.NewBadRequestError(fmt.Sprintf("The private CA is still used by workflow #%s, please modify it first.", workflowRecord.Id), nil)
+		}
+	}
+
+	return nil
+}
+
+func isPrivateCAReferencedByNodes(nodes []*domain.WorkflowNode, privateCAId string) bool {
+	for _, node := range nodes {
+		if node.Type == domain.WorkflowNodeTypeBizApply {
+			nodeCfg := node.Data.Config.AsBizApply()
+			if nodeCfg.CAProvider == string(domain.CAProviderTypePrivateCA) && xmaps.GetString(nodeCfg.CAProviderConfig, "privateCaId") == privateCAId {
+				return true
+			}
+		}
+
+		if isPrivateCAReferencedByNodes(node.Blocks, privateCAId) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,272 @@
+package privateca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+const (
+	defaultLeafLifetime = 90 * 24 * time.Hour
+
+	// 终端证书的用途模板。
+	ProfileServer = "server" // 服务端证书
+	ProfileClient = "client" // 客户端证书
+	ProfilePeer   = "peer"   // 同时用作服务端和客户端的证书，适用于服务间的双向 TLS
+)
+
+var keyUsageNames = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+}
+
+var extKeyUsageNames = map[string]x509.ExtKeyUsage{
+	"any":             x509.ExtKeyUsageAny,
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+type IssueCertificateRequest struct {
+	PrivateCAId     string
+	SubjectAltNames []string // 可以是域名、IP 地址、电子邮箱或 URI
+	KeyType         certcrypto.KeyType
+	Profile         string   // 用途模板（零值时默认值 [ProfileServer]）
+	KeyUsages       []string // 密钥用途（非零值时覆盖用途模板）
+	ExtKeyUsages    []string // 扩展密钥用途（非零值时覆盖用途模板）
+	ValidityTo      time.Time
+}
+
+type IssueCertificateResponse struct {
+	FullChainCertificate string
+	IssuerCertificate    string
+	PrivateKey           string
+}
+
+// 由私有 CA 签发终端证书。
+// 证书有效期不会超过签发 CA 的有效期，未指定时默认 90 天。
+func (s *PrivateCAService) IssueCertificate(ctx context.Context, req *IssueCertificateRequest) (*IssueCertificateResponse, error) {
+	if req.PrivateCAId == "" {
+		return nil, errors.New("the private ca is not specified")
+	}
+
+	template, err := newLeafTemplate(req.SubjectAltNames)
+	if err != nil {
+		return nil, err
+	}
+
+	privateCA, issuerCert, issuerKey, err := s.loadIssuer(ctx, req.PrivateCAId)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := certcrypto.GeneratePrivateKey(req.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("the private key is not a signer")
+	}
+
+	_, isRSA := signer.Public().(*rsa.PublicKey)
+	template.KeyUsage, template.ExtKeyUsage, err = resolveUsages(req.Profile, req.KeyUsages, req.ExtKeyUsages, isRSA)
+	if err != nil {
+		return nil, err
+	}
+
+	// 签发时间提前 1 分钟，以容忍客户端的时钟偏差
+	now := time.Now()
+	template.NotBefore = now.Add(-1 * time.Minute)
+	template.NotAfter = now.Add(defaultLeafLifetime)
+	if !req.ValidityTo.IsZero() {
+		if !req.ValidityTo.After(now) {
+			return nil, errors.New("the validity lifetime must be positive")
+		}
+		template.NotAfter = req.ValidityTo
+	}
+	if template.NotAfter.After(issuerCert.NotAfter) {
+		template.NotAfter = issuerCert.NotAfter
+	}
+
+	template.SerialNumber, err = generateSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, issuerCert, signer.Public(), issuerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	certX509, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	certPEM, err := xcert.ConvertCertificateToPEM(certX509)
+	if err != nil {
+		return nil, err
+	}
+
+	issuerChainPEM, err := s.buildIssuerChain(ctx, privateCA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueCertificateResponse{
+		FullChainCertificate: strings.TrimSpace(certPEM) + "\n" + issuerChainPEM,
+		IssuerCertificate:    issuerChainPEM,
+		PrivateKey:           strings.TrimSpace(string(certcrypto.PEMEncode(privateKey))),
+	}, nil
+}
+
+// 解析主题备用名称，以首个域名或 IP 地址作为通用名称。
+func newLeafTemplate(subjectAltNames []string) (*x509.Certificate, error) {
+	template := &x509.Certificate{BasicConstraintsValid: true}
+
+	for _, name := range subjectAltNames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		count := len(template.DNSNames) + len(template.IPAddresses)
+		if err := parseSubjectAltName(name, template); err != nil {
+			return nil, err
+		}
+
+		if template.Subject.CommonName == "" && len(name) <= 64 && len(template.DNSNames)+len(template.IPAddresses) > count {
+			template.Subject = pkix.Name{CommonName: name}
+		}
+	}
+
+	if len(template.DNSNames)+len(template.IPAddresses)+len(template.EmailAddresses)+len(template.URIs) == 0 {
+		return nil, errors.New("at least one subject alternative name is required")
+	}
+
+	return template, nil
+}
+
+// 校验主题备用名称是否合法。
+func CheckSubjectAltName(name string) error {
+	return parseSubjectAltName(strings.TrimSpace(name), &x509.Certificate{})
+}
+
+// 校验终端证书的用途模板及密钥用途是否合法。
+func CheckUsages(profile string, keyUsages []string, extKeyUsages []string) error {
+	_, _, err := resolveUsages(profile, keyUsages, extKeyUsages, false)
+	return err
+}
+
+// 解析以分号分隔的密钥用途列表。
+func SplitUsages(s string) []string {
+	usages := make([]string, 0)
+	for _, usage := range strings.Split(s, ";") {
+		if usage = strings.TrimSpace(usage); usage != "" {
+			usages = append(usages, usage)
+		}
+	}
+	return usages
+}
+
+// 解析主题备用名称并追加到证书模板中。
+// 按 IP 地址、URI（含 "://"）、电子邮箱（含 "@"）、域名的顺序识别。
+func parseSubjectAltName(name string, template *x509.Certificate) error {
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = append(template.IPAddresses, ip)
+		return nil
+	}
+
+	if strings.Contains(name, "://") {
+		uri, err := url.Parse(name)
+		if err != nil || uri.Scheme == "" {
+			return fmt.Errorf("invalid uri '%s'", name)
+		}
+		template.URIs = append(template.URIs, uri)
+		return nil
+	}
+
+	if strings.Contains(name, "@") {
+		addr, err := mail.ParseAddress(name)
+		if err != nil || addr.Address != name {
+			return fmt.Errorf("invalid email address '%s'", name)
+		}
+		template.EmailAddresses = append(template.EmailAddresses, name)
+		return nil
+	}
+
+	if strings.ContainsAny(name, " /\\:") || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid domain name '%s'", name)
+	}
+	if strings.Contains(strings.TrimPrefix(name, "*."), "*") {
+		return fmt.Errorf("invalid wildcard domain name '%s'", name)
+	}
+	template.DNSNames = append(template.DNSNames, strings.ToLower(name))
+	return nil
+}
+
+func resolveUsages(profile string, keyUsages []string, extKeyUsages []string, isRSA bool) (x509.KeyUsage, []x509.ExtKeyUsage, error) {
+	// 仅 RSA 密钥可用于密钥加密
+	keyUsage := x509.KeyUsageDigitalSignature
+	if isRSA {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	var extKeyUsage []x509.ExtKeyUsage
+	switch profile {
+	case "", ProfileServer:
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	case ProfileClient:
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	case ProfilePeer:
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	default:
+		return 0, nil, fmt.Errorf("unsupported certificate profile '%s'", profile)
+	}
+
+	if len(keyUsages) > 0 {
+		keyUsage = 0
+		for _, name := range keyUsages {
+			usage, ok := keyUsageNames[name]
+			if !ok {
+				return 0, nil, fmt.Errorf("unsupported key usage '%s'", name)
+			}
+			keyUsage |= usage
+		}
+	}
+
+	if len(extKeyUsages) > 0 {
+		extKeyUsage = make([]x509.ExtKeyUsage, 0, len(extKeyUsages))
+		for _, name := range extKeyUsages {
+			usage, ok := extKeyUsageNames[name]
+			if !ok {
+				return 0, nil, fmt.Errorf("unsupported extended key usage '%s'", name)
+			}
+			extKeyUsage = append(extKeyUsage, usage)
+		}
+	}
+
+	return keyUsage, extKeyUsage, nil
+}
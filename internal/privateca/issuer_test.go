@@ -0,0 +1,215 @@
+package privateca
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type memoryPrivateCARepository struct {
+	records map[string]*domain.PrivateCA
+}
+
+func (r *memoryPrivateCARepository) GetById(ctx context.Context, id string) (*domain.PrivateCA, error) {
+	if privateCA, ok := r.records[id]; ok {
+		return privateCA, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *memoryPrivateCARepository) Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error) {
+	if privateCA.Id == "" {
+		privateCA.Id = fmt.Sprintf("ca%d", len(r.records)+1)
+	}
+	r.records[privateCA.Id] = privateCA
+	return privateCA, nil
+}
+
+func TestResolveUsages(t *testing.T) {
+	tests := []struct {
+		name         string
+		profile      string
+		keyUsages    []string
+		extKeyUsages []string
+		isRSA        bool
+		wantKeyUsage x509.KeyUsage
+		wantExtUsage []x509.ExtKeyUsage
+		wantErr      bool
+	}{
+		{name: "default profile", wantKeyUsage: x509.KeyUsageDigitalSignature, wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}},
+		{name: "default profile with rsa key", isRSA: true, wantKeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment, wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}},
+		{name: "client profile", profile: ProfileClient, wantKeyUsage: x509.KeyUsageDigitalSignature, wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}},
+		{name: "peer profile", profile: ProfilePeer, wantKeyUsage: x509.KeyUsageDigitalSignature, wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}},
+		{
+			name:         "override usages",
+			profile:      ProfileServer,
+			keyUsages:    []string{"digitalSignature", "keyAgreement"},
+			extKeyUsages: []string{"codeSigning"},
+			isRSA:        true,
+			wantKeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+			wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		},
+		{name: "unknown profile", profile: "ca", wantErr: true},
+		{name: "unknown key usage", keyUsages: []string{"certSign"}, wantErr: true},
+		{name: "unknown extended key usage", extKeyUsages: []string{"serverauth"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyUsage, extKeyUsage, err := resolveUsages(tt.profile, tt.keyUsages, tt.extKeyUsages, tt.isRSA)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveUsages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if keyUsage != tt.wantKeyUsage {
+				t.Errorf("resolveUsages() keyUsage = %v, want %v", keyUsage, tt.wantKeyUsage)
+			}
+			if !slices.Equal(extKeyUsage, tt.wantExtUsage) {
+				t.Errorf("resolveUsages() extKeyUsage = %v, want %v", extKeyUsage, tt.wantExtUsage)
+			}
+		})
+	}
+}
+
+func TestNewLeafTemplate(t *testing.T) {
+	tests := []struct {
+		name            string
+		subjectAltNames []string
+		wantCommonName  string
+		wantDNSNames    []string
+		wantErr         bool
+	}{
+		{name: "domains", subjectAltNames: []string{"Example.com", "*.example.com"}, wantCommonName: "Example.com", wantDNSNames: []string{"example.com", "*.example.com"}},
+		{name: "ip address", subjectAltNames: []string{"10.0.0.1", "::1"}, wantCommonName: "10.0.0.1"},
+		{name: "email is not common name", subjectAltNames: []string{"admin@example.com", "example.com"}, wantCommonName: "example.com", wantDNSNames: []string{"example.com"}},
+		{name: "uri only", subjectAltNames: []string{"spiffe://example.org/service"}},
+		{name: "blank names are ignored", subjectAltNames: []string{" ", "example.com "}, wantCommonName: "example.com", wantDNSNames: []string{"example.com"}},
+		{name: "empty", subjectAltNames: []string{""}, wantErr: true},
+		{name: "invalid email", subjectAltNames: []string{"Admin <admin@example.com>"}, wantErr: true},
+		{name: "invalid uri", subjectAltNames: []string{"://example.com"}, wantErr: true},
+		{name: "invalid domain", subjectAltNames: []string{"example .com"}, wantErr: true},
+		{name: "invalid wildcard domain", subjectAltNames: []string{"a.*.example.com"}, wantErr: true},
+		{name: "trailing dot", subjectAltNames: []string{"example.com."}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := newLeafTemplate(tt.subjectAltNames)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newLeafTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if template.Subject.CommonName != tt.wantCommonName {
+				t.Errorf("newLeafTemplate() commonName = %q, want %q", template.Subject.CommonName, tt.wantCommonName)
+			}
+			if len(tt.wantDNSNames) > 0 && !slices.Equal(template.DNSNames, tt.wantDNSNames) {
+				t.Errorf("newLeafTemplate() dnsNames = %v, want %v", template.DNSNames, tt.wantDNSNames)
+			}
+		})
+	}
+}
+
+func TestSplitUsages(t *testing.T) {
+	got := SplitUsages(" digitalSignature ;; keyAgreement;")
+	want := []string{"digitalSignature", "keyAgreement"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SplitUsages() got = %v, want %v", got, want)
+	}
+}
+
+func TestIssueCertificate(t *testing.T) {
+	ctx := context.Background()
+	service := NewPrivateCAService(&memoryPrivateCARepository{records: make(map[string]*domain.PrivateCA)})
+
+	rootResp, err := service.CreatePrivateCA(ctx, &dtos.PrivateCACreateReq{CommonName: "Test Root CA", ValidityLifetime: "30d"})
+	if err != nil {
+		t.Fatalf("CreatePrivateCA() error = %v", err)
+	}
+
+	intermediateResp, err := service.CreatePrivateCA(ctx, &dtos.PrivateCACreateReq{CommonName: "Test Intermediate CA", ParentId: rootResp.PrivateCA.Id})
+	if err != nil {
+		t.Fatalf("CreatePrivateCA() error = %v", err)
+	}
+
+	// 中间 CA 默认只允许签发终端证书
+	if _, err := service.CreatePrivateCA(ctx, &dtos.PrivateCACreateReq{CommonName: "Test Sub CA", ParentId: intermediateResp.PrivateCA.Id}); err == nil {
+		t.Errorf("CreatePrivateCA() under a path length constrained ca expected error, got nil")
+	}
+
+	resp, err := service.IssueCertificate(ctx, &IssueCertificateRequest{
+		PrivateCAId:     intermediateResp.PrivateCA.Id,
+		SubjectAltNames: []string{"example.com", "10.0.0.1"},
+		KeyType:         certcrypto.EC256,
+		Profile:         ProfilePeer,
+		ValidityTo:      time.Now().Add(365 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("IssueCertificate() error = %v", err)
+	}
+
+	certs := make([]*x509.Certificate, 0)
+	for rest := []byte(resp.FullChainCertificate); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse full chain certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("expected full chain without root certificate to have 2 certificates, got %d", len(certs))
+	}
+
+	leaf := certs[0]
+	if leaf.Subject.CommonName != "example.com" {
+		t.Errorf("unexpected common name: %s", leaf.Subject.CommonName)
+	}
+	if !slices.Equal(leaf.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}) {
+		t.Errorf("unexpected extended key usages: %v", leaf.ExtKeyUsage)
+	}
+	if leaf.NotAfter.After(certs[1].NotAfter) {
+		t.Errorf("expected validity to be capped by the issuer, got %s > %s", leaf.NotAfter, certs[1].NotAfter)
+	}
+
+	rootCert, err := xcert.ParseCertificateFromPEM(rootResp.PrivateCA.Certificate)
+	if err != nil {
+		t.Fatalf("failed to parse root certificate: %v", err)
+	}
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(rootCert)
+	intermediates.AddCert(certs[1])
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       "example.com",
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Errorf("failed to verify issued certificate: %v", err)
+	}
+
+	if !strings.Contains(resp.PrivateKey, "PRIVATE KEY") {
+		t.Errorf("unexpected private key: %s", resp.PrivateKey)
+	}
+}
@@ -0,0 +1,298 @@
+package privateca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/samber/lo"
+	"github.com/xhit/go-str2duration/v2"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+const (
+	defaultRootLifetime         = 10 * 365 * 24 * time.Hour
+	defaultIntermediateLifetime = 5 * 365 * 24 * time.Hour
+	defaultKeyAlgorithm         = domain.CertificateKeyAlgorithmTypeEC384
+
+	// 证书链的最大深度，防止上级 CA 的引用成环。
+	maxChainDepth = 16
+)
+
+type PrivateCAService struct {
+	privateCARepo privateCARepository
+}
+
+func NewPrivateCAService(privateCARepo privateCARepository) *PrivateCAService {
+	return &PrivateCAService{
+		privateCARepo: privateCARepo,
+	}
+}
+
+func (s *PrivateCAService) CreatePrivateCA(ctx context.Context, req *dtos.PrivateCACreateReq) (*dtos.PrivateCACreateResp, error) {
+	if req.CommonName == "" {
+		return nil, domain.NewError(400, "invalid parameters: common name is required")
+	}
+
+	keyAlgorithm := domain.CertificateKeyAlgorithmType(req.KeyAlgorithm)
+	if keyAlgorithm == "" {
+		keyAlgorithm = defaultKeyAlgorithm
+	}
+	keyType, err := keyAlgorithm.KeyType()
+	if err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: %s", err.Error()))
+	}
+
+	lifetime := lo.Ternary(req.ParentId == "", defaultRootLifetime, defaultIntermediateLifetime)
+	if req.ValidityLifetime != "" {
+		duration, err := str2duration.ParseDuration(req.ValidityLifetime)
+		if err != nil || duration <= 0 {
+			return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: invalid validity lifetime '%s'", req.ValidityLifetime))
+		}
+		lifetime = duration
+	}
+
+	if req.MaxPathLen != nil && *req.MaxPathLen < 0 {
+		return nil, domain.NewError(400, "invalid parameters: max path length must be non-negative")
+	}
+
+	privateKey, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   req.CommonName,
+			Organization: lo.Compact([]string{req.Organization}),
+		},
+		NotBefore:             now,
+		NotAfter:              now.Add(lifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            -1,
+	}
+	if req.MaxPathLen != nil {
+		template.MaxPathLen = int(*req.MaxPathLen)
+		template.MaxPathLenZero = *req.MaxPathLen == 0
+	}
+
+	// 根 CA 自签名，中间 CA 由上级 CA 签发
+	signer := privateKey.(crypto.Signer)
+	issuerCert, issuerKey := template, signer
+	if req.ParentId != "" {
+		parentCA, parentCert, parentKey, err := s.loadIssuer(ctx, req.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		// 中间 CA 的路径长度约束须小于上级 CA 的约束，未指定时默认只允许签发终端证书
+		if parentCert.MaxPathLen == 0 && parentCert.MaxPathLenZero {
+			return nil, domain.NewError(400, fmt.Sprintf("private ca #%s is not allowed to issue intermediate ca", parentCA.Id))
+		}
+		if req.MaxPathLen == nil {
+			template.MaxPathLen = 0
+			template.MaxPathLenZero = true
+		}
+		if parentCert.MaxPathLen > 0 && template.MaxPathLen >= parentCert.MaxPathLen {
+			return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: max path length must be less than %d", parentCert.MaxPathLen))
+		}
+
+		if template.NotAfter.After(parentCert.NotAfter) {
+			template.NotAfter = parentCert.NotAfter
+		}
+
+		issuerCert, issuerKey = parentCert, parentKey
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, issuerCert, signer.Public(), issuerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ca certificate: %w", err)
+	}
+
+	certX509, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca certificate: %w", err)
+	}
+
+	certPEM, err := xcert.ConvertCertificateToPEM(certX509)
+	if err != nil {
+		return nil, err
+	}
+
+	privateCA := &domain.PrivateCA{
+		Name:        lo.CoalesceOrEmpty(req.Name, req.CommonName),
+		ParentId:    req.ParentId,
+		Certificate: strings.TrimSpace(certPEM),
+		PrivateKey:  strings.TrimSpace(string(certcrypto.PEMEncode(privateKey))),
+	}
+	populateFromX509(privateCA, certX509)
+	if privateCA, err = s.privateCARepo.Save(ctx, privateCA); err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCACreateResp{PrivateCA: privateCA}, nil
+}
+
+func (s *PrivateCAService) ImportPrivateCA(ctx context.Context, req *dtos.PrivateCAImportReq) (*dtos.PrivateCAImportResp, error) {
+	certX509, err := xcert.ParseCertificateFromPEM(req.Certificate)
+	if err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: failed to parse certificate: %s", err.Error()))
+	} else if !certX509.IsCA || !certX509.BasicConstraintsValid {
+		return nil, domain.NewError(400, "invalid parameters: the certificate is not a ca certificate")
+	} else if certX509.KeyUsage != 0 && certX509.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, domain.NewError(400, "invalid parameters: the certificate is not allowed to sign certificates")
+	}
+
+	privateKey, err := xcert.ParsePrivateKeyFromPEM(req.PrivateKey)
+	if err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: failed to parse private key: %s", err.Error()))
+	}
+	if signer, ok := privateKey.(crypto.Signer); !ok {
+		return nil, domain.NewError(400, "invalid parameters: unsupported private key")
+	} else if pubkey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pubkey.Equal(certX509.PublicKey) {
+		return nil, domain.NewError(400, "invalid parameters: the private key does not match the certificate")
+	}
+
+	if req.ParentId != "" {
+		parentCA, err := s.privateCARepo.GetById(ctx, req.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		parentCert, err := xcert.ParseCertificateFromPEM(parentCA.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate of private ca #%s: %w", parentCA.Id, err)
+		}
+
+		if err := certX509.CheckSignatureFrom(parentCert); err != nil {
+			return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: the certificate is not issued by private ca #%s", parentCA.Id))
+		}
+	}
+
+	privateCA := &domain.PrivateCA{
+		Name:        lo.CoalesceOrEmpty(req.Name, certX509.Subject.CommonName),
+		ParentId:    req.ParentId,
+		Certificate: strings.TrimSpace(req.Certificate),
+		PrivateKey:  strings.TrimSpace(req.PrivateKey),
+	}
+	populateFromX509(privateCA, certX509)
+	if privateCA, err = s.privateCARepo.Save(ctx, privateCA); err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCAImportResp{PrivateCA: privateCA}, nil
+}
+
+// 读取用于签发证书的 CA，并校验其仍在有效期内。
+func (s *PrivateCAService) loadIssuer(ctx context.Context, privateCAId string) (*domain.PrivateCA, *x509.Certificate, crypto.Signer, error) {
+	privateCA, err := s.privateCARepo.GetById(ctx, privateCAId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, nil, nil, fmt.Errorf("private ca #%s not found", privateCAId)
+		}
+		return nil, nil, nil, fmt.Errorf("failed to get private ca #%s: %w", privateCAId, err)
+	}
+
+	certX509, err := xcert.ParseCertificateFromPEM(privateCA.Certificate)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse certificate of private ca #%s: %w", privateCA.Id, err)
+	}
+
+	now := time.Now()
+	if now.Before(certX509.NotBefore) || now.After(certX509.NotAfter) {
+		return nil, nil, nil, fmt.Errorf("private ca #%s is not in its validity period", privateCA.Id)
+	}
+
+	privateKey, err := xcert.ParsePrivateKeyFromPEM(privateCA.PrivateKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse private key of private ca #%s: %w", privateCA.Id, err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unsupported private key of private ca #%s", privateCA.Id)
+	}
+
+	return privateCA, certX509, signer, nil
+}
+
+// 构造由指定 CA 签发的证书的上级证书链，不含自签名的根证书。
+// 若指定 CA 即为根 CA，则仅返回根证书。
+func (s *PrivateCAService) buildIssuerChain(ctx context.Context, privateCA *domain.PrivateCA) (string, error) {
+	chain := make([]string, 0)
+	visited := make(map[string]bool)
+
+	current := privateCA
+	for depth := 0; ; depth++ {
+		if depth >= maxChainDepth || visited[current.Id] {
+			return "", fmt.Errorf("the chain of private ca #%s is too deep or has a cycle", privateCA.Id)
+		}
+		visited[current.Id] = true
+
+		certX509, err := xcert.ParseCertificateFromPEM(current.Certificate)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate of private ca #%s: %w", current.Id, err)
+		}
+
+		if !isSelfSigned(certX509) || current.Id == privateCA.Id {
+			chain = append(chain, current.Certificate)
+		}
+
+		if current.ParentId == "" {
+			break
+		}
+
+		parent, err := s.privateCARepo.GetById(ctx, current.ParentId)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent of private ca #%s: %w", current.Id, err)
+		}
+		current = parent
+	}
+
+	return strings.Join(chain, "\n"), nil
+}
+
+func populateFromX509(privateCA *domain.PrivateCA, certX509 *x509.Certificate) {
+	certificate := (&domain.Certificate{}).PopulateFromX509(certX509)
+	privateCA.CommonName = certX509.Subject.CommonName
+	privateCA.SerialNumber = certificate.SerialNumber
+	privateCA.KeyAlgorithm = certificate.KeyAlgorithm
+	privateCA.ValidityNotBefore = certificate.ValidityNotBefore
+	privateCA.ValidityNotAfter = certificate.ValidityNotAfter
+}
+
+func generateSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	return serialNumber, nil
+}
+
+func isSelfSigned(certX509 *x509.Certificate) bool {
+	if !strings.EqualFold(certX509.Subject.String(), certX509.Issuer.String()) {
+		return false
+	}
+
+	return certX509.CheckSignatureFrom(certX509) == nil
+}
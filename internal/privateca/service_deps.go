@@ -0,0 +1,12 @@
+package privateca
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type privateCARepository interface {
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+	Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error)
+}
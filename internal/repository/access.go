@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/certimate-go/certimate/internal/app"
@@ -33,6 +34,31 @@ func (r *AccessRepository) GetById(ctx context.Context, id string) (*domain.Acce
 	return r.castRecordToModel(record)
 }
 
+func (r *AccessRepository) ListByNameAndProvider(ctx context.Context, name string, provider string) ([]*domain.Access, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameAccess,
+		"name={:name} && provider={:provider} && deleted=null",
+		"-created",
+		0, 0,
+		dbx.Params{"name": name, "provider": provider},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make([]*domain.Access, 0)
+	for _, record := range records {
+		access, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		accesses = append(accesses, access)
+	}
+
+	return accesses, nil
+}
+
 func (r *AccessRepository) castRecordToModel(record *core.Record) (*domain.Access, error) {
 	if record == nil {
 		return nil, errors.New("the record is nil")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
@@ -17,12 +18,18 @@ func NewCertificateRepository() *CertificateRepository {
 	return &CertificateRepository{}
 }
 
-func (r *CertificateRepository) ListExpiringSoon(ctx context.Context) ([]*domain.Certificate, error) {
+// 列出即将过期的证书。
+// 由工作流签发的证书，仅返回每个工作流节点最新签发的一张，已被续期替代的旧证书不再返回。
+func (r *CertificateRepository) ListExpiringSoon(ctx context.Context, daysLeft int) ([]*domain.Certificate, error) {
 	records, err := app.GetApp().FindAllRecords(
 		domain.CollectionNameCertificate,
 		dbx.NewExp("validityNotAfter>DATETIME('now')"),
-		dbx.NewExp("validityNotAfter<DATETIME('now', '+20 days')"),
-		dbx.NewExp("deleted=null"),
+		dbx.NewExp(fmt.Sprintf("validityNotAfter<DATETIME('now', '+%d days')", daysLeft)),
+		dbx.NewExp("deleted=''"),
+		dbx.NewExp(`(workflowRef='' OR NOT EXISTS (
+			SELECT 1 FROM certificate AS t
+			WHERE t.workflowRef = certificate.workflowRef AND t.workflowNodeId = certificate.workflowNodeId AND t.deleted = '' AND t.created > certificate.created
+		))`),
 	)
 	if err != nil {
 		return nil, err
@@ -97,6 +104,40 @@ func (r *CertificateRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context,
 	return r.castRecordToModel(records[0])
 }
 
+// 标记证书的即将过期事件已触发指定工作流。
+// 若此前已标记（如已由其他实例处理），则返回 false。
+func (r *CertificateRepository) MarkExpiringNotified(ctx context.Context, certificateId string, workflowId string) (bool, error) {
+	res, err := app.GetDB().
+		NewQuery(`UPDATE certificate SET expiringNotifiedWorkflows = json_insert(COALESCE(NULLIF(expiringNotifiedWorkflows, ''), '[]'), '$[#]', {:workflowId})
+			WHERE id = {:id} AND NOT EXISTS (
+				SELECT 1 FROM json_each(COALESCE(NULLIF(certificate.expiringNotifiedWorkflows, ''), '[]')) WHERE value = {:workflowId}
+			)`).
+		Bind(dbx.Params{"id": certificateId, "workflowId": workflowId}).
+		Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// 撤销证书的即将过期事件已触发指定工作流的标记。
+func (r *CertificateRepository) UnmarkExpiringNotified(ctx context.Context, certificateId string, workflowId string) error {
+	_, err := app.GetDB().
+		NewQuery(`UPDATE certificate SET expiringNotifiedWorkflows = (
+				SELECT json_group_array(value) FROM json_each(COALESCE(NULLIF(certificate.expiringNotifiedWorkflows, ''), '[]')) WHERE value <> {:workflowId}
+			)
+			WHERE id = {:id}`).
+		Bind(dbx.Params{"id": certificateId, "workflowId": workflowId}).
+		Execute()
+	return err
+}
+
 func (r *CertificateRepository) Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
 	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameCertificate)
 	if err != nil {
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type InstanceRepository struct{}
+
+func NewInstanceRepository() *InstanceRepository {
+	return &InstanceRepository{}
+}
+
+func (r *InstanceRepository) ListAlive(ctx context.Context, timeout time.Duration) ([]*domain.Instance, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameInstance,
+		dbx.NewExp(fmt.Sprintf("heartbeatAt>DATETIME('now', '-%d seconds')", int(timeout.Seconds()))),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]*domain.Instance, 0)
+	for _, record := range records {
+		instance, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+func (r *InstanceRepository) Save(ctx context.Context, instance *domain.Instance) (*domain.Instance, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameInstance)
+	if err != nil {
+		return instance, err
+	}
+
+	// 实例 ID 由实例自身生成，首次上报心跳时创建记录
+	record, err := app.GetApp().FindRecordById(collection, instance.Id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return instance, err
+		}
+
+		record = core.NewRecord(collection)
+		record.Id = instance.Id
+	}
+
+	record.Set("hostname", instance.Hostname)
+	record.Set("heartbeatAt", instance.HeartbeatAt)
+	err = app.GetApp().Save(record)
+	if err != nil {
+		return instance, err
+	}
+
+	instance.Id = record.Id
+	instance.CreatedAt = record.GetDateTime("created").Time()
+	instance.UpdatedAt = record.GetDateTime("updated").Time()
+	return instance, nil
+}
+
+func (r *InstanceRepository) DeleteById(ctx context.Context, id string) error {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameInstance, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	return app.GetApp().Delete(record)
+}
+
+func (r *InstanceRepository) DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameInstance, exprs...)
+	if err != nil {
+		return 0, err
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
+
+func (r *InstanceRepository) castRecordToModel(record *core.Record) (*domain.Instance, error) {
+	if record == nil {
+		return nil, errors.New("the record is nil")
+	}
+
+	instance := &domain.Instance{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Hostname:    record.GetString("hostname"),
+		HeartbeatAt: record.GetDateTime("heartbeatAt").Time(),
+	}
+	return instance, nil
+}
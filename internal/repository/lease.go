@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type LeaseRepository struct{}
+
+func NewLeaseRepository() *LeaseRepository {
+	return &LeaseRepository{}
+}
+
+// 尝试获取或续期指定名称的租约。
+// 仅当租约不存在、已过期或已由同一持有者持有时才能获取成功。
+func (r *LeaseRepository) TryAcquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameLease)
+	if err != nil {
+		return false, err
+	}
+
+	acquired := false
+	inserting := false
+	err = app.GetApp().RunInTransaction(func(txApp core.App) error {
+		record, err := txApp.FindFirstRecordByFilter(collection, "name={:name}", dbx.Params{"name": name})
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			record = core.NewRecord(collection)
+			record.Set("name", name)
+			inserting = true
+		} else if record.GetString("holder") != holder && record.GetDateTime("expiresAt").Time().After(time.Now()) {
+			return nil
+		}
+
+		record.Set("holder", holder)
+		record.Set("expiresAt", time.Now().Add(ttl))
+		if err := txApp.Save(record); err != nil {
+			return err
+		}
+
+		acquired = true
+		return nil
+	})
+	if err != nil {
+		// 多个实例同时首次获取同一租约时，仅有一个能插入成功，其余的会违反唯一约束，此时视作未获取到租约
+		if inserting {
+			if _, ferr := app.GetApp().FindFirstRecordByFilter(collection, "name={:name}", dbx.Params{"name": name}); ferr == nil {
+				return false, nil
+			}
+		}
+
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+func (r *LeaseRepository) DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameLease, exprs...)
+	if err != nil {
+		return 0, err
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	// 私钥密文的前缀，用于区分加密版本。
+	privateCAKeyCipherPrefix = "enc:v1:"
+	// 自动生成的加密密钥文件名，位于数据目录下。
+	privateCAKeyFileName = ".privateca_secret"
+)
+
+type PrivateCARepository struct{}
+
+func NewPrivateCARepository() *PrivateCARepository {
+	return &PrivateCARepository{}
+}
+
+func (r *PrivateCARepository) List(ctx context.Context) ([]*domain.PrivateCA, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNamePrivateCA)
+	if err != nil {
+		return nil, err
+	}
+
+	privateCAs := make([]*domain.PrivateCA, 0, len(records))
+	for _, record := range records {
+		privateCA, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		privateCAs = append(privateCAs, privateCA)
+	}
+
+	return privateCAs, nil
+}
+
+func (r *PrivateCARepository) GetById(ctx context.Context, id string) (*domain.PrivateCA, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNamePrivateCA, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *PrivateCARepository) Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNamePrivateCA)
+	if err != nil {
+		return privateCA, err
+	}
+
+	var record *core.Record
+	if privateCA.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, privateCA.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return privateCA, domain.ErrRecordNotFound
+			}
+			return privateCA, err
+		}
+	}
+
+	privateKeyCipher, err := encryptPrivateCAKey(privateCA.PrivateKey)
+	if err != nil {
+		return privateCA, fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+
+	record.Set("name", privateCA.Name)
+	record.Set("parentRef", privateCA.ParentId)
+	record.Set("commonName", privateCA.CommonName)
+	record.Set("serialNumber", privateCA.SerialNumber)
+	record.Set("certificate", privateCA.Certificate)
+	record.Set("privateKey", privateKeyCipher)
+	record.Set("keyAlgorithm", string(privateCA.KeyAlgorithm))
+	record.Set("validityNotBefore", privateCA.ValidityNotBefore)
+	record.Set("validityNotAfter", privateCA.ValidityNotAfter)
+	if err := app.GetApp().Save(record); err != nil {
+		return privateCA, err
+	}
+
+	privateCA.Id = record.Id
+	privateCA.CreatedAt = record.GetDateTime("created").Time()
+	privateCA.UpdatedAt = record.GetDateTime("updated").Time()
+	return privateCA, nil
+}
+
+func (r *PrivateCARepository) castRecordToModel(record *core.Record) (*domain.PrivateCA, error) {
+	if record == nil {
+		return nil, errors.New("the record is nil")
+	}
+
+	privateKey, err := decryptPrivateCAKey(record.GetString("privateKey"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key of private ca #%s: %w", record.Id, err)
+	}
+
+	privateCA := &domain.PrivateCA{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Name:              record.GetString("name"),
+		ParentId:          record.GetString("parentRef"),
+		CommonName:        record.GetString("commonName"),
+		SerialNumber:      record.GetString("serialNumber"),
+		Certificate:       record.GetString("certificate"),
+		PrivateKey:        privateKey,
+		KeyAlgorithm:      domain.CertificateKeyAlgorithmType(record.GetString("keyAlgorithm")),
+		ValidityNotBefore: record.GetDateTime("validityNotBefore").Time(),
+		ValidityNotAfter:  record.GetDateTime("validityNotAfter").Time(),
+	}
+	return privateCA, nil
+}
+
+var (
+	privateCAKey     string
+	privateCAKeyErr  error
+	privateCAKeyOnce sync.Once
+)
+
+// 判断是否已通过环境变量设置私有 CA 私钥的加密密钥。
+// 多实例部署时各实例的数据目录未必共享，须通过环境变量为所有实例设置相同的密钥。
+func IsPrivateCASecretConfigured() bool {
+	return getPrivateCASecretFromEnv() != ""
+}
+
+func getPrivateCASecretFromEnv() string {
+	secret := os.Getenv("CERTIMATE_PRIVATECA_SECRET")
+	if secret == "" && app.GetApp().EncryptionEnv() != "" {
+		secret = os.Getenv(app.GetApp().EncryptionEnv())
+	}
+	return secret
+}
+
+// 获取私有 CA 私钥的加密密钥。
+// 优先取自环境变量 `CERTIMATE_PRIVATECA_SECRET`，其次取自 PocketBase 的 `--encryptionEnv` 参数所指定的环境变量；
+// 均未设置时，在数据目录下自动生成密钥文件（仅限单实例部署，参见 [IsPrivateCASecretConfigured]）。
+func getPrivateCAKey() (string, error) {
+	privateCAKeyOnce.Do(func() {
+		secret := getPrivateCASecretFromEnv()
+		if secret == "" {
+			keyFile := filepath.Join(app.GetApp().DataDir(), privateCAKeyFileName)
+			if data, err := os.ReadFile(keyFile); err == nil {
+				secret = strings.TrimSpace(string(data))
+			} else if errors.Is(err, os.ErrNotExist) {
+				secret = security.RandomString(64)
+				if err := os.WriteFile(keyFile, []byte(secret), 0o600); err != nil {
+					privateCAKeyErr = fmt.Errorf("failed to write secret file: %w", err)
+					return
+				}
+
+				app.GetLogger().Warn("private ca secret is not configured, a random one has been generated", slog.String("file", keyFile))
+			} else {
+				privateCAKeyErr = fmt.Errorf("failed to read secret file: %w", err)
+				return
+			}
+		}
+
+		// AES-256 要求 32 字节的密钥
+		hash := sha256.Sum256([]byte(secret))
+		privateCAKey = string(hash[:])
+	})
+
+	return privateCAKey, privateCAKeyErr
+}
+
+func encryptPrivateCAKey(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", errors.New("the private key is empty")
+	}
+
+	key, err := getPrivateCAKey()
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := security.Encrypt([]byte(plaintext), key)
+	if err != nil {
+		return "", err
+	}
+
+	return privateCAKeyCipherPrefix + ciphertext, nil
+}
+
+func decryptPrivateCAKey(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, privateCAKeyCipherPrefix) {
+		return "", errors.New("the private key is not encrypted")
+	}
+
+	key, err := getPrivateCAKey()
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := security.Decrypt(strings.TrimPrefix(ciphertext, privateCAKeyCipherPrefix), key)
+	if err != nil {
+		return "", errors.New("the private key cannot be decrypted, may be the secret has been changed")
+	}
+
+	return string(plaintext), nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
 )
 
 type SettingsRepository struct{}
@@ -45,3 +46,30 @@ func (r *SettingsRepository) GetByName(ctx context.Context, name string) (*domai
 	}
 	return settings, nil
 }
+
+// 保存系统设置，同名设置已存在时覆盖其内容。
+func (r *SettingsRepository) Save(ctx context.Context, settings *domain.Settings) (*domain.Settings, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameSettings)
+	if err != nil {
+		return settings, err
+	}
+
+	record, err := app.GetApp().FindFirstRecordByFilter(collection, "name={:name}", dbx.Params{"name": settings.Name})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return settings, err
+		}
+		record = core.NewRecord(collection)
+	}
+
+	record.Set("name", settings.Name)
+	record.Set("content", settings.Content)
+	if err := app.GetApp().Save(record); err != nil {
+		return settings, err
+	}
+
+	settings.Id = record.Id
+	settings.CreatedAt = record.GetDateTime("created").Time()
+	settings.UpdatedAt = record.GetDateTime("updated").Time()
+	return settings, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type txAppKey struct{}
+
+// 在同一事务中执行 fn。
+// fn 中以传入的 ctx 调用的仓储方法均使用该事务，fn 返回错误时回滚全部写入；已在事务中时直接复用外层事务。
+func RunInTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if _, ok := ctx.Value(txAppKey{}).(core.App); ok {
+		return fn(ctx)
+	}
+
+	return app.GetApp().RunInTransaction(func(txApp core.App) error {
+		return fn(context.WithValue(ctx, txAppKey{}, txApp))
+	})
+}
+
+// 将外部已开启的事务（如 PocketBase 钩子中开启的事务）绑定到 ctx，使以返回的 ctx 调用的仓储方法均使用该事务。
+func WithTransaction(ctx context.Context, txApp core.App) context.Context {
+	return context.WithValue(ctx, txAppKey{}, txApp)
+}
+
+// 获取当前上下文中的事务实例，不在事务中时返回全局实例。
+func getApp(ctx context.Context) core.App {
+	if txApp, ok := ctx.Value(txAppKey{}).(core.App); ok {
+		return txApp
+	}
+
+	return app.GetApp()
+}
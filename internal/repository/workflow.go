@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 
-	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
@@ -18,7 +17,7 @@ func NewWorkflowRepository() *WorkflowRepository {
 }
 
 func (r *WorkflowRepository) ListEnabledScheduled(ctx context.Context) ([]*domain.Workflow, error) {
-	records, err := app.GetApp().FindRecordsByFilter(
+	records, err := getApp(ctx).FindRecordsByFilter(
 		domain.CollectionNameWorkflow,
 		"enabled={:enabled} && trigger={:trigger}",
 		"-created",
@@ -42,8 +41,57 @@ func (r *WorkflowRepository) ListEnabledScheduled(ctx context.Context) ([]*domai
 	return workflows, nil
 }
 
+func (r *WorkflowRepository) ListEnabledEventTriggered(ctx context.Context) ([]*domain.Workflow, error) {
+	records, err := getApp(ctx).FindRecordsByFilter(
+		domain.CollectionNameWorkflow,
+		"enabled={:enabled} && trigger={:trigger}",
+		"-created",
+		0, 0,
+		dbx.Params{"enabled": true, "trigger": string(domain.WorkflowTriggerTypeEvent)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]*domain.Workflow, 0)
+	for _, record := range records {
+		workflow, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
+func (r *WorkflowRepository) ListGitOpsManaged(ctx context.Context) ([]*domain.Workflow, error) {
+	records, err := getApp(ctx).FindRecordsByFilter(
+		domain.CollectionNameWorkflow,
+		"gitopsRef!=''",
+		"-created",
+		0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]*domain.Workflow, 0)
+	for _, record := range records {
+		workflow, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
 func (r *WorkflowRepository) GetById(ctx context.Context, id string) (*domain.Workflow, error) {
-	record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflow, id)
+	record, err := getApp(ctx).FindRecordById(domain.CollectionNameWorkflow, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
@@ -55,7 +103,7 @@ func (r *WorkflowRepository) GetById(ctx context.Context, id string) (*domain.Wo
 }
 
 func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow) (*domain.Workflow, error) {
-	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflow)
+	collection, err := getApp(ctx).FindCollectionByNameOrId(domain.CollectionNameWorkflow)
 	if err != nil {
 		return workflow, err
 	}
@@ -64,7 +112,7 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	if workflow.Id == "" {
 		record = core.NewRecord(collection)
 	} else {
-		record, err = app.GetApp().FindRecordById(collection, workflow.Id)
+		record, err = getApp(ctx).FindRecordById(collection, workflow.Id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return workflow, domain.ErrRecordNotFound
@@ -77,6 +125,11 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	record.Set("description", workflow.Description)
 	record.Set("trigger", string(workflow.Trigger))
 	record.Set("triggerCron", workflow.TriggerCron)
+	record.Set("triggerEvent", workflow.TriggerEvent)
+	record.Set("webhookKey", workflow.WebhookKey)
+	record.Set("webhookSecret", workflow.WebhookSecret)
+	record.Set("priority", workflow.Priority)
+	record.Set("maxConcurrentRuns", workflow.MaxConcurrentRuns)
 	record.Set("enabled", workflow.Enabled)
 	record.Set("graphDraft", workflow.GraphDraft)
 	record.Set("graphContent", workflow.GraphContent)
@@ -85,7 +138,10 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	record.Set("lastRunRef", workflow.LastRunId)
 	record.Set("lastRunStatus", string(workflow.LastRunStatus))
 	record.Set("lastRunTime", workflow.LastRunTime)
-	if err := app.GetApp().Save(record); err != nil {
+	record.Set("gitopsRef", workflow.GitOpsRef)
+	record.Set("gitopsChecksum", workflow.GitOpsChecksum)
+	record.Set("gitopsDrifted", workflow.GitOpsDrifted)
+	if err := getApp(ctx).Save(record); err != nil {
 		return workflow, err
 	}
 
@@ -95,6 +151,18 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	return workflow, nil
 }
 
+func (r *WorkflowRepository) DeleteById(ctx context.Context, id string) error {
+	record, err := getApp(ctx).FindRecordById(domain.CollectionNameWorkflow, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	return getApp(ctx).Delete(record)
+}
+
 func (r *WorkflowRepository) castRecordToModel(record *core.Record) (*domain.Workflow, error) {
 	if record == nil {
 		return nil, errors.New("the record is nil")
@@ -110,24 +178,37 @@ func (r *WorkflowRepository) castRecordToModel(record *core.Record) (*domain.Wor
 		return nil, errors.New("field 'graphContent' is malformed")
 	}
 
+	var triggerEvent *domain.WorkflowTriggerEvent
+	if err := record.UnmarshalJSONField("triggerEvent", &triggerEvent); err != nil {
+		return nil, errors.New("field 'triggerEvent' is malformed")
+	}
+
 	workflow := &domain.Workflow{
 		Meta: domain.Meta{
 			Id:        record.Id,
 			CreatedAt: record.GetDateTime("created").Time(),
 			UpdatedAt: record.GetDateTime("updated").Time(),
 		},
-		Name:          record.GetString("name"),
-		Description:   record.GetString("description"),
-		Trigger:       domain.WorkflowTriggerType(record.GetString("trigger")),
-		TriggerCron:   record.GetString("triggerCron"),
-		Enabled:       record.GetBool("enabled"),
-		GraphDraft:    graphDraft,
-		GraphContent:  graphContent,
-		HasDraft:      record.GetBool("hasDraft"),
-		HasContent:    record.GetBool("hasContent"),
-		LastRunId:     record.GetString("lastRunRef"),
-		LastRunStatus: domain.WorkflowRunStatusType(record.GetString("lastRunStatus")),
-		LastRunTime:   record.GetDateTime("lastRunTime").Time(),
+		Name:              record.GetString("name"),
+		Description:       record.GetString("description"),
+		Trigger:           domain.WorkflowTriggerType(record.GetString("trigger")),
+		TriggerCron:       record.GetString("triggerCron"),
+		TriggerEvent:      triggerEvent,
+		WebhookKey:        record.GetString("webhookKey"),
+		WebhookSecret:     record.GetString("webhookSecret"),
+		Priority:          int32(record.GetInt("priority")),
+		MaxConcurrentRuns: int32(record.GetInt("maxConcurrentRuns")),
+		Enabled:           record.GetBool("enabled"),
+		GraphDraft:        graphDraft,
+		GraphContent:      graphContent,
+		HasDraft:          record.GetBool("hasDraft"),
+		HasContent:        record.GetBool("hasContent"),
+		LastRunId:         record.GetString("lastRunRef"),
+		LastRunStatus:     domain.WorkflowRunStatusType(record.GetString("lastRunStatus")),
+		LastRunTime:       record.GetDateTime("lastRunTime").Time(),
+		GitOpsRef:         record.GetString("gitopsRef"),
+		GitOpsChecksum:    record.GetString("gitopsChecksum"),
+		GitOpsDrifted:     record.GetBool("gitopsDrifted"),
 	}
 	return workflow, nil
 }
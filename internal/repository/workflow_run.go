@@ -9,6 +9,7 @@ import (
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 type WorkflowRunRepository struct{}
@@ -17,6 +18,31 @@ func NewWorkflowRunRepository() *WorkflowRunRepository {
 	return &WorkflowRunRepository{}
 }
 
+func (r *WorkflowRunRepository) ListByStatus(ctx context.Context, status domain.WorkflowRunStatusType) ([]*domain.WorkflowRun, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowRun,
+		"status={:status}",
+		"created",
+		0, 0,
+		dbx.Params{"status": string(status)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRuns := make([]*domain.WorkflowRun, 0)
+	for _, record := range records {
+		workflowRun, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflowRuns = append(workflowRuns, workflowRun)
+	}
+
+	return workflowRuns, nil
+}
+
 func (r *WorkflowRunRepository) GetById(ctx context.Context, id string) (*domain.WorkflowRun, error) {
 	record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflowRun, id)
 	if err != nil {
@@ -49,12 +75,20 @@ func (r *WorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.Wo
 	}
 
 	record.Set("workflowRef", workflowRun.WorkflowId)
+	record.Set("parentRunRef", workflowRun.ParentRunId)
+	record.Set("resumedFromRef", workflowRun.ResumedFromId)
 	record.Set("trigger", string(workflowRun.Trigger))
+	record.Set("triggerPayload", workflowRun.TriggerPayload)
 	record.Set("status", string(workflowRun.Status))
 	record.Set("startedAt", workflowRun.StartedAt)
 	record.Set("endedAt", workflowRun.EndedAt)
 	record.Set("graph", workflowRun.Graph)
 	record.Set("error", workflowRun.Error)
+	record.Set("snapshot", workflowRun.Snapshot)
+	record.Set("dryRun", workflowRun.DryRun)
+	record.Set("plan", workflowRun.Plan)
+	record.Set("claimedBy", workflowRun.ClaimedBy)
+	record.Set("priority", workflowRun.Priority)
 	err = app.GetApp().Save(record)
 	if err != nil {
 		return workflowRun, err
@@ -66,6 +100,100 @@ func (r *WorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.Wo
 	return workflowRun, nil
 }
 
+// 由指定实例认领等待中的运行，并将其状态置为执行中。
+// 若该运行已被认领、已被取消，或其所属工作流正在执行的运行数已达上限，则认领失败。
+// 暂停等待中（如等待审批）的运行不占用并发数，恢复后需重新认领。
+func (r *WorkflowRunRepository) ClaimPending(ctx context.Context, id string, instanceId string, maxConcurrentRuns int) (bool, error) {
+	res, err := app.GetDB().
+		NewQuery(`UPDATE workflow_run SET status = 'processing', claimedBy = {:instanceId}, updated = {:updated}
+			WHERE id = {:id} AND status = 'pending' AND (
+				SELECT COUNT(1) FROM workflow_run AS t
+				WHERE t.workflowRef = workflow_run.workflowRef AND t.status = 'processing' AND t.parentRunRef = ''
+			) < {:maxConcurrentRuns}`).
+		Bind(dbx.Params{"id": id, "instanceId": instanceId, "maxConcurrentRuns": max(1, maxConcurrentRuns), "updated": types.NowDateTime().String()}).
+		Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// 由指定实例接管已失效实例认领的执行中的运行。
+// 若该运行已被其他实例接管或已结束，则接管失败。
+func (r *WorkflowRunRepository) ClaimInterrupted(ctx context.Context, id string, fromInstanceId string, toInstanceId string) (bool, error) {
+	res, err := app.GetDB().
+		NewQuery(`UPDATE workflow_run SET claimedBy = {:toInstanceId}, updated = {:updated}
+			WHERE id = {:id} AND status = 'processing' AND claimedBy = {:fromInstanceId}`).
+		Bind(dbx.Params{"id": id, "fromInstanceId": fromInstanceId, "toInstanceId": toInstanceId, "updated": types.NowDateTime().String()}).
+		Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// 将等待中的运行置为等待派发，并写入新的状态快照。
+// 若该运行已不在等待中（如已被取消，或已被其他请求处理），则操作失败。
+func (r *WorkflowRunRepository) ResumeWaiting(ctx context.Context, workflowRun *domain.WorkflowRun) (bool, error) {
+	errNotWaiting := errors.New("the workflow run is not waiting")
+
+	err := app.GetApp().RunInTransaction(func(txApp core.App) error {
+		record, err := txApp.FindRecordById(domain.CollectionNameWorkflowRun, workflowRun.Id)
+		if err != nil {
+			return err
+		} else if record.GetString("status") != string(domain.WorkflowRunStatusTypeWaiting) {
+			return errNotWaiting
+		}
+
+		record.Set("status", string(domain.WorkflowRunStatusTypePending))
+		record.Set("snapshot", workflowRun.Snapshot)
+		record.Set("claimedBy", "")
+		if err := txApp.Save(record); err != nil {
+			return err
+		}
+
+		workflowRun.Status = domain.WorkflowRunStatusTypePending
+		workflowRun.ClaimedBy = ""
+		workflowRun.UpdatedAt = record.GetDateTime("updated").Time()
+
+		// 事务级联更新所属工作流的最后运行状态
+		workflowRecord, err := txApp.FindRecordById(domain.CollectionNameWorkflow, workflowRun.WorkflowId)
+		if err != nil {
+			return err
+		} else if workflowRun.Id == workflowRecord.GetString("lastRunRef") {
+			workflowRecord.IgnoreUnchangedFields(true)
+			workflowRecord.Set("lastRunStatus", record.GetString("status"))
+			if err := txApp.Save(workflowRecord); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errNotWaiting) {
+			return false, nil
+		} else if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrRecordNotFound
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error) {
 	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflowRun)
 	if err != nil {
@@ -87,12 +215,20 @@ func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowR
 
 	err = app.GetApp().RunInTransaction(func(txApp core.App) error {
 		record.Set("workflowRef", workflowRun.WorkflowId)
+		record.Set("parentRunRef", workflowRun.ParentRunId)
+		record.Set("resumedFromRef", workflowRun.ResumedFromId)
 		record.Set("trigger", string(workflowRun.Trigger))
+		record.Set("triggerPayload", workflowRun.TriggerPayload)
 		record.Set("status", string(workflowRun.Status))
 		record.Set("startedAt", workflowRun.StartedAt)
 		record.Set("endedAt", workflowRun.EndedAt)
 		record.Set("graph", workflowRun.Graph)
 		record.Set("error", workflowRun.Error)
+		record.Set("snapshot", workflowRun.Snapshot)
+		record.Set("dryRun", workflowRun.DryRun)
+		record.Set("plan", workflowRun.Plan)
+		record.Set("claimedBy", workflowRun.ClaimedBy)
+		record.Set("priority", workflowRun.Priority)
 		err = txApp.Save(record)
 		if err != nil {
 			return err
@@ -102,6 +238,11 @@ func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowR
 		workflowRun.CreatedAt = record.GetDateTime("created").Time()
 		workflowRun.UpdatedAt = record.GetDateTime("updated").Time()
 
+		// 试运行不影响所属工作流的最后运行记录
+		if workflowRun.DryRun {
+			return nil
+		}
+
 		// 事务级联更新所属工作流的最后运行记录
 		workflowRecord, err := txApp.FindRecordById(domain.CollectionNameWorkflow, workflowRun.WorkflowId)
 		if err != nil {
@@ -133,6 +274,15 @@ func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowR
 	return workflowRun, nil
 }
 
+func (r *WorkflowRunRepository) CountWhere(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	count, err := app.GetApp().CountRecords(domain.CollectionNameWorkflowRun, exprs...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 func (r *WorkflowRunRepository) DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error) {
 	records, err := app.GetApp().FindAllRecords(domain.CollectionNameWorkflowRun, exprs...)
 	if err != nil {
@@ -166,19 +316,42 @@ func (r *WorkflowRunRepository) castRecordToModel(record *core.Record) (*domain.
 		return nil, errors.New("field 'graph' is malformed")
 	}
 
+	triggerPayload := make(map[string]any)
+	if err := record.UnmarshalJSONField("triggerPayload", &triggerPayload); err != nil {
+		return nil, errors.New("field 'triggerPayload' is malformed")
+	}
+
+	var snapshot *domain.WorkflowRunSnapshot
+	if err := record.UnmarshalJSONField("snapshot", &snapshot); err != nil {
+		return nil, errors.New("field 'snapshot' is malformed")
+	}
+
+	var plan *domain.WorkflowRunPlan
+	if err := record.UnmarshalJSONField("plan", &plan); err != nil {
+		return nil, errors.New("field 'plan' is malformed")
+	}
+
 	workflowRun := &domain.WorkflowRun{
 		Meta: domain.Meta{
 			Id:        record.Id,
 			CreatedAt: record.GetDateTime("created").Time(),
 			UpdatedAt: record.GetDateTime("updated").Time(),
 		},
-		WorkflowId: record.GetString("workflowRef"),
-		Status:     domain.WorkflowRunStatusType(record.GetString("status")),
-		Trigger:    domain.WorkflowTriggerType(record.GetString("trigger")),
-		StartedAt:  record.GetDateTime("startedAt").Time(),
-		EndedAt:    record.GetDateTime("endedAt").Time(),
-		Graph:      graph,
-		Error:      record.GetString("error"),
+		WorkflowId:     record.GetString("workflowRef"),
+		ParentRunId:    record.GetString("parentRunRef"),
+		ResumedFromId:  record.GetString("resumedFromRef"),
+		Status:         domain.WorkflowRunStatusType(record.GetString("status")),
+		Trigger:        domain.WorkflowTriggerType(record.GetString("trigger")),
+		TriggerPayload: triggerPayload,
+		StartedAt:      record.GetDateTime("startedAt").Time(),
+		EndedAt:        record.GetDateTime("endedAt").Time(),
+		Graph:          graph,
+		Error:          record.GetString("error"),
+		Snapshot:       snapshot,
+		DryRun:         record.GetBool("dryRun"),
+		Plan:           plan,
+		ClaimedBy:      record.GetString("claimedBy"),
+		Priority:       int32(record.GetInt("priority")),
 	}
 	return workflowRun, nil
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type WorkflowVersionRepository struct{}
+
+func NewWorkflowVersionRepository() *WorkflowVersionRepository {
+	return &WorkflowVersionRepository{}
+}
+
+func (r *WorkflowVersionRepository) GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error) {
+	records, err := getApp(ctx).FindRecordsByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId}",
+		"-version",
+		1, 0,
+		dbx.Params{"workflowId": workflowId},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return r.castRecordToModel(records[0])
+}
+
+func (r *WorkflowVersionRepository) GetByWorkflowIdAndVersion(ctx context.Context, workflowId string, version int32) (*domain.WorkflowVersion, error) {
+	record, err := getApp(ctx).FindFirstRecordByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId} && version={:version}",
+		dbx.Params{"workflowId": workflowId, "version": version},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+// 保存新的版本记录，版本号自动递增。
+func (r *WorkflowVersionRepository) Create(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error) {
+	collection, err := getApp(ctx).FindCollectionByNameOrId(domain.CollectionNameWorkflowVersion)
+	if err != nil {
+		return workflowVersion, err
+	}
+
+	record := core.NewRecord(collection)
+	err = getApp(ctx).RunInTransaction(func(txApp core.App) error {
+		var latestVersion struct {
+			Version int32 `db:"version"`
+		}
+		if err := txApp.DB().
+			NewQuery("SELECT COALESCE(MAX(version), 0) AS version FROM workflow_version WHERE workflowRef = {:workflowId}").
+			Bind(dbx.Params{"workflowId": workflowVersion.WorkflowId}).
+			One(&latestVersion); err != nil {
+			return err
+		}
+
+		record.Set("workflowRef", workflowVersion.WorkflowId)
+		record.Set("version", latestVersion.Version+1)
+		record.Set("graph", workflowVersion.Graph)
+		record.Set("author", workflowVersion.Author)
+		record.Set("note", workflowVersion.Note)
+		return txApp.Save(record)
+	})
+	if err != nil {
+		return workflowVersion, err
+	}
+
+	workflowVersion.Id = record.Id
+	workflowVersion.Version = int32(record.GetInt("version"))
+	workflowVersion.CreatedAt = record.GetDateTime("created").Time()
+	workflowVersion.UpdatedAt = record.GetDateTime("updated").Time()
+	return workflowVersion, nil
+}
+
+func (r *WorkflowVersionRepository) castRecordToModel(record *core.Record) (*domain.WorkflowVersion, error) {
+	if record == nil {
+		return nil, errors.New("the record is nil")
+	}
+
+	var graph *domain.WorkflowGraph
+	if err := record.UnmarshalJSONField("graph", &graph); err != nil {
+		return nil, errors.New("field 'graph' is malformed")
+	}
+
+	workflowVersion := &domain.WorkflowVersion{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		WorkflowId: record.GetString("workflowRef"),
+		Version:    int32(record.GetInt("version")),
+		Graph:      graph,
+		Author:     record.GetString("author"),
+		Note:       record.GetString("note"),
+	}
+	return workflowVersion, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type privateCAService interface {
+	CreatePrivateCA(ctx context.Context, req *dtos.PrivateCACreateReq) (*dtos.PrivateCACreateResp, error)
+	ImportPrivateCA(ctx context.Context, req *dtos.PrivateCAImportReq) (*dtos.PrivateCAImportResp, error)
+}
+
+type PrivateCAHandler struct {
+	service privateCAService
+}
+
+func NewPrivateCAHandler(router *router.RouterGroup[*core.RequestEvent], service privateCAService) {
+	handler := &PrivateCAHandler{
+		service: service,
+	}
+
+	group := router.Group("/private-cas")
+	group.POST("", handler.create)
+	group.POST("/import", handler.importCA)
+}
+
+func (handler *PrivateCAHandler) create(e *core.RequestEvent) error {
+	req := &dtos.PrivateCACreateReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.CreatePrivateCA(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *PrivateCAHandler) importCA(e *core.RequestEvent) error {
+	req := &dtos.PrivateCAImportReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.ImportPrivateCA(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
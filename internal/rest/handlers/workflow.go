@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/samber/lo"
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/domain/dtos"
@@ -15,7 +22,20 @@ import (
 type workflowService interface {
 	GetStatistics(ctx context.Context) (*dtos.WorkflowStatisticsResp, error)
 	StartRun(ctx context.Context, req *dtos.WorkflowStartRunReq) (*dtos.WorkflowStartRunResp, error)
+	TriggerWebhook(ctx context.Context, req *dtos.WorkflowWebhookTriggerReq) (*dtos.WorkflowStartRunResp, error)
+	ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error)
 	CancelRun(ctx context.Context, req *dtos.WorkflowCancelRunReq) (*dtos.WorkflowCancelRunResp, error)
+	DecideApproval(ctx context.Context, req *dtos.WorkflowDecideApprovalReq) (*dtos.WorkflowDecideApprovalResp, error)
+	DecideApprovalByLink(ctx context.Context, req *dtos.WorkflowDecideApprovalByLinkReq) (*dtos.WorkflowDecideApprovalResp, error)
+	PreviewTemplate(ctx context.Context, req *dtos.WorkflowPreviewTemplateReq) (*dtos.WorkflowPreviewTemplateResp, error)
+	DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error)
+	RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error)
+	RotateWebhookSecret(ctx context.Context, req *dtos.WorkflowRotateWebhookSecretReq) (*dtos.WorkflowRotateWebhookSecretResp, error)
+	ValidateGraph(ctx context.Context, req *dtos.WorkflowValidateGraphReq) (*dtos.WorkflowValidateGraphResp, error)
+	ExportBundle(ctx context.Context, req *dtos.WorkflowExportBundleReq) (*dtos.WorkflowExportBundleResp, error)
+	ImportBundle(ctx context.Context, req *dtos.WorkflowImportBundleReq) (*dtos.WorkflowImportBundleResp, error)
+	GetGitOpsStatus(ctx context.Context) (*dtos.WorkflowGitOpsStatusResp, error)
+	SyncGitOps(ctx context.Context) (*dtos.WorkflowGitOpsStatusResp, error)
 	Shutdown(ctx context.Context)
 }
 
@@ -31,7 +51,30 @@ func NewWorkflowHandler(router *router.RouterGroup[*core.RequestEvent], service
 	group := router.Group("/workflows")
 	group.GET("/stats", handler.getStatistics)
 	group.POST("/{workflowId}/runs", handler.startRun)
+	group.POST("/{workflowId}/runs/{runId}/resume", handler.resumeRun)
 	group.POST("/{workflowId}/runs/{runId}/cancel", handler.cancelRun)
+	group.POST("/{workflowId}/runs/{runId}/approve", handler.approveRun)
+	group.POST("/{workflowId}/runs/{runId}/reject", handler.rejectRun)
+	group.POST("/{workflowId}/runs/{runId}/preview-template", handler.previewTemplate)
+	group.GET("/{workflowId}/versions/diff", handler.diffVersions)
+	group.POST("/{workflowId}/versions/{version}/rollback", handler.rollbackVersion)
+	group.POST("/{workflowId}/webhook-secret", handler.rotateWebhookSecret)
+	group.POST("/{workflowId}/validate", handler.validateGraph)
+	group.GET("/{workflowId}/export", handler.exportBundle)
+	group.POST("/import", handler.importBundle)
+	group.GET("/gitops", handler.getGitOpsStatus)
+	group.POST("/gitops/sync", handler.syncGitOps)
+}
+
+func NewWorkflowWebhookHandler(router *router.RouterGroup[*core.RequestEvent], service workflowService) {
+	handler := &WorkflowHandler{
+		service: service,
+	}
+
+	group := router.Group("/workflows")
+	group.POST("/{workflowId}/{webhookKey}", handler.triggerWebhook)
+	group.GET("/{workflowId}/runs/{runId}/approval", handler.showApprovalPage)
+	group.POST("/{workflowId}/runs/{runId}/approval", handler.decideApprovalByLink)
 }
 
 func (handler *WorkflowHandler) getStatistics(e *core.RequestEvent) error {
@@ -61,6 +104,19 @@ func (handler *WorkflowHandler) startRun(e *core.RequestEvent) error {
 	return resp.Ok(e, res)
 }
 
+func (handler *WorkflowHandler) resumeRun(e *core.RequestEvent) error {
+	req := &dtos.WorkflowResumeRunReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+
+	res, err := handler.service.ResumeRun(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
 func (handler *WorkflowHandler) cancelRun(e *core.RequestEvent) error {
 	req := &dtos.WorkflowCancelRunReq{}
 	req.WorkflowId = e.Request.PathValue("workflowId")
@@ -73,3 +129,270 @@ func (handler *WorkflowHandler) cancelRun(e *core.RequestEvent) error {
 
 	return resp.Ok(e, res)
 }
+
+func (handler *WorkflowHandler) approveRun(e *core.RequestEvent) error {
+	return handler.decideApproval(e, "approve")
+}
+
+func (handler *WorkflowHandler) rejectRun(e *core.RequestEvent) error {
+	return handler.decideApproval(e, "reject")
+}
+
+func (handler *WorkflowHandler) decideApproval(e *core.RequestEvent, action string) error {
+	req := &dtos.WorkflowDecideApprovalReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+	req.Action = action
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+	if e.Auth != nil {
+		req.DecidedBy = lo.CoalesceOrEmpty(e.Auth.Email(), e.Auth.Id)
+	}
+
+	res, err := handler.service.DecideApproval(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) previewTemplate(e *core.RequestEvent) error {
+	req := &dtos.WorkflowPreviewTemplateReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.PreviewTemplate(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) diffVersions(e *core.RequestEvent) error {
+	fromVersion, err := strconv.ParseInt(e.Request.URL.Query().Get("from"), 10, 32)
+	if err != nil {
+		return resp.Err(e, errors.New("invalid parameters: the value of 'from' must be a version number"))
+	}
+
+	toVersion, err := strconv.ParseInt(e.Request.URL.Query().Get("to"), 10, 32)
+	if err != nil {
+		return resp.Err(e, errors.New("invalid parameters: the value of 'to' must be a version number"))
+	}
+
+	req := &dtos.WorkflowDiffVersionsReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.FromVersion = int32(fromVersion)
+	req.ToVersion = int32(toVersion)
+
+	res, err := handler.service.DiffVersions(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) rollbackVersion(e *core.RequestEvent) error {
+	version, err := strconv.ParseInt(e.Request.PathValue("version"), 10, 32)
+	if err != nil {
+		return resp.Err(e, errors.New("invalid parameters: the value of 'version' must be a version number"))
+	}
+
+	req := &dtos.WorkflowRollbackVersionReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.Version = int32(version)
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+	if e.Auth != nil {
+		req.Author = lo.CoalesceOrEmpty(e.Auth.Email(), e.Auth.Id)
+	}
+
+	res, err := handler.service.RollbackVersion(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) rotateWebhookSecret(e *core.RequestEvent) error {
+	req := &dtos.WorkflowRotateWebhookSecretReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.RotateWebhookSecret(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) validateGraph(e *core.RequestEvent) error {
+	req := &dtos.WorkflowValidateGraphReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.ValidateGraph(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) exportBundle(e *core.RequestEvent) error {
+	req := &dtos.WorkflowExportBundleReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.Format = e.Request.URL.Query().Get("format")
+
+	res, err := handler.service.ExportBundle(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) importBundle(e *core.RequestEvent) error {
+	req := &dtos.WorkflowImportBundleReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+	if e.Auth != nil {
+		req.Author = lo.CoalesceOrEmpty(e.Auth.Email(), e.Auth.Id)
+	}
+
+	res, err := handler.service.ImportBundle(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) triggerWebhook(e *core.RequestEvent) error {
+	body, err := io.ReadAll(io.LimitReader(e.Request.Body, domain.WorkflowRunTriggerPayloadMaxSize+1))
+	if err != nil {
+		return resp.Err(e, err)
+	} else if len(body) > domain.WorkflowRunTriggerPayloadMaxSize {
+		return resp.Err(e, errors.New("invalid parameters: the request body is too large"))
+	}
+
+	req := &dtos.WorkflowWebhookTriggerReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.WebhookKey = e.Request.PathValue("webhookKey")
+	req.Signature = e.Request.Header.Get("X-Certimate-Signature")
+	req.Body = body
+
+	res, err := handler.service.TriggerWebhook(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) showApprovalPage(e *core.RequestEvent) error {
+	// 仅展示确认页面，不做任何变更，以免邮件客户端等预取链接时误触发审批
+	query := e.Request.URL.Query()
+	data := map[string]any{
+		"WorkflowId": e.Request.PathValue("workflowId"),
+		"RunId":      e.Request.PathValue("runId"),
+		"Action":     query.Get("action"),
+	}
+	return renderApprovalPage(e, http.StatusOK, data)
+}
+
+func (handler *WorkflowHandler) decideApprovalByLink(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return renderApprovalPage(e, http.StatusBadRequest, map[string]any{"Error": "invalid parameters: the value of 'expires' must be a timestamp"})
+	}
+
+	req := &dtos.WorkflowDecideApprovalByLinkReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+	req.NodeId = query.Get("node")
+	req.Action = query.Get("action")
+	req.Expires = expires
+	req.Signature = query.Get("signature")
+	req.DecidedBy = strings.TrimSpace(e.Request.FormValue("decidedBy"))
+	req.Comment = strings.TrimSpace(e.Request.FormValue("comment"))
+
+	res, err := handler.service.DecideApprovalByLink(e.Request.Context(), req)
+	if err != nil {
+		code := http.StatusBadRequest
+		var xerr *domain.Error
+		if errors.As(err, &xerr) && xerr.Code == http.StatusUnauthorized {
+			code = http.StatusUnauthorized
+		}
+		return renderApprovalPage(e, code, map[string]any{"Error": err.Error()})
+	}
+
+	return renderApprovalPage(e, http.StatusOK, map[string]any{"Decided": string(res.Status)})
+}
+
+var approvalPageTemplate = template.Must(template.New("approval").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Certimate - Workflow Approval</title>
+</head>
+<body style="font-family: sans-serif; max-width: 480px; margin: 48px auto; padding: 0 16px;">
+<h2>Workflow Approval</h2>
+{{ if .Error }}
+<p style="color: #dc2626;">{{ .Error }}</p>
+{{ else if .Decided }}
+<p>The workflow run has been <strong>{{ .Decided }}</strong>.</p>
+{{ else }}
+<p>Workflow: <code>{{ .WorkflowId }}</code><br>Run: <code>{{ .RunId }}</code></p>
+<form method="post">
+<p><label>Your name<br><input type="text" name="decidedBy" maxlength="100" style="width: 100%;"></label></p>
+<p><label>Comment<br><textarea name="comment" rows="4" maxlength="1000" style="width: 100%;"></textarea></label></p>
+<p><button type="submit">{{ if eq .Action "reject" }}Reject{{ else }}Approve{{ end }}</button></p>
+</form>
+{{ end }}
+</body>
+</html>`))
+
+func renderApprovalPage(e *core.RequestEvent, status int, data map[string]any) error {
+	buf := &bytes.Buffer{}
+	if err := approvalPageTemplate.Execute(buf, data); err != nil {
+		return err
+	}
+
+	return e.HTML(status, buf.String())
+}
+
+func (handler *WorkflowHandler) getGitOpsStatus(e *core.RequestEvent) error {
+	res, err := handler.service.GetGitOpsStatus(e.Request.Context())
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowHandler) syncGitOps(e *core.RequestEvent) error {
+	res, err := handler.service.SyncGitOps(e.Request.Context())
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
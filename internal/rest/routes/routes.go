@@ -9,6 +9,7 @@ import (
 
 	"github.com/certimate-go/certimate/internal/certificate"
 	"github.com/certimate-go/certimate/internal/notify"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/rest/handlers"
 	"github.com/certimate-go/certimate/internal/statistics"
@@ -20,20 +21,24 @@ var (
 	workflowSvc    *workflow.WorkflowService
 	statisticsSvc  *statistics.StatisticsService
 	notifySvc      *notify.NotifyService
+	privateCASvc   *privateca.PrivateCAService
 )
 
 func Register(router *router.Router[*core.RequestEvent]) {
 	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
 	certificateRepo := repository.NewCertificateRepository()
 	settingsRepo := repository.NewSettingsRepository()
 	statisticsRepo := repository.NewStatisticsRepository()
+	privateCARepo := repository.NewPrivateCARepository()
 
 	certificateSvc = certificate.NewCertificateService(certificateRepo, settingsRepo)
-	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo, settingsRepo)
+	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, certificateRepo, accessRepo, settingsRepo, privateCARepo)
 	statisticsSvc = statistics.NewStatisticsService(statisticsRepo)
 	notifySvc = notify.NewNotifyService(accessRepo)
+	privateCASvc = privateca.NewPrivateCAService(privateCARepo)
 
 	group := router.Group("/api")
 	group.Bind(apis.RequireSuperuserAuth())
@@ -41,6 +46,11 @@ func Register(router *router.Router[*core.RequestEvent]) {
 	handlers.NewWorkflowHandler(group, workflowSvc)
 	handlers.NewStatisticsHandler(group, statisticsSvc)
 	handlers.NewNotifyHandler(group, notifySvc)
+	handlers.NewPrivateCAHandler(group, privateCASvc)
+
+	// Webhook 触发接口无需登录，通过 URL 中的密钥（及可选的签名）鉴权
+	webhookGroup := router.Group("/api/webhooks")
+	handlers.NewWorkflowWebhookHandler(webhookGroup, workflowSvc)
 }
 
 func Unregister() {
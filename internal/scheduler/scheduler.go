@@ -1,21 +1,31 @@
 package scheduler
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certificate"
+	"github.com/certimate-go/certimate/internal/cluster"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/workflow"
 )
 
 func Register() {
+	// 多实例模式下需先上报实例心跳，以便调度器判断其他实例是否存活
+	if err := cluster.GetSingletonCluster().Bootup(context.Background()); err != nil {
+		app.GetLogger().Error("failed to bootup cluster", slog.Any("error", err))
+	}
+
+	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
 	certificateRepo := repository.NewCertificateRepository()
 	settingsRepo := repository.NewSettingsRepository()
+	privateCARepo := repository.NewPrivateCARepository()
 
-	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo, settingsRepo)
+	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, certificateRepo, accessRepo, settingsRepo, privateCARepo)
 	certificateSvc := certificate.NewCertificateService(certificateRepo, settingsRepo)
 
 	if err := InitWorkflowScheduler(workflowSvc); err != nil {
@@ -26,3 +36,9 @@ func Register() {
 		app.GetLogger().Error("failed to init certificate scheduler", slog.Any("error", err))
 	}
 }
+
+func Unregister() {
+	if err := cluster.GetSingletonCluster().Shutdown(context.Background()); err != nil {
+		app.GetLogger().Error("failed to shutdown cluster", slog.Any("error", err))
+	}
+}
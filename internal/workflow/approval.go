@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
+)
+
+func (s *WorkflowService) DecideApproval(ctx context.Context, req *dtos.WorkflowDecideApprovalReq) (*dtos.WorkflowDecideApprovalResp, error) {
+	var status domain.WorkflowRunApprovalStatusType
+	switch req.Action {
+	case engine.ApprovalActionApprove:
+		status = domain.WorkflowRunApprovalStatusTypeApproved
+	case engine.ApprovalActionReject:
+		status = domain.WorkflowRunApprovalStatusTypeRejected
+	default:
+		return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: unsupported approval action '%s'", req.Action))
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != req.WorkflowId {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	if err := s.decideApproval(ctx, workflowRun, status, req.DecidedBy, req.Comment); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowDecideApprovalResp{Status: status}, nil
+}
+
+func (s *WorkflowService) DecideApprovalByLink(ctx context.Context, req *dtos.WorkflowDecideApprovalByLinkReq) (*dtos.WorkflowDecideApprovalResp, error) {
+	if !engine.VerifyApprovalLink(req.WorkflowId, req.RunId, req.NodeId, req.Action, req.Expires, req.Signature) {
+		return nil, domain.ErrApprovalLinkUnauthorized
+	} else if req.Expires > 0 && time.Now().Unix() > req.Expires {
+		return nil, domain.NewError(400, "the approval link has expired")
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != req.WorkflowId {
+		return nil, domain.ErrRecordNotFound
+	} else if workflowRun.Snapshot == nil || workflowRun.Snapshot.WaitingNodeId != req.NodeId {
+		// 同一运行可能先后在多个审批节点等待，旧节点的链接不能用于新节点
+		return nil, domain.NewError(400, "the approval link is no longer valid")
+	}
+
+	var status domain.WorkflowRunApprovalStatusType
+	switch req.Action {
+	case engine.ApprovalActionApprove:
+		status = domain.WorkflowRunApprovalStatusTypeApproved
+	case engine.ApprovalActionReject:
+		status = domain.WorkflowRunApprovalStatusTypeRejected
+	default:
+		return nil, domain.NewError(400, fmt.Sprintf("invalid parameters: unsupported approval action '%s'", req.Action))
+	}
+
+	decidedBy := "anonymous (via signed link)"
+	if req.DecidedBy != "" {
+		decidedBy = fmt.Sprintf("%s (via signed link)", req.DecidedBy)
+	}
+
+	if err := s.decideApproval(ctx, workflowRun, status, decidedBy, req.Comment); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowDecideApprovalResp{Status: status}, nil
+}
+
+func (s *WorkflowService) decideApproval(ctx context.Context, workflowRun *domain.WorkflowRun, status domain.WorkflowRunApprovalStatusType, decidedBy string, comment string) error {
+	if workflowRun.Status != domain.WorkflowRunStatusTypeWaiting || workflowRun.Snapshot == nil || workflowRun.Snapshot.WaitingNodeId == "" {
+		return domain.NewError(400, "workflow run is not waiting for approval")
+	} else if workflowRun.Graph == nil {
+		return errors.New("workflow run graph is empty")
+	} else if node, ok := workflowRun.Graph.GetNodeById(workflowRun.Snapshot.WaitingNodeId); !ok || node.Type != domain.WorkflowNodeTypeApproval {
+		return domain.NewError(400, "workflow run is not waiting for approval")
+	}
+
+	// 过期后只能被拒绝，不能再被批准
+	if status == domain.WorkflowRunApprovalStatusTypeApproved {
+		if expiresAt := engine.GetApprovalExpiresAt(workflowRun.Snapshot); !expiresAt.IsZero() && time.Now().After(expiresAt) {
+			return domain.NewError(400, "the approval has expired")
+		}
+	}
+
+	if err := engine.ApplyApprovalDecision(workflowRun.Snapshot, status, decidedBy, comment); err != nil {
+		return domain.NewError(400, err.Error())
+	}
+
+	// 以事务方式将运行由等待中改为排队中，防止重复审批
+	if ok, err := s.workflowRunRepo.ResumeWaiting(ctx, workflowRun); err != nil {
+		return err
+	} else if !ok {
+		return domain.NewError(400, "workflow run has already been decided")
+	}
+
+	app.GetLogger().Info(fmt.Sprintf("workflow run #%s is %s by %s", workflowRun.Id, status, decidedBy))
+
+	if err := s.dispatcher.Start(ctx, workflowRun.Id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *WorkflowService) expireWaitingApprovals(ctx context.Context) error {
+	workflowRuns, err := s.workflowRunRepo.ListByStatus(ctx, domain.WorkflowRunStatusTypeWaiting)
+	if err != nil {
+		app.GetLogger().Error("failed to list waiting workflow runs", slog.Any("error", err))
+		return err
+	}
+
+	for _, workflowRun := range workflowRuns {
+		expiresAt := engine.GetApprovalExpiresAt(workflowRun.Snapshot)
+		if expiresAt.IsZero() || time.Now().Before(expiresAt) {
+			continue
+		}
+
+		if err := s.decideApproval(ctx, workflowRun, domain.WorkflowRunApprovalStatusTypeExpired, "system", ""); err != nil {
+			app.GetLogger().Error(fmt.Sprintf("failed to expire approval of workflow run #%s", workflowRun.Id), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+func (s *WorkflowService) ExportBundle(ctx context.Context, req *dtos.WorkflowExportBundleReq) (*dtos.WorkflowExportBundleResp, error) {
+	format := req.Format
+	if format == "" {
+		format = domain.WorkflowBundleFormatYAML
+	} else if format != domain.WorkflowBundleFormatYAML && format != domain.WorkflowBundleFormatJSON {
+		return nil, domain.NewError(400, fmt.Sprintf("unsupported bundle format '%s'", format))
+	}
+
+	bundle := &domain.WorkflowBundle{
+		Version:    domain.WorkflowBundleVersion,
+		ExportedAt: time.Now(),
+		Workflows:  make([]*domain.WorkflowBundleWorkflow, 0),
+		Accesses:   make([]*domain.WorkflowBundleAccess, 0),
+	}
+
+	// 子工作流随同导出，其引用以占位符表示
+	workflowRefs := map[string]string{req.WorkflowId: "workflow1"} // Key: WorkflowId
+	accessRefs := make(map[string]string)                          // Key: AccessId
+	workflowQueue := []string{req.WorkflowId}
+	for len(workflowQueue) > 0 {
+		workflowId := workflowQueue[0]
+		workflowQueue = workflowQueue[1:]
+
+		workflow, err := s.workflowRepo.GetById(ctx, workflowId)
+		if err != nil {
+			return nil, err
+		} else if workflow.GraphContent == nil || len(workflow.GraphContent.Nodes) == 0 {
+			return nil, fmt.Errorf("workflow #%s graph content is empty", workflow.Id)
+		}
+
+		graph, err := cloneWorkflowGraph(workflow.GraphContent)
+		if err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		walkWorkflowNodes(graph.Nodes, func(node *domain.WorkflowNode) {
+			// 授权记录中包含密钥等敏感信息，仅导出其名称和提供商
+			for key, value := range node.Data.Config {
+				accessId, _ := value.(string)
+				if !strings.HasSuffix(key, "AccessId") || accessId == "" {
+					continue
+				}
+
+				accessRef, ok := accessRefs[accessId]
+				if !ok {
+					access, err := s.accessRepo.GetById(ctx, accessId)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get access #%s referenced by node #%s of workflow #%s: %w", accessId, node.Id, workflow.Id, err))
+						continue
+					}
+
+					accessRef = fmt.Sprintf("access%d", len(accessRefs)+1)
+					accessRefs[accessId] = accessRef
+					bundle.Accesses = append(bundle.Accesses, &domain.WorkflowBundleAccess{
+						Ref:      accessRef,
+						Name:     access.Name,
+						Provider: access.Provider,
+					})
+				}
+
+				node.Data.Config[key] = domain.NewWorkflowBundleAccessPlaceholder(accessRef)
+			}
+
+			if node.Type == domain.WorkflowNodeTypeSubWorkflow {
+				subWorkflowId := node.Data.Config.AsSubWorkflow().WorkflowId
+				if subWorkflowId == "" {
+					return
+				}
+
+				subWorkflowRef, ok := workflowRefs[subWorkflowId]
+				if !ok {
+					subWorkflowRef = fmt.Sprintf("workflow%d", len(workflowRefs)+1)
+					workflowRefs[subWorkflowId] = subWorkflowRef
+					workflowQueue = append(workflowQueue, subWorkflowId)
+				}
+
+				node.Data.Config["workflowId"] = domain.NewWorkflowBundleWorkflowPlaceholder(subWorkflowRef)
+			}
+		})
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+
+		bundle.Workflows = append(bundle.Workflows, &domain.WorkflowBundleWorkflow{
+			Ref:               workflowRefs[workflow.Id],
+			Name:              workflow.Name,
+			Description:       workflow.Description,
+			Trigger:           workflow.Trigger,
+			TriggerCron:       workflow.TriggerCron,
+			TriggerEvent:      workflow.TriggerEvent,
+			Priority:          workflow.Priority,
+			MaxConcurrentRuns: workflow.MaxConcurrentRuns,
+			Graph:             graph,
+		})
+	}
+
+	var content []byte
+	var err error
+	switch format {
+	case domain.WorkflowBundleFormatYAML:
+		content, err = yaml.Marshal(bundle)
+	case domain.WorkflowBundleFormatJSON:
+		content, err = json.MarshalIndent(bundle, "", "  ")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workflow bundle: %w", err)
+	}
+
+	return &dtos.WorkflowExportBundleResp{
+		Format:  format,
+		Content: string(content),
+	}, nil
+}
+
+func (s *WorkflowService) ImportBundle(ctx context.Context, req *dtos.WorkflowImportBundleReq) (*dtos.WorkflowImportBundleResp, error) {
+	// YAML 是 JSON 的超集，因此无需区分格式
+	bundle := &domain.WorkflowBundle{}
+	if err := yaml.Unmarshal([]byte(req.Content), bundle); err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("invalid workflow bundle: %s", err.Error()))
+	} else if bundle.Version != domain.WorkflowBundleVersion {
+		return nil, domain.NewError(400, fmt.Sprintf("unsupported workflow bundle version '%s'", bundle.Version))
+	} else if len(bundle.Workflows) == 0 {
+		return nil, domain.NewError(400, "invalid workflow bundle: no workflows")
+	}
+
+	// 先校验导出包，校验通过后再写入，以免导入部分工作流
+	accessIds, err := s.verifyBundle(ctx, bundle, req.AccessMappings)
+	if err != nil {
+		return nil, domain.NewError(400, err.Error())
+	}
+
+	// 在同一事务中写入全部工作流及其版本记录，任一失败时全部回滚
+	resp := &dtos.WorkflowImportBundleResp{
+		Workflows: make([]*dtos.WorkflowImportBundleRespItem, 0),
+	}
+	err = repository.RunInTransaction(ctx, func(txCtx context.Context) error {
+		// 先创建工作流记录，以便将子工作流占位符替换为新的工作流 ID
+		workflows := make(map[string]*domain.Workflow) // Key: WorkflowRef
+		for _, bundleWorkflow := range bundle.Workflows {
+			workflow := &domain.Workflow{
+				Name:              bundleWorkflow.Name,
+				Description:       bundleWorkflow.Description,
+				Trigger:           bundleWorkflow.Trigger,
+				TriggerCron:       bundleWorkflow.TriggerCron,
+				TriggerEvent:      bundleWorkflow.TriggerEvent,
+				Priority:          bundleWorkflow.Priority,
+				MaxConcurrentRuns: bundleWorkflow.MaxConcurrentRuns,
+				Enabled:           false, // 导入的工作流默认不启用，需用户确认后手动启用
+			}
+			if workflow, err = s.workflowRepo.Save(txCtx, workflow); err != nil {
+				return err
+			}
+
+			workflows[bundleWorkflow.Ref] = workflow
+		}
+
+		for _, bundleWorkflow := range bundle.Workflows {
+			workflow := workflows[bundleWorkflow.Ref]
+
+			graph := bundleWorkflow.Graph
+			replaceBundlePlaceholders(graph, accessIds, lo.MapValues(workflows, func(w *domain.Workflow, _ string) string { return w.Id }))
+
+			workflow.GraphDraft = graph
+			workflow.GraphContent = graph
+			workflow.HasDraft = false
+			workflow.HasContent = true
+			if _, err := s.workflowRepo.Save(txCtx, workflow); err != nil {
+				return err
+			}
+
+			if _, err := s.recordVersion(txCtx, workflow.Id, req.Author, "imported from bundle"); err != nil {
+				return err
+			}
+
+			resp.Workflows = append(resp.Workflows, &dtos.WorkflowImportBundleRespItem{
+				Ref:  bundleWorkflow.Ref,
+				Id:   workflow.Id,
+				Name: workflow.Name,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// 校验导出包中的工作流及占位符，并返回授权记录占位符到本地授权记录 ID 的映射。
+func (s *WorkflowService) verifyBundle(ctx context.Context, bundle *domain.WorkflowBundle, accessMappings map[string]string) (map[string]string, error) {
+	var errs []error
+
+	bundleAccesses := make(map[string]*domain.WorkflowBundleAccess) // Key: AccessRef
+	for _, bundleAccess := range bundle.Accesses {
+		if _, ok := bundleAccesses[bundleAccess.Ref]; ok {
+			errs = append(errs, fmt.Errorf("duplicate access ref '%s'", bundleAccess.Ref))
+		}
+		bundleAccesses[bundleAccess.Ref] = bundleAccess
+	}
+
+	bundleWorkflows := make(map[string]*domain.WorkflowBundleWorkflow) // Key: WorkflowRef
+	for _, bundleWorkflow := range bundle.Workflows {
+		if _, ok := bundleWorkflows[bundleWorkflow.Ref]; ok {
+			errs = append(errs, fmt.Errorf("duplicate workflow ref '%s'", bundleWorkflow.Ref))
+		}
+		bundleWorkflows[bundleWorkflow.Ref] = bundleWorkflow
+	}
+
+	accessIds := make(map[string]string) // Key: AccessRef
+	for _, bundleWorkflow := range bundle.Workflows {
+		if bundleWorkflow.Graph == nil {
+			errs = append(errs, fmt.Errorf("workflow '%s': graph is empty", bundleWorkflow.Ref))
+			continue
+		}
+
+		walkWorkflowNodes(bundleWorkflow.Graph.Nodes, func(node *domain.WorkflowNode) {
+			for key, value := range node.Data.Config {
+				str, _ := value.(string)
+
+				if accessRef, ok := domain.ParseWorkflowBundleAccessPlaceholder(str); ok {
+					if _, ok := accessIds[accessRef]; ok {
+						continue
+					}
+
+					bundleAccess, ok := bundleAccesses[accessRef]
+					if !ok {
+						errs = append(errs, fmt.Errorf("workflow '%s': node #%s references undeclared access '%s'", bundleWorkflow.Ref, node.Id, accessRef))
+						continue
+					}
+
+					accessId := accessMappings[accessRef]
+					if accessId == "" {
+						errs = append(errs, fmt.Errorf("access '%s' (name: '%s', provider: '%s') is not mapped to a local access", accessRef, bundleAccess.Name, bundleAccess.Provider))
+						accessIds[accessRef] = ""
+						continue
+					}
+
+					access, err := s.accessRepo.GetById(ctx, accessId)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("access '%s' is mapped to access #%s, which could not be found: %w", accessRef, accessId, err))
+					} else if access.Provider != bundleAccess.Provider {
+						errs = append(errs, fmt.Errorf("access '%s' requires provider '%s', but access #%s is of provider '%s'", accessRef, bundleAccess.Provider, accessId, access.Provider))
+					}
+					accessIds[accessRef] = accessId
+				} else if workflowRef, ok := domain.ParseWorkflowBundleWorkflowPlaceholder(str); ok {
+					if _, ok := bundleWorkflows[workflowRef]; !ok {
+						errs = append(errs, fmt.Errorf("workflow '%s': node #%s references undeclared workflow '%s'", bundleWorkflow.Ref, node.Id, workflowRef))
+					}
+				} else if strings.HasSuffix(key, "AccessId") && str != "" {
+					// 授权记录 ID 在不同实例间不通用，必须以占位符表示
+					errs = append(errs, fmt.Errorf("workflow '%s': node #%s field '%s' must be an access placeholder", bundleWorkflow.Ref, node.Id, key))
+				}
+			}
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// 将授权记录占位符替换为本地授权记录后，再进行完整的静态校验；子工作流尚未创建，保留其占位符
+	workflowPlaceholders := lo.SliceToMap(bundle.Workflows, func(w *domain.WorkflowBundleWorkflow) (string, string) {
+		return w.Ref, domain.NewWorkflowBundleWorkflowPlaceholder(w.Ref)
+	})
+	for _, bundleWorkflow := range bundle.Workflows {
+		graph, err := cloneWorkflowGraph(bundleWorkflow.Graph)
+		if err != nil {
+			return nil, err
+		}
+		replaceBundlePlaceholders(graph, accessIds, workflowPlaceholders)
+
+		workflow := &domain.Workflow{
+			Trigger:      bundleWorkflow.Trigger,
+			TriggerCron:  bundleWorkflow.TriggerCron,
+			TriggerEvent: bundleWorkflow.TriggerEvent,
+		}
+		if err := s.validateGraph(ctx, workflow, graph).Err(); err != nil {
+			errs = append(errs, fmt.Errorf("workflow '%s': %w", bundleWorkflow.Ref, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return accessIds, nil
+}
+
+// 将流程图中的占位符替换为本地的授权记录 ID 和工作流 ID。
+func replaceBundlePlaceholders(graph *domain.WorkflowGraph, accessIds map[string]string, workflowIds map[string]string) {
+	walkWorkflowNodes(graph.Nodes, func(node *domain.WorkflowNode) {
+		for key, value := range node.Data.Config {
+			str, _ := value.(string)
+			if accessRef, ok := domain.ParseWorkflowBundleAccessPlaceholder(str); ok {
+				node.Data.Config[key] = accessIds[accessRef]
+			} else if workflowRef, ok := domain.ParseWorkflowBundleWorkflowPlaceholder(str); ok {
+				node.Data.Config[key] = workflowIds[workflowRef]
+			}
+		}
+	})
+}
+
+func walkWorkflowNodes(nodes []*domain.WorkflowNode, fn func(node *domain.WorkflowNode)) {
+	for _, node := range nodes {
+		fn(node)
+		walkWorkflowNodes(node.Blocks, fn)
+	}
+}
+
+func cloneWorkflowGraph(graph *domain.WorkflowGraph) (*domain.WorkflowGraph, error) {
+	data, err := json.Marshal(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &domain.WorkflowGraph{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
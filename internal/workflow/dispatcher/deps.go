@@ -12,9 +12,12 @@ type workflowRepository interface {
 }
 
 type workflowRunRepository interface {
+	ListByStatus(ctx context.Context, status domain.WorkflowRunStatusType) ([]*domain.WorkflowRun, error)
 	GetById(ctx context.Context, id string) (*domain.WorkflowRun, error)
 	Save(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
 	SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
+	ClaimPending(ctx context.Context, id string, instanceId string, maxConcurrentRuns int) (bool, error)
+	ClaimInterrupted(ctx context.Context, id string, fromInstanceId string, toInstanceId string) (bool, error)
 }
 
 type workflowLogRepository interface {
@@ -9,12 +9,15 @@ import (
 	"os"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/cluster"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/eventbus"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/workflow/engine"
 	"github.com/certimate-go/certimate/pkg/logging"
@@ -22,9 +25,26 @@ import (
 
 var maxWorkers = 1
 
+const (
+	InterruptedPolicyRequeue = "requeue"
+	InterruptedPolicyFail    = "fail"
+)
+
+// 服务重启前仍在执行中的运行的处理策略：
+//   - "requeue"：重新放入等待队列，从头开始执行；
+//   - "fail"：标记为失败，可通过恢复运行从失败节点继续执行。
+var interruptedPolicy = InterruptedPolicyFail
+
+func init() {
+	envInterruptedPolicy := os.Getenv("CERTIMATE_WORKFLOW_INTERRUPTED_POLICY")
+	if envInterruptedPolicy == InterruptedPolicyRequeue {
+		interruptedPolicy = InterruptedPolicyRequeue
+	}
+}
+
 func init() {
 	envMaxWorkers := os.Getenv("CERTIMATE_WORKFLOW_MAX_WORKERS")
-	if n, err := strconv.Atoi(envMaxWorkers); err != nil && n > 0 {
+	if n, err := strconv.Atoi(envMaxWorkers); err == nil && n > 0 {
 		maxWorkers = n
 	} else {
 		maxWorkers = runtime.GOMAXPROCS(0)
@@ -44,9 +64,16 @@ type WorkflowDispatcher interface {
 }
 
 type Statistics struct {
-	Concurrency      int
-	PendingRunIds    []string
-	ProcessingRunIds []string
+	Concurrency         int
+	ProviderConcurrency map[string]int
+	PendingRunIds       []string
+	PendingRunGroups    []StatisticsPendingRunGroup
+	ProcessingRunIds    []string
+}
+
+type StatisticsPendingRunGroup struct {
+	Priority int32
+	RunIds   []string
 }
 
 type workflowDispatcher struct {
@@ -54,13 +81,18 @@ type workflowDispatcher struct {
 	concurrency int
 
 	taskMtx         sync.RWMutex
-	pendingRunQueue []string
+	pendingRunQueue []*pendingTaskInfo   // 按优先级排序
 	processingTasks map[string]*taskInfo // Key: RunId
 
+	wakeupCh chan struct{} // 唤醒派发协程
+
 	workflowRepo    workflowRepository
 	workflowRunRepo workflowRunRepository
 	workflowLogRepo workflowLogRepository
 
+	cluster  cluster.Cluster
+	eventBus eventbus.EventBus
+
 	syslog *slog.Logger
 }
 
@@ -71,12 +103,21 @@ func (wd *workflowDispatcher) GetStatistics() Statistics {
 	defer wd.taskMtx.RUnlock()
 
 	stats := Statistics{
-		Concurrency:      wd.concurrency,
-		PendingRunIds:    make([]string, 0),
-		ProcessingRunIds: make([]string, 0),
+		Concurrency:         wd.concurrency,
+		ProviderConcurrency: engine.GetProviderConcurrency(),
+		PendingRunIds:       make([]string, 0),
+		PendingRunGroups:    make([]StatisticsPendingRunGroup, 0),
+		ProcessingRunIds:    make([]string, 0),
 	}
-	for _, pendingRunId := range wd.pendingRunQueue {
-		stats.PendingRunIds = append(stats.PendingRunIds, pendingRunId)
+	for _, pendingTask := range wd.pendingRunQueue {
+		stats.PendingRunIds = append(stats.PendingRunIds, pendingTask.RunId)
+
+		// 等待队列已按优先级排序，相同优先级的任务必然相邻
+		if len(stats.PendingRunGroups) == 0 || stats.PendingRunGroups[len(stats.PendingRunGroups)-1].Priority != pendingTask.Priority {
+			stats.PendingRunGroups = append(stats.PendingRunGroups, StatisticsPendingRunGroup{Priority: pendingTask.Priority, RunIds: make([]string, 0)})
+		}
+		group := &stats.PendingRunGroups[len(stats.PendingRunGroups)-1]
+		group.RunIds = append(group.RunIds, pendingTask.RunId)
 	}
 	for _, processingRunId := range wd.processingTasks {
 		stats.ProcessingRunIds = append(stats.ProcessingRunIds, processingRunId.RunId)
@@ -93,15 +134,24 @@ func (wd *workflowDispatcher) Bootup(ctx context.Context) error {
 	wd.taskMtx.Lock()
 	defer wd.taskMtx.Unlock()
 
-	if _, err := app.GetDB().NewQuery("UPDATE workflow SET lastRunStatus = 'canceled' WHERE lastRunStatus = 'pending' OR lastRunStatus = 'processing'").Execute(); err != nil {
+	// 等待队列持久化在数据库中，即状态为 pending 的运行记录；
+	// 重启前仍在执行中的运行，按策略重新入队或标记为失败
+	if err := wd.recoverInterruptedRuns(ctx); err != nil {
 		return err
 	}
-	if _, err := app.GetDB().NewQuery("UPDATE workflow_run SET status = 'canceled' WHERE status = 'pending' OR status = 'processing'").Execute(); err != nil {
+	if err := wd.loadPendingRuns(ctx); err != nil {
 		return err
 	}
 
+	// 多实例模式下，需定期接管已失效实例中断的运行，并同步其他实例创建的等待中的运行
+	if wd.cluster.IsHAEnabled() {
+		wd.cluster.OnHeartbeat(wd.onClusterHeartbeat)
+	}
+
 	wd.booted = true
 
+	wd.tryNextAsync()
+
 	return nil
 }
 
@@ -114,17 +164,23 @@ func (wd *workflowDispatcher) Shutdown(ctx context.Context) error {
 	defer wd.taskMtx.Unlock()
 
 	for runId, task := range wd.processingTasks {
+		task.interrupted.Store(true)
 		task.cancel()
 		delete(wd.processingTasks, runId)
 	}
 
 	wd.booted = false
-	wd.pendingRunQueue = make([]string, 0)
+	wd.pendingRunQueue = make([]*pendingTaskInfo, 0)
 	wd.processingTasks = make(map[string]*taskInfo)
 	return nil
 }
 
 func (wd *workflowDispatcher) Start(ctx context.Context, runId string) error {
+	workflowRun, err := wd.workflowRunRepo.GetById(ctx, runId)
+	if err != nil {
+		return err
+	}
+
 	wd.taskMtx.Lock()
 	defer wd.taskMtx.Unlock()
 
@@ -132,14 +188,12 @@ func (wd *workflowDispatcher) Start(ctx context.Context, runId string) error {
 		return fmt.Errorf("workflow run %s is already processing", runId)
 	}
 
-	for _, pendingRunId := range wd.pendingRunQueue {
-		if pendingRunId == runId {
-			return fmt.Errorf("workflow run %s is already in the queue", runId)
-		}
+	if wd.isPending(runId) {
+		return fmt.Errorf("workflow run %s is already in the queue", runId)
 	}
 
-	wd.pendingRunQueue = append(wd.pendingRunQueue, runId)
-	go func() { wd.tryNextAsync() }()
+	wd.enqueue(workflowRun)
+	wd.tryNextAsync()
 
 	return nil
 }
@@ -151,7 +205,7 @@ func (wd *workflowDispatcher) Cancel(ctx context.Context, runId string) error {
 	workflowRun, err := wd.workflowRunRepo.GetById(ctx, runId)
 	if err != nil {
 		return err
-	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing {
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing && workflowRun.Status != domain.WorkflowRunStatusTypeWaiting {
 		return fmt.Errorf("workflow run #%s is already completed", workflowRun.Id)
 	}
 
@@ -180,14 +234,9 @@ func (wd *workflowDispatcher) Cancel(ctx context.Context, runId string) error {
 		wd.syslog.Info(fmt.Sprintf("workflow run #%s was canceled", task.RunId))
 	}
 
-	for i, pendingRunId := range wd.pendingRunQueue {
-		if pendingRunId == runId {
-			wd.pendingRunQueue = append(wd.pendingRunQueue[:i], wd.pendingRunQueue[i+1:]...)
-			break
-		}
-	}
+	wd.dequeue(runId)
 
-	go func() { wd.tryNextAsync() }()
+	wd.tryNextAsync()
 
 	return nil
 }
@@ -216,7 +265,12 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 
 	// 尝试继续执行等待队列中的任务
 	defer func() {
-		delete(wd.processingTasks, task.RunId)
+		wd.taskMtx.Lock()
+		if wd.processingTasks[task.RunId] == task {
+			delete(wd.processingTasks, task.RunId)
+		}
+		wd.taskMtx.Unlock()
+
 		wd.tryNextAsync()
 	}()
 
@@ -227,8 +281,8 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		}
 		return
 	} else {
-		if workflowRun.Status == domain.WorkflowRunStatusTypePending {
-			workflowRun.Status = domain.WorkflowRunStatusTypeProcessing
+		if workflowRun.Status == domain.WorkflowRunStatusTypeProcessing && workflowRun.ClaimedBy == wd.cluster.InstanceId() {
+			// 运行已在派发时认领，此处仅级联更新所属工作流的状态
 			wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
 		} else {
 			// WTF? That should be impossible!
@@ -243,11 +297,30 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		return
 	}
 
+	// 从失败的运行中恢复时，读取其状态快照；从等待中继续执行时，使用自身的状态快照
+	var resumeFrom *engine.Snapshot
+	if workflowRun.Snapshot != nil && workflowRun.Snapshot.WaitingNodeId != "" {
+		resumeFrom = workflowRun.Snapshot
+	} else if workflowRun.ResumedFromId != "" {
+		resumedFromRun, err := wd.workflowRunRepo.GetById(task.ctx, workflowRun.ResumedFromId)
+		if err != nil {
+			wd.syslog.Error(fmt.Sprintf("failed to get workflow run #%s record", workflowRun.ResumedFromId), slog.Any("error", err))
+			return
+		}
+
+		resumeFrom = resumedFromRun.Snapshot
+	}
+
 	// 初始化工作流引擎
 	logsBuf := make(domain.WorkflowLogs, 0)
+	logsMtx := &sync.Mutex{} // 并行分支中的节点可能会同时写入日志
 	we := engine.NewWorkflowEngine()
 	we.OnEnd(func(ctx context.Context) error {
-		if errmsg := logsBuf.ErrorString(); errmsg == "" {
+		logsMtx.Lock()
+		errmsg := logsBuf.ErrorString()
+		logsMtx.Unlock()
+
+		if errmsg == "" {
 			workflowRun.Status = domain.WorkflowRunStatusTypeSucceeded
 			workflowRun.EndedAt = time.Now()
 		} else {
@@ -256,10 +329,24 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 			workflowRun.Error = errmsg
 		}
 		wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
+		wd.publishRunCompletedEvent(task.ctx, workflow, workflowRun)
 		return nil
 	})
 	we.OnError(func(ctx context.Context, err error) error {
+		if errors.Is(err, engine.ErrSuspended) {
+			// 暂停执行时释放工作协程，待外部操作后重新入队并从等待节点继续执行
+			workflowRun.Status = domain.WorkflowRunStatusTypeWaiting
+			wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
+			wd.syslog.Info(fmt.Sprintf("workflow run #%s is waiting at node #%s", task.RunId, workflowRun.Snapshot.WaitingNodeId))
+			return nil
+		}
+
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// 因调度器关闭而中断时，保留执行中状态，待下次启动时按策略处理
+			if task.interrupted.Load() {
+				return nil
+			}
+
 			workflowRun.Status = domain.WorkflowRunStatusTypeCanceled
 			wd.workflowRunRepo.SaveWithCascading(context.Background(), workflowRun)
 		} else {
@@ -267,24 +354,36 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 			workflowRun.EndedAt = time.Now()
 			workflowRun.Error = err.Error()
 			wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
+			wd.publishRunCompletedEvent(task.ctx, workflow, workflowRun)
 		}
 		return nil
 	})
+	we.OnSnapshot(func(ctx context.Context, snapshot *engine.Snapshot) error {
+		workflowRun.Snapshot = snapshot
+		return nil
+	})
+	we.OnPlan(func(ctx context.Context, plan *engine.Plan) error {
+		workflowRun.Plan = plan
+		return nil
+	})
 	we.OnNodeError(func(ctx context.Context, node *engine.Node, err error) error {
 		if errors.Is(err, engine.ErrTerminated) || errors.Is(err, engine.ErrBlocksException) {
 			return nil
 		}
 
 		log := domain.WorkflowLog{}
-		log.WorkflowId = task.WorkflowId
-		log.RunId = task.RunId
+		log.WorkflowId, log.RunId = wd.resolveLogRun(ctx, task)
 		log.NodeId = node.Id
 		log.NodeName = node.Data.Name
 		log.Timestamp = time.Now().UnixMilli()
 		log.Level = int32(slog.LevelError)
 		log.Message = err.Error()
 		log.CreatedAt = time.Now()
-		logsBuf = append(logsBuf, log)
+		if log.RunId == task.RunId { // 仅当前运行的日志参与判定运行结果
+			logsMtx.Lock()
+			logsBuf = append(logsBuf, log)
+			logsMtx.Unlock()
+		}
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -294,8 +393,7 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 	})
 	we.OnNodeLogging(func(ctx context.Context, node *engine.Node, record logging.Record) error {
 		log := domain.WorkflowLog{}
-		log.WorkflowId = task.WorkflowId
-		log.RunId = task.RunId
+		log.WorkflowId, log.RunId = wd.resolveLogRun(ctx, task)
 		log.NodeId = node.Id
 		log.NodeName = node.Data.Name
 		log.Timestamp = record.Time.UnixMilli()
@@ -303,7 +401,11 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		log.Message = record.Message
 		log.Data = record.Data()
 		log.CreatedAt = time.Now()
-		logsBuf = append(logsBuf, log)
+		if log.RunId == task.RunId { // 仅当前运行的日志参与判定运行结果
+			logsMtx.Lock()
+			logsBuf = append(logsBuf, log)
+			logsMtx.Unlock()
+		}
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -319,62 +421,309 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		WorkflowName: workflow.Name,
 		RunId:        workflowRun.Id,
 		RunTrigger:   workflowRun.Trigger,
+		RunPayload:   workflowRun.TriggerPayload,
+		TriggerChain: workflowRun.GetTriggerChain(),
 		Graph:        workflowRun.Graph,
+		ResumeFrom:   resumeFrom,
+		DryRun:       workflowRun.DryRun,
 	})
 	wd.syslog.Info(fmt.Sprintf("workflow run #%s (work#%s) stopped", task.RunId, task.WorkflowId))
 }
 
+// 接管中断的运行，即由已失效实例认领的执行中的运行。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) recoverInterruptedRuns(ctx context.Context) error {
+	processingRuns, err := wd.workflowRunRepo.ListByStatus(ctx, domain.WorkflowRunStatusTypeProcessing)
+	if err != nil {
+		return err
+	}
+
+	aliveInstanceIds, err := wd.cluster.GetAliveInstanceIds(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, workflowRun := range processingRuns {
+		if slices.Contains(aliveInstanceIds, workflowRun.ClaimedBy) {
+			continue
+		}
+
+		// 多个实例可能同时尝试接管，仅接管成功的实例继续处理
+		if claimed, err := wd.workflowRunRepo.ClaimInterrupted(ctx, workflowRun.Id, workflowRun.ClaimedBy, wd.cluster.InstanceId()); err != nil {
+			return err
+		} else if !claimed {
+			continue
+		}
+
+		// 子工作流的运行记录随父运行一同执行，不单独入队
+		if interruptedPolicy == InterruptedPolicyRequeue && workflowRun.ParentRunId == "" {
+			workflowRun.Status = domain.WorkflowRunStatusTypePending
+			workflowRun.ClaimedBy = ""
+			wd.enqueue(workflowRun)
+			wd.syslog.Info(fmt.Sprintf("workflow run #%s was interrupted, re-enqueue it", workflowRun.Id))
+		} else {
+			workflowRun.Status = domain.WorkflowRunStatusTypeFailed
+			workflowRun.EndedAt = time.Now()
+			workflowRun.Error = "the workflow run was interrupted by the server restart"
+			workflowRun.ClaimedBy = wd.cluster.InstanceId()
+			wd.syslog.Warn(fmt.Sprintf("workflow run #%s was interrupted", workflowRun.Id))
+		}
+
+		if _, err := wd.workflowRunRepo.SaveWithCascading(ctx, workflowRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 将数据库中等待中的运行加入等待队列。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) loadPendingRuns(ctx context.Context) error {
+	pendingRuns, err := wd.workflowRunRepo.ListByStatus(ctx, domain.WorkflowRunStatusTypePending)
+	if err != nil {
+		return err
+	}
+
+	for _, workflowRun := range pendingRuns {
+		if _, exists := wd.processingTasks[workflowRun.Id]; exists {
+			continue
+		}
+
+		if !wd.isPending(workflowRun.Id) {
+			wd.enqueue(workflowRun)
+		}
+	}
+
+	return nil
+}
+
+func (wd *workflowDispatcher) onClusterHeartbeat(ctx context.Context) {
+	wd.taskMtx.Lock()
+	defer wd.taskMtx.Unlock()
+
+	if !wd.booted {
+		return
+	}
+
+	if err := wd.recoverInterruptedRuns(ctx); err != nil {
+		wd.syslog.Error("failed to recover interrupted workflow runs", slog.Any("error", err))
+	}
+	if err := wd.loadPendingRuns(ctx); err != nil {
+		wd.syslog.Error("failed to load pending workflow runs", slog.Any("error", err))
+	}
+
+	// 运行可能已在其他实例上被取消，或因本实例心跳超时而被其他实例接管
+	for runId, task := range wd.processingTasks {
+		workflowRun, err := wd.workflowRunRepo.GetById(ctx, runId)
+		if err != nil {
+			continue
+		}
+
+		if workflowRun.Status == domain.WorkflowRunStatusTypeCanceled {
+			task.cancel()
+			delete(wd.processingTasks, runId)
+			wd.syslog.Info(fmt.Sprintf("workflow run #%s was canceled", task.RunId))
+		} else if workflowRun.ClaimedBy != wd.cluster.InstanceId() {
+			task.interrupted.Store(true)
+			task.cancel()
+			delete(wd.processingTasks, runId)
+			wd.syslog.Warn(fmt.Sprintf("workflow run #%s was taken over by instance #%s", task.RunId, workflowRun.ClaimedBy))
+		}
+	}
+
+	wd.tryNextAsync()
+}
+
+// 获取节点日志所属的工作流运行，子工作流中的节点日志归属于子工作流的运行。
+func (wd *workflowDispatcher) resolveLogRun(ctx context.Context, task *taskInfo) (workflowId string, runId string) {
+	if workflowId, runId, ok := engine.GetExecutingRun(ctx); ok && runId != "" {
+		return workflowId, runId
+	}
+	return task.WorkflowId, task.RunId
+}
+
+func (wd *workflowDispatcher) publishRunCompletedEvent(ctx context.Context, workflow *domain.Workflow, workflowRun *domain.WorkflowRun) {
+	// 试运行不发布事件，以免触发其他工作流
+	if workflowRun.DryRun {
+		return
+	}
+
+	switch workflowRun.Status {
+	case domain.WorkflowRunStatusTypeSucceeded:
+		wd.eventBus.Publish(ctx, domain.NewWorkflowRunEvent(domain.EventTypeWorkflowRunSucceeded, workflow, workflowRun))
+	case domain.WorkflowRunStatusTypeFailed:
+		wd.eventBus.Publish(ctx, domain.NewWorkflowRunEvent(domain.EventTypeWorkflowRunFailed, workflow, workflowRun))
+	}
+}
+
+// 唤醒派发协程。派发仅在单一协程中串行进行，以免多个协程同时通过并发数检查后超额派发。
 func (wd *workflowDispatcher) tryNextAsync() {
+	select {
+	case wd.wakeupCh <- struct{}{}:
+	default:
+	}
+}
+
+func (wd *workflowDispatcher) dispatchLoop() {
+	for range wd.wakeupCh {
+		for wd.dispatchNext() {
+		}
+	}
+}
+
+// 派发等待队列中首个满足并发限制的任务，返回是否已派发。
+// 查询或认领运行时不持有 taskMtx，因此在持有写锁后需再次检查各项并发限制，并预占并发名额。
+func (wd *workflowDispatcher) dispatchNext() bool {
 	wd.taskMtx.RLock()
+	if !wd.booted {
+		wd.taskMtx.RUnlock()
+		return false
+	}
+	if wd.isConcurrencyExceeded() {
+		if len(wd.pendingRunQueue) > 0 {
+			wd.syslog.Warn(fmt.Sprintf("%d workflow run(s) are pending, because the maximum concurrency (limit: %d) has been reached", len(wd.pendingRunQueue), wd.concurrency))
+		}
 
-	for i, pendingRunId := range wd.pendingRunQueue {
-		workflowRun, err := wd.workflowRunRepo.GetById(context.Background(), pendingRunId)
+		wd.taskMtx.RUnlock()
+		return false
+	}
+	pendingTasks := slices.Clone(wd.pendingRunQueue)
+	wd.taskMtx.RUnlock()
+
+	// 按优先级依次尝试，排在前面的任务受限时，允许派发后面的任务
+	for _, pendingTask := range pendingTasks {
+		pendingRunId := pendingTask.RunId
+
+		workflow, err := wd.workflowRepo.GetById(context.Background(), pendingTask.WorkflowId)
 		if err != nil {
-			wd.syslog.Error(fmt.Sprintf("failed to get workflow run #%s record", pendingRunId), slog.Any("error", err))
+			wd.syslog.Error(fmt.Sprintf("failed to get workflow #%s record", pendingTask.WorkflowId), slog.Any("error", err))
 			continue
 		}
 
-		var hasSameWorkflowTask bool // 相同 Workflow 的任务同一时间只能有一个 Run 在执行
-		for _, task := range wd.processingTasks {
-			if task.WorkflowId == workflowRun.WorkflowId {
-				hasSameWorkflowTask = true
-				break
-			}
+		wd.taskMtx.Lock()
+		if !wd.booted || wd.isConcurrencyExceeded() {
+			wd.taskMtx.Unlock()
+			return false
+		}
+		if !wd.isPending(pendingRunId) {
+			wd.taskMtx.Unlock()
+			continue
+		}
+		if reason := wd.checkRunConcurrency(pendingTask, workflow); reason != "" {
+			wd.taskMtx.Unlock()
+			wd.syslog.Warn(fmt.Sprintf("workflow run #%s is pending, because %s", pendingRunId, reason))
+			continue
 		}
 
-		if hasSameWorkflowTask {
-			wd.syslog.Warn(fmt.Sprintf("workflow run #%s is pending, because tasks that belonging to the same workflow #%s already exists", workflowRun.Id, workflowRun.WorkflowId))
-		} else if len(wd.processingTasks) >= wd.concurrency && wd.concurrency > 0 {
-			wd.syslog.Warn(fmt.Sprintf("workflow run #%s is pending, because the maximum concurrency (limit: %d) has been reached", pendingRunId, wd.concurrency))
-		} else {
-			wd.taskMtx.RUnlock()
+		ctxRun, ctxCancel := context.WithCancel(context.Background())
+		task := &taskInfo{WorkflowId: pendingTask.WorkflowId, RunId: pendingRunId, ctx: ctxRun, cancel: ctxCancel}
+		wd.dequeue(pendingRunId)
+		wd.processingTasks[pendingRunId] = task
+		wd.taskMtx.Unlock()
+
+		// 认领该运行，多实例模式下其可能已被其他实例认领
+		claimed, err := wd.workflowRunRepo.ClaimPending(context.Background(), pendingRunId, wd.cluster.InstanceId(), workflow.GetMaxConcurrentRuns())
+		if err != nil || !claimed {
+			// 仍在等待中的（如相同工作流的其他运行正在其他实例上执行），留在队列中稍后重试
+			requeue := true
+			if err != nil {
+				wd.syslog.Error(fmt.Sprintf("failed to claim workflow run #%s", pendingRunId), slog.Any("error", err))
+			} else if workflowRun, err := wd.workflowRunRepo.GetById(context.Background(), pendingRunId); err != nil || workflowRun.Status != domain.WorkflowRunStatusTypePending {
+				requeue = false
+			}
+
 			wd.taskMtx.Lock()
-			defer wd.taskMtx.Unlock()
-
-			ctxRun, ctxCancel := context.WithCancel(context.Background())
-			task := &taskInfo{WorkflowId: workflowRun.WorkflowId, RunId: workflowRun.Id, ctx: ctxRun, cancel: ctxCancel}
-			wd.pendingRunQueue = append(wd.pendingRunQueue[:i], wd.pendingRunQueue[i+1:]...)
-			wd.processingTasks[pendingRunId] = task
-			wd.syslog.Info(fmt.Sprintf("workflow run #%s (work#%s) is being dispatched ...", task.RunId, task.WorkflowId))
-			go func() { wd.tryExecuteAsync(task) }()
-			return
+			if wd.processingTasks[pendingRunId] == task {
+				delete(wd.processingTasks, pendingRunId)
+				if requeue && wd.booted && !wd.isPending(pendingRunId) {
+					wd.enqueueTask(pendingTask)
+				}
+			}
+			wd.taskMtx.Unlock()
+			ctxCancel()
+			continue
 		}
+
+		// 认领期间运行可能已被取消，或调度器已关闭
+		wd.taskMtx.RLock()
+		dispatched := wd.processingTasks[pendingRunId] == task
+		wd.taskMtx.RUnlock()
+		if !dispatched {
+			ctxCancel()
+			continue
+		}
+
+		wd.syslog.Info(fmt.Sprintf("workflow run #%s (work#%s) is being dispatched ...", task.RunId, task.WorkflowId))
+		go func() { wd.tryExecuteAsync(task) }()
+		return true
 	}
 
-	wd.taskMtx.RUnlock()
+	return false
+}
+
+// 检查全局并发数是否已达上限。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) isConcurrencyExceeded() bool {
+	return wd.concurrency > 0 && len(wd.processingTasks) >= wd.concurrency
+}
+
+// 检查运行是否受工作流的并发限制，返回受限原因。调用方需持有 taskMtx。
+// 提供商的并发限制在执行节点时检查，参见 [engine.GetProviderConcurrency]。
+func (wd *workflowDispatcher) checkRunConcurrency(pendingTask *pendingTaskInfo, workflow *domain.Workflow) string {
+	var sameWorkflowTasks int // 相同 Workflow 同一时间执行的 Run 不能超过其最大并发运行数
+	for _, task := range wd.processingTasks {
+		if task.WorkflowId == pendingTask.WorkflowId {
+			sameWorkflowTasks++
+		}
+	}
+
+	if sameWorkflowTasks >= workflow.GetMaxConcurrentRuns() {
+		return fmt.Sprintf("the maximum concurrent runs (limit: %d) of the same workflow #%s has been reached", workflow.GetMaxConcurrentRuns(), pendingTask.WorkflowId)
+	}
+
+	return ""
+}
+
+// 按优先级将运行插入等待队列，相同优先级的按入队顺序排列。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) enqueue(workflowRun *domain.WorkflowRun) {
+	wd.enqueueTask(newPendingTaskInfo(workflowRun))
+}
+
+// 按优先级将任务插入等待队列。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) enqueueTask(pendingTask *pendingTaskInfo) {
+	index := slices.IndexFunc(wd.pendingRunQueue, func(t *pendingTaskInfo) bool { return pendingTask.precedes(t) })
+	if index < 0 {
+		index = len(wd.pendingRunQueue)
+	}
+	wd.pendingRunQueue = slices.Insert(wd.pendingRunQueue, index, pendingTask)
+}
+
+// 将运行移出等待队列。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) dequeue(runId string) {
+	wd.pendingRunQueue = slices.DeleteFunc(wd.pendingRunQueue, func(t *pendingTaskInfo) bool { return t.RunId == runId })
+}
+
+// 判断运行是否在等待队列中。调用方需持有 taskMtx。
+func (wd *workflowDispatcher) isPending(runId string) bool {
+	return slices.ContainsFunc(wd.pendingRunQueue, func(t *pendingTaskInfo) bool { return t.RunId == runId })
 }
 
 func newWorkflowDispatcher() WorkflowDispatcher {
-	return &workflowDispatcher{
+	wd := &workflowDispatcher{
 		concurrency: maxWorkers,
 
-		pendingRunQueue: make([]string, 0),
+		pendingRunQueue: make([]*pendingTaskInfo, 0),
 		processingTasks: make(map[string]*taskInfo),
 
+		wakeupCh: make(chan struct{}, 1),
+
 		workflowRepo:    repository.NewWorkflowRepository(),
 		workflowRunRepo: repository.NewWorkflowRunRepository(),
 		workflowLogRepo: repository.NewWorkflowLogRepository(),
 
+		cluster:  cluster.GetSingletonCluster(),
+		eventBus: eventbus.GetSingletonEventBus(),
+
 		syslog: app.GetLogger(),
 	}
+	go wd.dispatchLoop()
+	return wd
 }
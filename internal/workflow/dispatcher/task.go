@@ -2,6 +2,9 @@
 
 import (
 	"context"
+	"sync/atomic"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type taskInfo struct {
@@ -10,4 +13,31 @@ type taskInfo struct {
 
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	interrupted atomic.Bool // 是否因调度器关闭而中断，中断的任务将在下次启动时按策略处理
+}
+
+type pendingTaskInfo struct {
+	WorkflowId string
+	RunId      string
+	Priority   int32
+	Manual     bool
+}
+
+// 判断当前任务是否应排在另一任务之前：
+// 优先级高的排在前面；优先级相同时，手动触发的排在前面；其余按入队顺序。
+func (t *pendingTaskInfo) precedes(other *pendingTaskInfo) bool {
+	if t.Priority != other.Priority {
+		return t.Priority > other.Priority
+	}
+	return t.Manual && !other.Manual
+}
+
+func newPendingTaskInfo(workflowRun *domain.WorkflowRun) *pendingTaskInfo {
+	return &pendingTaskInfo{
+		WorkflowId: workflowRun.WorkflowId,
+		RunId:      workflowRun.Id,
+		Priority:   workflowRun.Priority,
+		Manual:     workflowRun.Trigger == domain.WorkflowTriggerTypeManual,
+	}
 }
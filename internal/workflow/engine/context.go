@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"sync"
 )
 
 type WorkflowContext struct {
@@ -13,6 +14,9 @@ type WorkflowContext struct {
 	variables VariableManager
 	inputs    InOutManager
 
+	resume  *resumeState  // 从指定节点恢复运行时的状态，非恢复运行时为 nil
+	planner *planRecorder // 试运行时记录执行计划，非试运行时为 nil
+
 	ctx context.Context
 }
 
@@ -43,6 +47,10 @@ func (c *WorkflowContext) SetContext(ctx context.Context) *WorkflowContext {
 	return c
 }
 
+func (c *WorkflowContext) IsDryRun() bool {
+	return c.planner != nil
+}
+
 func (c *WorkflowContext) Clone() *WorkflowContext {
 	return &WorkflowContext{
 		WorkflowId: c.WorkflowId,
@@ -53,6 +61,40 @@ func (c *WorkflowContext) Clone() *WorkflowContext {
 		variables: c.variables,
 		inputs:    c.inputs,
 
+		resume:  c.resume,
+		planner: c.planner,
+
 		ctx: c.ctx,
 	}
 }
+
+// 返回待恢复的节点 ID；未处于恢复运行中或已到达恢复节点时返回空字符串。
+func (c *WorkflowContext) resumingNodeId() string {
+	if c.resume == nil {
+		return ""
+	}
+
+	c.resume.mtx.Lock()
+	defer c.resume.mtx.Unlock()
+	return c.resume.nodeId
+}
+
+// 标记已到达指定节点。若该节点即为恢复节点，则结束恢复状态，后续节点按正常流程执行。
+func (c *WorkflowContext) reachNode(nodeId string) {
+	if c.resume == nil {
+		return
+	}
+
+	c.resume.mtx.Lock()
+	defer c.resume.mtx.Unlock()
+	if c.resume.nodeId == nodeId {
+		c.resume.nodeId = ""
+	}
+}
+
+// 恢复运行的状态，在同一次运行的所有上下文副本间共享，
+// 以便在任一分支中到达恢复节点后，其他容器节点不再跳过分支。
+type resumeState struct {
+	mtx    sync.Mutex
+	nodeId string
+}
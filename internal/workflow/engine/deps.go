@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/privateca"
 )
 
 type accessRepository interface {
@@ -16,8 +17,13 @@ type certificateRepository interface {
 	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
 }
 
+type workflowRepository interface {
+	GetById(ctx context.Context, workflowId string) (*domain.Workflow, error)
+}
+
 type workflowRunRepository interface {
 	GetById(ctx context.Context, workflowRunId string) (*domain.WorkflowRun, error)
+	Save(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
 }
 
 type workflowOutputRepository interface {
@@ -28,3 +34,11 @@ type workflowOutputRepository interface {
 type settingsRepository interface {
 	GetByName(ctx context.Context, name string) (*domain.Settings, error)
 }
+
+type privateCARepository interface {
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+}
+
+type privateCAIssuer interface {
+	IssueCertificate(ctx context.Context, req *privateca.IssueCertificateRequest) (*privateca.IssueCertificateResponse, error)
+}
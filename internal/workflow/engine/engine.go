@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"runtime/debug"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/samber/lo"
 
@@ -21,7 +24,34 @@ type WorkflowExecution struct {
 	WorkflowName string
 	RunId        string
 	RunTrigger   domain.WorkflowTriggerType
+	RunPayload   map[string]any // 触发器携带的数据，如 Webhook 请求体
+	TriggerChain []string       // 触发本次运行的事件所经过的工作流触发链，运行中产生的事件将携带该触发链
 	Graph        *Graph
+	ResumeFrom   *Snapshot // 从状态快照中恢复，并从失败节点（或等待节点）继续执行
+	DryRun       bool      // 是否试运行，试运行时各节点仅报告执行计划而不产生实际影响
+}
+
+type triggerChainKey struct{}
+
+type executingRunKey struct{}
+
+type executingRun struct {
+	WorkflowId string
+	RunId      string
+}
+
+// 获取上下文中正在执行的工作流运行；子工作流中的节点将返回子工作流及其运行的 ID。
+func GetExecutingRun(ctx context.Context) (workflowId string, runId string, ok bool) {
+	if run, ok := ctx.Value(executingRunKey{}).(executingRun); ok {
+		return run.WorkflowId, run.RunId, true
+	}
+	return "", "", false
+}
+
+// 获取运行中产生的事件所应携带的工作流触发链，即触发本次运行的事件所经过的触发链，再加上当前的子工作流调用栈。
+func getEventChain(ctx context.Context, workflowId string) []string {
+	chain, _ := ctx.Value(triggerChainKey{}).([]string)
+	return append(slices.Clone(chain), getSubWorkflowCallStack(ctx, workflowId)...)
 }
 
 type WorkflowEngine interface {
@@ -30,6 +60,8 @@ type WorkflowEngine interface {
 	OnStart(callback func(ctx context.Context) error)
 	OnEnd(callback func(ctx context.Context) error)
 	OnError(callback func(ctx context.Context, err error) error)
+	OnSnapshot(callback func(ctx context.Context, snapshot *Snapshot) error)
+	OnPlan(callback func(ctx context.Context, plan *Plan) error)
 	OnNodeStart(callback func(ctx context.Context, node *Node) error)
 	OnNodeEnd(callback func(ctx context.Context, node *Node, res *NodeExecutionResult) error)
 	OnNodeError(callback func(ctx context.Context, node *Node, err error) error)
@@ -37,12 +69,14 @@ type WorkflowEngine interface {
 }
 
 type workflowEngine struct {
-	executors map[NodeType]NodeExecutor
+	executors map[NodeType]func() NodeExecutor // 每次执行节点时都会创建新的执行器实例，以便并行分支间互不干扰
 
 	hooksMtx           sync.RWMutex
 	onStartHooks       [](func(ctx context.Context) error)
 	onEndHooks         [](func(ctx context.Context) error)
 	onErrorHooks       [](func(ctx context.Context, err error) error)
+	onSnapshotHooks    [](func(ctx context.Context, snapshot *Snapshot) error)
+	onPlanHooks        [](func(ctx context.Context, plan *Plan) error)
 	onNodeStartHooks   [](func(ctx context.Context, node *Node) error)
 	onNodeEndHooks     [](func(ctx context.Context, node *Node, res *NodeExecutionResult) error)
 	onNodeErrorHooks   [](func(ctx context.Context, node *Node, err error) error)
@@ -64,18 +98,28 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 		}
 	}()
 
+	ctx = context.WithValue(ctx, triggerChainKey{}, execution.TriggerChain)
+	ctx = context.WithValue(ctx, executingRunKey{}, executingRun{WorkflowId: execution.WorkflowId, RunId: execution.RunId})
+
 	we.fireOnStartHooks(ctx)
 
 	wfIOs := newInOutManager()
-
 	wfVars := newVariableManager()
+	if execution.ResumeFrom != nil {
+		restoreSnapshot(execution.ResumeFrom, wfVars, wfIOs)
+	}
+
 	wfVars.Set(stateVarKeyWorkflowId, execution.WorkflowId, "string")
 	wfVars.Set(stateVarKeyWorkflowName, execution.WorkflowName, "string")
 	wfVars.Set(stateVarKeyRunId, execution.RunId, "string")
 	wfVars.Set(stateVarKeyRunTrigger, execution.RunTrigger, "string")
+	wfVars.Set(stateVarKeyWaitingNodeId, "", "string")
 	wfVars.Set(stateVarKeyErrorNodeId, "", "string")
 	wfVars.Set(stateVarKeyErrorNodeName, "", "string")
 	wfVars.Set(stateVarKeyErrorMessage, "", "string")
+	for _, state := range flattenPayloadVariables(stateVarKeyTriggerPrefix, execution.RunPayload) {
+		wfVars.Add(state)
+	}
 
 	wfCtx := (&WorkflowContext{}).
 		SetExecutingWorkflow(execution.WorkflowId, execution.RunId, execution.Graph).
@@ -83,13 +127,30 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 		SetInputsManager(wfIOs).
 		SetVariablesManager(wfVars).
 		SetContext(ctx)
+	if execution.ResumeFrom != nil {
+		wfCtx.resume = &resumeState{nodeId: execution.ResumeFrom.ErrorNodeId}
+		if execution.ResumeFrom.WaitingNodeId != "" {
+			wfCtx.resume.nodeId = execution.ResumeFrom.WaitingNodeId
+		}
+	}
+	if execution.DryRun {
+		wfCtx.planner = newPlanRecorder()
+	}
 	if err := we.executeBlocks(wfCtx, execution.Graph.Nodes); err != nil {
 		if !errors.Is(err, ErrTerminated) {
+			if wfCtx.planner != nil {
+				we.fireOnPlanHooks(ctx, wfCtx.planner.Plan())
+			}
+			we.fireOnSnapshotHooks(ctx, takeSnapshot(wfVars, wfIOs))
 			we.fireOnErrorHooks(ctx, err)
 			return err
 		}
 	}
 
+	if wfCtx.planner != nil {
+		we.fireOnPlanHooks(ctx, wfCtx.planner.Plan())
+	}
+	we.fireOnSnapshotHooks(ctx, takeSnapshot(wfVars, wfIOs))
 	we.fireOnEndHooks(ctx)
 
 	return nil
@@ -113,6 +174,18 @@ func (we *workflowEngine) OnError(callback func(ctx context.Context, err error)
 	we.onErrorHooks = append(we.onErrorHooks, callback)
 }
 
+func (we *workflowEngine) OnSnapshot(callback func(ctx context.Context, snapshot *Snapshot) error) {
+	we.hooksMtx.Lock()
+	defer we.hooksMtx.Unlock()
+	we.onSnapshotHooks = append(we.onSnapshotHooks, callback)
+}
+
+func (we *workflowEngine) OnPlan(callback func(ctx context.Context, plan *Plan) error) {
+	we.hooksMtx.Lock()
+	defer we.hooksMtx.Unlock()
+	we.onPlanHooks = append(we.onPlanHooks, callback)
+}
+
 func (we *workflowEngine) OnNodeStart(callback func(ctx context.Context, node *Node) error) {
 	we.hooksMtx.Lock()
 	defer we.hooksMtx.Unlock()
@@ -138,15 +211,19 @@ func (we *workflowEngine) OnNodeLogging(callback func(ctx context.Context, node
 }
 
 func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error {
-	executor, ok := we.executors[node.Type]
-	if !ok {
+	var executor NodeExecutor
+	var logger *slog.Logger
+	if executorFactory, ok := we.executors[node.Type]; !ok {
 		err := fmt.Errorf("workflow engine: no executor registered for node type: '%s'", node.Type)
 		return err
 	} else {
-		logger := slog.New(logging.NewHookHandler(&logging.HookHandlerOptions{
+		executor = executorFactory()
+
+		logger = slog.New(logging.NewHookHandler(&logging.HookHandlerOptions{
 			Level: slog.LevelDebug,
-			WriteFunc: func(ctx context.Context, record logging.Record) error {
-				we.fireOnNodeLoggingHooks(ctx, node, record)
+			WriteFunc: func(_ context.Context, record logging.Record) error {
+				// 使用工作流上下文，以便钩子区分日志所属的运行（如子工作流的运行）
+				we.fireOnNodeLoggingHooks(wfCtx.ctx, node, record)
 				return nil
 			},
 		}))
@@ -156,15 +233,33 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeId, node.Id, "string")
 	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeName, node.Data.Name, "string")
 
+	// 到达恢复节点后，结束恢复状态
+	wfCtx.reachNode(node.Id)
+
+	execCtx := newNodeExecutionContext(wfCtx, node)
+
 	// 节点已禁用，直接跳过执行
 	if node.Data.Disabled {
+		execCtx.ReportPlan(PlanActionSkip, "the node is disabled", nil)
 		return nil
 	}
 
 	we.fireOnNodeStartHooks(wfCtx.ctx, node)
 
-	execCtx := newNodeExecutionContext(wfCtx, node)
-	execRes, err := executor.Execute(execCtx)
+	// 试运行时先按执行顺序占位，节点执行器可再报告更详细的计划
+	execCtx.ReportPlan(PlanActionExecute, "", nil)
+
+	execRes, err := we.executeNodeWithTimeout(execCtx, executor, logger)
+	if err != nil && errors.Is(err, ErrSuspended) {
+		// 暂停执行不视为节点失败，仅记录暂停节点以便后续继续执行
+		if !errors.Is(err, ErrBlocksException) {
+			wfCtx.variables.Set(stateVarKeyWaitingNodeId, node.Id, "string")
+		}
+		return err
+	}
+	if err != nil && !errors.Is(err, ErrTerminated) && !errors.Is(err, ErrBlocksException) {
+		execCtx.ReportPlan(PlanActionFail, err.Error(), nil)
+	}
 	if err != nil && !errors.Is(err, ErrTerminated) {
 		if !errors.Is(err, ErrBlocksException) {
 			wfCtx.variables.Set(stateVarKeyErrorNodeId, node.Id, "string")
@@ -191,8 +286,9 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 			}
 		}
 
+		// 试运行时不持久化输出，以免影响后续正式运行时的跳过判断
 		execOutputs := lo.Filter(execRes.Outputs, func(state InOutState, _ int) bool { return state.Persistent })
-		if execRes.outputForced || len(execOutputs) > 0 {
+		if (execRes.outputForced || len(execOutputs) > 0) && !execCtx.IsDryRun() {
 			output := &domain.WorkflowOutput{
 				WorkflowId: execCtx.WorkflowId,
 				RunId:      execCtx.RunId,
@@ -227,8 +323,140 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	return nil
 }
 
+// 节点超时后，等待执行器响应上下文取消并退出的时长
+const nodeTimeoutGracePeriod = 3 * time.Second
+
+func (we *workflowEngine) executeNodeWithTimeout(execCtx *NodeExecutionContext, executor NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	timeout := execCtx.Node.Data.Timeout
+	if timeout <= 0 {
+		return we.executeNodeWithRetry(execCtx, executor, logger)
+	}
+
+	parentCtx := execCtx.ctx
+	timeoutCtx, timeoutCancel := context.WithTimeout(parentCtx, time.Duration(timeout)*time.Second)
+	defer timeoutCancel()
+
+	// 节点在独立的状态副本中执行，按时完成后再写回；
+	// 超时后执行器可能仍未退出，其后续产生的变量和输出将随副本一并丢弃，以免影响后续节点
+	nodeVars := newVariableManager()
+	for _, state := range execCtx.variables.All() {
+		nodeVars.Add(state)
+	}
+	nodeIOs := newInOutManager()
+	for _, state := range execCtx.inputs.All() {
+		nodeIOs.Add(state)
+	}
+	nodeCtx := newNodeExecutionContext(&execCtx.WorkflowContext, execCtx.Node).
+		SetVariablesManager(nodeVars).
+		SetInputsManager(nodeIOs).
+		SetContext(timeoutCtx)
+
+	type result struct {
+		res *NodeExecutionResult
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("workflow engine: node panic: %v", r)}
+			}
+		}()
+
+		res, err := we.executeNodeWithRetry(nodeCtx, executor, logger)
+		done <- result{res, err}
+	}()
+
+	// 超时后稍作等待，以便保留响应了上下文取消的执行器的执行结果；
+	// 即使执行器未正确处理上下文取消，也不会一直占用调度器的工作协程
+	var r result
+	select {
+	case r = <-done:
+	case <-timeoutCtx.Done():
+		select {
+		case r = <-done:
+		case <-time.After(nodeTimeoutGracePeriod):
+			if parentCtx.Err() != nil {
+				return nil, parentCtx.Err()
+			}
+
+			logger.Warn(fmt.Sprintf("the node has not completed within %d second(s), abort it", timeout))
+			return nil, fmt.Errorf("%w after %d second(s)", ErrNodeTimedOut, timeout)
+		}
+	}
+
+	execCtx.variables.Erase()
+	for _, state := range nodeVars.All() {
+		execCtx.variables.Add(state)
+	}
+	execCtx.inputs.Erase()
+	for _, state := range nodeIOs.All() {
+		execCtx.inputs.Add(state)
+	}
+
+	if r.err != nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && parentCtx.Err() == nil {
+		// 子节点因超时失败时保留原错误的包装，以免覆盖子节点已记录的失败节点
+		if errors.Is(r.err, ErrBlocksException) {
+			return r.res, fmt.Errorf("%w after %d second(s): %w", ErrNodeTimedOut, timeout, r.err)
+		}
+		return r.res, fmt.Errorf("%w after %d second(s)", ErrNodeTimedOut, timeout)
+	}
+	return r.res, r.err
+}
+
+func (we *workflowEngine) executeNodeWithRetry(execCtx *NodeExecutionContext, executor NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	policy := execCtx.Node.Data.Retry
+	if policy == nil {
+		if e, ok := executor.(withDefaultRetryPolicy); ok {
+			policy = e.DefaultRetryPolicy()
+		}
+	}
+
+	maxAttempts := 1
+	if policy != nil && policy.MaxAttempts > 1 && !execCtx.IsDryRun() {
+		maxAttempts = int(policy.MaxAttempts)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			logger.Info(fmt.Sprintf("retry attempt %d of %d ...", attempt, maxAttempts))
+		}
+
+		execRes, err := we.executeNodeWithProviderLimit(execCtx, executor, logger)
+		if err == nil || attempt >= maxAttempts || !isRetryableError(policy, err) {
+			return execRes, err
+		}
+
+		backoff := computeRetryBackoff(policy, attempt)
+		logger.Warn(fmt.Sprintf("attempt %d of %d failed, will retry in %s: %s", attempt, maxAttempts, backoff.Round(time.Millisecond), err.Error()))
+
+		select {
+		case <-execCtx.ctx.Done():
+			return execRes, execCtx.ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (we *workflowEngine) executeNodeWithProviderLimit(execCtx *NodeExecutionContext, executor NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	// 试运行时不会调用提供商的接口，无需限制
+	if execCtx.IsDryRun() {
+		return executor.Execute(execCtx)
+	}
+
+	release, err := providerLimiter.Acquire(execCtx.ctx, getNodeProviders(execCtx.Node), func(key string, limit int) {
+		logger.Info(fmt.Sprintf("waiting, because the maximum concurrency (limit: %d) of provider '%s' has been reached", limit, key))
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return executor.Execute(execCtx)
+}
+
 func (we *workflowEngine) executeBlocks(wfCtx *WorkflowContext, blocks []*Node) error {
-	for _, node := range blocks {
+	for _, node := range skipBlocksBeforeResumeNode(wfCtx, blocks) {
 		select {
 		case <-wfCtx.ctx.Done():
 			return wfCtx.ctx.Err()
@@ -274,6 +502,26 @@ func (we *workflowEngine) fireOnErrorHooks(ctx context.Context, err error) {
 	}
 }
 
+func (we *workflowEngine) fireOnSnapshotHooks(ctx context.Context, snapshot *Snapshot) {
+	we.hooksMtx.RLock()
+	defer we.hooksMtx.RUnlock()
+	for _, cb := range we.onSnapshotHooks {
+		if cbErr := cb(ctx, snapshot); cbErr != nil {
+			we.syslog.Error("workflow engine: error in onSnapshot hook", slog.Any("error", cbErr))
+		}
+	}
+}
+
+func (we *workflowEngine) fireOnPlanHooks(ctx context.Context, plan *Plan) {
+	we.hooksMtx.RLock()
+	defer we.hooksMtx.RUnlock()
+	for _, cb := range we.onPlanHooks {
+		if cbErr := cb(ctx, plan); cbErr != nil {
+			we.syslog.Error("workflow engine: error in onPlan hook", slog.Any("error", cbErr))
+		}
+	}
+}
+
 func (we *workflowEngine) fireOnNodeStartHooks(ctx context.Context, node *Node) {
 	we.hooksMtx.RLock()
 	defer we.hooksMtx.RUnlock()
@@ -316,22 +564,88 @@ func (we *workflowEngine) fireOnNodeLoggingHooks(ctx context.Context, node *Node
 
 func NewWorkflowEngine() WorkflowEngine {
 	engine := &workflowEngine{
-		executors:    make(map[NodeType]NodeExecutor),
+		executors:    make(map[NodeType]func() NodeExecutor),
 		wfoutputRepo: repository.NewWorkflowOutputRepository(),
 		syslog:       app.GetLogger(),
 	}
-	engine.executors[NodeTypeStart] = newStartNodeExecutor()
-	engine.executors[NodeTypeEnd] = newEndNodeExecutor()
-	engine.executors[NodeTypeDelay] = newDelayNodeExecutor()
-	engine.executors[NodeTypeCondition] = newConditionNodeExecutor()
-	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor()
-	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor()
-	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor()
-	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor()
-	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor()
-	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor()
-	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor()
-	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor()
-	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor()
+	engine.executors[NodeTypeStart] = newStartNodeExecutor
+	engine.executors[NodeTypeEnd] = newEndNodeExecutor
+	engine.executors[NodeTypeDelay] = newDelayNodeExecutor
+	engine.executors[NodeTypeCondition] = newConditionNodeExecutor
+	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor
+	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor
+	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor
+	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor
+	engine.executors[NodeTypeParallel] = newParallelNodeExecutor
+	engine.executors[NodeTypeParallelBlock] = newParallelBlockNodeExecutor
+	engine.executors[NodeTypeSubWorkflow] = newSubWorkflowNodeExecutor
+	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor
+	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor
+	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor
+	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor
+	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor
+	engine.executors[NodeTypeSetVariables] = newSetVariablesNodeExecutor
+	engine.executors[NodeTypeScript] = newScriptNodeExecutor
+	engine.executors[NodeTypeHttpRequest] = newHttpRequestNodeExecutor
+	engine.executors[NodeTypeApproval] = newApprovalNodeExecutor
 	return engine
 }
+
+// 判断节点类型是否已注册执行器。
+func IsNodeTypeSupported(nodeType NodeType) bool {
+	engine := NewWorkflowEngine().(*workflowEngine)
+	_, ok := engine.executors[nodeType]
+	return ok
+}
+
+// 获取节点执行后可能产生的节点作用域变量及其值类型。
+// 返回值 dynamic 为 true 时，表示节点还可能产生无法静态确定的其他变量。
+func GetNodeScopedVariables(nodeType NodeType) (variables map[string]string, dynamic bool) {
+	variables = map[string]string{
+		stateVarKeyNodeId:   "string",
+		stateVarKeyNodeName: "string",
+	}
+
+	certificateVariables := map[string]string{
+		stateVarKeyCertificateDomain:    "string",
+		stateVarKeyCertificateDomains:   "string",
+		stateVarKeyCertificateNotBefore: "datetime",
+		stateVarKeyCertificateNotAfter:  "datetime",
+		stateVarKeyCertificateHoursLeft: "number",
+		stateVarKeyCertificateDaysLeft:  "number",
+		stateVarKeyCertificateValidity:  "boolean",
+	}
+
+	switch nodeType {
+	case NodeTypeBizApply, NodeTypeBizUpload:
+		variables[stateVarKeyNodeSkipped] = "boolean"
+		maps.Copy(variables, certificateVariables)
+
+	case NodeTypeBizMonitor:
+		maps.Copy(variables, certificateVariables)
+
+	case NodeTypeBizDeploy:
+		variables[stateVarKeyNodeSkipped] = "boolean"
+
+	case NodeTypeSubWorkflow:
+		variables[stateVarKeySubWorkflowRunId] = "string"
+		dynamic = true
+
+	case NodeTypeSetVariables, NodeTypeScript:
+		dynamic = true
+
+	case NodeTypeApproval:
+		variables[stateVarKeyApprovalStatus] = "string"
+		variables[stateVarKeyApprovalExpiresAt] = "datetime"
+		variables[stateVarKeyApprovalDecidedBy] = "string"
+		variables[stateVarKeyApprovalDecidedAt] = "datetime"
+		variables[stateVarKeyApprovalComment] = "string"
+
+	case NodeTypeHttpRequest:
+		variables[stateVarKeyResponseStatus] = "number"
+		variables[stateVarKeyResponseBody] = "string"
+		dynamic = true
+	}
+
+	return variables, dynamic
+}
@@ -0,0 +1,265 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const nodeTypeTesting = NodeType("testing")
+
+// 记录执行过的节点，并按预设次数令节点执行失败
+type testingNodeRecorder struct {
+	mtx      sync.Mutex
+	executed []string
+	failures map[string]int
+}
+
+func (r *testingNodeRecorder) Take() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	executed := r.executed
+	r.executed = nil
+	slices.Sort(executed)
+	return executed
+}
+
+type testingNodeExecutor struct {
+	nodeExecutor
+	recorder *testingNodeRecorder
+}
+
+func (ne *testingNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	ne.recorder.mtx.Lock()
+	ne.recorder.executed = append(ne.recorder.executed, execCtx.Node.Id)
+	failed := ne.recorder.failures[execCtx.Node.Id] > 0
+	if failed {
+		ne.recorder.failures[execCtx.Node.Id]--
+	}
+	ne.recorder.mtx.Unlock()
+
+	switch {
+	case failed:
+		return nil, fmt.Errorf("node '%s' failed", execCtx.Node.Id)
+
+	case execCtx.Node.Data.Config["block"] == true:
+		// 阻塞至上下文取消
+		<-execCtx.ctx.Done()
+		return nil, execCtx.ctx.Err()
+
+	case execCtx.Node.Data.Config["sleep"] != nil:
+		// 不响应上下文取消，结束后写入变量
+		time.Sleep(execCtx.Node.Data.Config["sleep"].(time.Duration))
+		execCtx.variables.Set("late", true, "boolean")
+
+	case execCtx.Node.Data.Config["set"] != nil:
+		execCtx.variables.Set("shared", execCtx.Node.Data.Config["set"], "string")
+	}
+
+	return newNodeExecutionResult(execCtx.Node), nil
+}
+
+func TestWorkflowEngineResume(t *testing.T) {
+	node := func(id string, nodeType NodeType, blocks ...*Node) *Node {
+		return &Node{Id: id, Type: nodeType, Data: domain.WorkflowNodeData{Name: id}, Blocks: blocks}
+	}
+
+	graph := &Graph{
+		Nodes: []*Node{
+			node("a", nodeTypeTesting),
+			node("cond", NodeTypeCondition,
+				node("cond_b1", NodeTypeBranchBlock, node("b", nodeTypeTesting)),
+				node("cond_b2", NodeTypeBranchBlock, node("c", nodeTypeTesting)),
+			),
+			node("tc", NodeTypeTryCatch,
+				node("tc_try", NodeTypeTryBlock, node("d", nodeTypeTesting)),
+				node("tc_catch", NodeTypeCatchBlock, node("e", nodeTypeTesting)),
+			),
+			node("par", NodeTypeParallel,
+				node("par_b1", NodeTypeParallelBlock, node("f", nodeTypeTesting)),
+				node("par_b2", NodeTypeParallelBlock, node("g", nodeTypeTesting)),
+			),
+			node("h", nodeTypeTesting),
+		},
+	}
+
+	recorder := &testingNodeRecorder{failures: map[string]int{"c": 1, "d": 1, "e": 1, "g": 1}}
+	engine := &workflowEngine{
+		executors: map[NodeType]func() NodeExecutor{
+			NodeTypeCondition:     newConditionNodeExecutor,
+			NodeTypeBranchBlock:   newBranchBlockNodeExecutor,
+			NodeTypeTryCatch:      newTryCatchNodeExecutor,
+			NodeTypeTryBlock:      newTryBlockNodeExecutor,
+			NodeTypeCatchBlock:    newCatchBlockNodeExecutor,
+			NodeTypeParallel:      newParallelNodeExecutor,
+			NodeTypeParallelBlock: newParallelBlockNodeExecutor,
+			nodeTypeTesting: func() NodeExecutor {
+				return &testingNodeExecutor{nodeExecutor: nodeExecutor{logger: slog.Default()}, recorder: recorder}
+			},
+		},
+		syslog: slog.Default(),
+	}
+
+	var snapshot *Snapshot
+	engine.OnSnapshot(func(ctx context.Context, s *Snapshot) error {
+		snapshot = s
+		return nil
+	})
+
+	steps := []struct {
+		name         string
+		wantExecuted []string
+		wantErrorAt  string
+	}{
+		{name: "first run", wantExecuted: []string{"a", "b", "c"}, wantErrorAt: "c"},
+		{name: "resume inside condition", wantExecuted: []string{"c", "d", "e"}, wantErrorAt: "e"},
+		{name: "resume inside catch", wantExecuted: []string{"e"}, wantErrorAt: "tc"},
+		{name: "resume from try/catch", wantExecuted: []string{"d", "f", "g"}, wantErrorAt: "g"},
+		{name: "resume inside parallel", wantExecuted: []string{"g", "h"}, wantErrorAt: ""},
+	}
+	for i, step := range steps {
+		execution := WorkflowExecution{WorkflowId: "wf", RunId: fmt.Sprintf("run%d", i), Graph: graph}
+		if i > 0 {
+			execution.ResumeFrom = snapshot
+		}
+
+		err := engine.Invoke(t.Context(), execution)
+		if got := recorder.Take(); !slices.Equal(got, step.wantExecuted) {
+			t.Fatalf("%s: executed nodes got = %v, want %v", step.name, got, step.wantExecuted)
+		}
+		if (err != nil) != (step.wantErrorAt != "") {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if snapshot.ErrorNodeId != step.wantErrorAt {
+			t.Fatalf("%s: error node got = %q, want %q", step.name, snapshot.ErrorNodeId, step.wantErrorAt)
+		}
+	}
+}
+
+func TestWorkflowEngineTimeout(t *testing.T) {
+	recorder := &testingNodeRecorder{failures: map[string]int{}}
+	engine := &workflowEngine{
+		executors: map[NodeType]func() NodeExecutor{
+			NodeTypeTryCatch:   newTryCatchNodeExecutor,
+			NodeTypeTryBlock:   newTryBlockNodeExecutor,
+			NodeTypeCatchBlock: newCatchBlockNodeExecutor,
+			nodeTypeTesting: func() NodeExecutor {
+				return &testingNodeExecutor{nodeExecutor: nodeExecutor{logger: slog.Default()}, recorder: recorder}
+			},
+		},
+		syslog: slog.Default(),
+	}
+
+	var snapshot *Snapshot
+	engine.OnSnapshot(func(ctx context.Context, s *Snapshot) error {
+		snapshot = s
+		return nil
+	})
+
+	t.Run("keep the failed child node", func(t *testing.T) {
+		graph := &Graph{
+			Nodes: []*Node{
+				{Id: "tc", Type: NodeTypeTryCatch, Data: domain.WorkflowNodeData{Name: "tc", Timeout: 1}, Blocks: []*Node{
+					{Id: "tc_try", Type: NodeTypeTryBlock, Blocks: []*Node{
+						{Id: "blocking", Type: nodeTypeTesting, Data: domain.WorkflowNodeData{Config: domain.WorkflowNodeConfig{"block": true}}},
+					}},
+				}},
+			},
+		}
+
+		err := engine.Invoke(t.Context(), WorkflowExecution{WorkflowId: "wf", RunId: "run1", Graph: graph})
+		if !errors.Is(err, ErrNodeTimedOut) {
+			t.Fatalf("Invoke() error = %v, want %v", err, ErrNodeTimedOut)
+		}
+		if snapshot.ErrorNodeId != "blocking" {
+			t.Fatalf("error node got = %q, want %q", snapshot.ErrorNodeId, "blocking")
+		}
+	})
+
+	t.Run("discard the states of the abandoned node", func(t *testing.T) {
+		graph := &Graph{
+			Nodes: []*Node{
+				{Id: "hanging", Type: nodeTypeTesting, Data: domain.WorkflowNodeData{Timeout: 1, Config: domain.WorkflowNodeConfig{"sleep": nodeTimeoutGracePeriod + 2*time.Second}}},
+			},
+		}
+
+		startedAt := time.Now()
+		err := engine.Invoke(t.Context(), WorkflowExecution{WorkflowId: "wf", RunId: "run2", Graph: graph})
+		if elapsed := time.Since(startedAt); elapsed >= nodeTimeoutGracePeriod+2*time.Second {
+			t.Fatalf("Invoke() should return before the node exits, elapsed %s", elapsed)
+		}
+		if !errors.Is(err, ErrNodeTimedOut) {
+			t.Fatalf("Invoke() error = %v, want %v", err, ErrNodeTimedOut)
+		}
+		if snapshot.ErrorNodeId != "hanging" {
+			t.Fatalf("error node got = %q, want %q", snapshot.ErrorNodeId, "hanging")
+		}
+
+		for _, variable := range snapshot.Variables {
+			if variable.Key == "late" {
+				t.Fatalf("the variable written after timeout should be discarded")
+			}
+		}
+	})
+}
+
+func TestWorkflowEngineParallelMerge(t *testing.T) {
+	branch := func(id string, config domain.WorkflowNodeConfig) *Node {
+		return &Node{Id: id, Type: NodeTypeParallelBlock, Blocks: []*Node{
+			{Id: id + "_node", Type: nodeTypeTesting, Data: domain.WorkflowNodeData{Config: config}},
+		}}
+	}
+
+	graph := &Graph{
+		Nodes: []*Node{
+			{Id: "par", Type: NodeTypeParallel, Blocks: []*Node{
+				branch("b1", domain.WorkflowNodeConfig{"set": "b1"}),
+				branch("b2", domain.WorkflowNodeConfig{"set": "b2"}),
+				branch("b3", domain.WorkflowNodeConfig{}),
+			}},
+		},
+	}
+
+	recorder := &testingNodeRecorder{failures: map[string]int{}}
+	engine := &workflowEngine{
+		executors: map[NodeType]func() NodeExecutor{
+			NodeTypeParallel:      newParallelNodeExecutor,
+			NodeTypeParallelBlock: newParallelBlockNodeExecutor,
+			nodeTypeTesting: func() NodeExecutor {
+				return &testingNodeExecutor{nodeExecutor: nodeExecutor{logger: slog.Default()}, recorder: recorder}
+			},
+		},
+		syslog: slog.Default(),
+	}
+
+	var snapshot *Snapshot
+	engine.OnSnapshot(func(ctx context.Context, s *Snapshot) error {
+		snapshot = s
+		return nil
+	})
+
+	// 多个分支写入同一变量时，以靠后的分支为准，与分支的完成顺序无关
+	for i := range 10 {
+		if err := engine.Invoke(t.Context(), WorkflowExecution{WorkflowId: "wf", RunId: fmt.Sprintf("run%d", i), Graph: graph}); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+
+		var got any
+		for _, variable := range snapshot.Variables {
+			if variable.Scope == "" && variable.Key == "shared" {
+				got = variable.Value
+			}
+		}
+		if got != "b2" {
+			t.Fatalf("merged variable got = %v, want %v", got, "b2")
+		}
+	}
+}
@@ -9,4 +9,8 @@ var (
 	ErrTerminated = errors.New("workflow engine: execution was terminated")
 	// 表示工作流引擎在执行子节点时发生异常
 	ErrBlocksException = errors.New("workflow engine: error occurred when executing blocks")
+	// 表示工作流引擎在执行节点时超时
+	ErrNodeTimedOut = errors.New("workflow engine: node timed out")
+	// 表示工作流引擎暂停执行，等待外部操作（如人工审批）后从暂停节点继续执行
+	ErrSuspended = errors.New("workflow engine: execution was suspended")
 )
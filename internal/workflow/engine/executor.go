@@ -56,14 +56,26 @@ func (c *NodeExecutionContext) SetContext(ctx context.Context) *NodeExecutionCon
 	return c
 }
 
+// 试运行时报告当前节点的执行计划，非试运行时不做任何处理。
+func (c *NodeExecutionContext) ReportPlan(action PlanAction, reason string, details map[string]any) {
+	if c.planner == nil {
+		return
+	}
+
+	c.planner.Record(c.WorkflowId, c.Node, action, reason, details)
+}
+
 func newNodeExecutionContext(wfCtx *WorkflowContext, node *Node) *NodeExecutionContext {
-	return (&NodeExecutionContext{}).
+	execCtx := (&NodeExecutionContext{}).
 		SetExecutingWorkflow(wfCtx.WorkflowId, wfCtx.RunId, wfCtx.RunGraph).
 		SetExecutingNode(node).
 		SetEngine(wfCtx.engine).
 		SetVariablesManager(wfCtx.variables).
 		SetInputsManager(wfCtx.inputs).
 		SetContext(wfCtx.ctx)
+	execCtx.resume = wfCtx.resume
+	execCtx.planner = wfCtx.planner
+	return execCtx
 }
 
 type NodeExecutionResult struct {
@@ -0,0 +1,277 @@
+﻿package engine
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/notify"
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+const (
+	ApprovalActionApprove = "approve"
+	ApprovalActionReject  = "reject"
+)
+
+const (
+	approvalDefaultSubject = "[Certimate] Approval required: {{ .workflow.name }}"
+	approvalDefaultMessage = "Workflow \"{{ .workflow.name }}\" (run #{{ .run.id }}) is waiting for approval.\n\n" +
+		"Approve: {{ .approval.approveUrl }}\n" +
+		"Reject: {{ .approval.rejectUrl }}\n" +
+		"{{ if .approval.expiresAt }}Expires at: {{ .approval.expiresAt }}\n{{ end }}"
+)
+
+type approvalNodeExecutor struct {
+	nodeExecutor
+
+	accessRepo accessRepository
+}
+
+func (ne *approvalNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsApproval()
+
+	// 试运行时不等待审批，视为已通过
+	if execCtx.IsDryRun() {
+		execCtx.ReportPlan(PlanActionExecute, "will wait for approval", map[string]any{
+			"provider":    nodeCfg.Provider,
+			"waitTimeout": nodeCfg.WaitTimeout,
+		})
+		return execRes, nil
+	}
+
+	// 子工作流的运行随父运行一同执行，无法单独暂停
+	if _, ok := execCtx.variables.Get(stateVarKeyParentRunId); ok {
+		return execRes, errors.New("approval node is not supported in sub-workflows")
+	}
+
+	// 从等待中继续执行时，根据审批结果决定是否继续；
+	// 审批结果仅对发起审批的运行有效，从失败运行恢复时将重新发起审批
+	if state, ok := execCtx.variables.GetScoped(execCtx.Node.Id, stateVarKeyApprovalRunId); ok && state.ValueString() == execCtx.RunId {
+		status, _ := execCtx.variables.GetScoped(execCtx.Node.Id, stateVarKeyApprovalStatus)
+		decidedBy := ""
+		if state, ok := execCtx.variables.GetScoped(execCtx.Node.Id, stateVarKeyApprovalDecidedBy); ok {
+			decidedBy = state.ValueString()
+		}
+
+		switch domain.WorkflowRunApprovalStatusType(status.ValueString()) {
+		case domain.WorkflowRunApprovalStatusTypeApproved:
+			ne.logger.Info(fmt.Sprintf("approved by %s", decidedBy))
+			return execRes, nil
+
+		case domain.WorkflowRunApprovalStatusTypeRejected:
+			return execRes, fmt.Errorf("approval was rejected by %s", decidedBy)
+
+		case domain.WorkflowRunApprovalStatusTypeExpired:
+			return execRes, errors.New("approval has expired")
+		}
+	}
+
+	// 发起审批
+	var expiresAt time.Time
+	if nodeCfg.WaitTimeout > 0 {
+		expiresAt = time.Now().Add(time.Duration(nodeCfg.WaitTimeout) * time.Second).Truncate(time.Second)
+	}
+
+	approveUrl, err := buildApprovalLink(execCtx.WorkflowId, execCtx.RunId, execCtx.Node.Id, ApprovalActionApprove, expiresAt)
+	if err != nil {
+		ne.logger.Warn("could not build approval link", slog.Any("error", err))
+	}
+	rejectUrl, err := buildApprovalLink(execCtx.WorkflowId, execCtx.RunId, execCtx.Node.Id, ApprovalActionReject, expiresAt)
+	if err != nil {
+		ne.logger.Warn("could not build rejection link", slog.Any("error", err))
+	}
+
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalRunId, execCtx.RunId, "string")
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalStatus, string(domain.WorkflowRunApprovalStatusTypePending), "string")
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalExpiresAt, expiresAt, "datetime")
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalApproveUrl, approveUrl, "string")
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalRejectUrl, rejectUrl, "string")
+	execCtx.variables.RemoveScoped(execCtx.Node.Id, stateVarKeyApprovalDecidedBy)
+	execCtx.variables.RemoveScoped(execCtx.Node.Id, stateVarKeyApprovalDecidedAt)
+	execCtx.variables.RemoveScoped(execCtx.Node.Id, stateVarKeyApprovalComment)
+
+	// 通知审批人
+	if nodeCfg.Provider != "" {
+		if err := ne.notifyApprovers(execCtx, approveUrl, rejectUrl, expiresAt); err != nil {
+			ne.logger.Warn("could not notify approvers")
+			return execRes, err
+		}
+	}
+
+	if expiresAt.IsZero() {
+		ne.logger.Info("waiting for approval ...")
+	} else {
+		ne.logger.Info(fmt.Sprintf("waiting for approval until %s ...", expiresAt.Format(time.RFC3339)))
+	}
+
+	return execRes, ErrSuspended
+}
+
+func (ne *approvalNodeExecutor) notifyApprovers(execCtx *NodeExecutionContext, approveUrl, rejectUrl string, expiresAt time.Time) error {
+	nodeCfg := execCtx.Node.Data.Config.AsApproval()
+
+	providerAccessConfig := make(map[string]any)
+	if nodeCfg.ProviderAccessId != "" {
+		if access, err := ne.accessRepo.GetById(execCtx.ctx, nodeCfg.ProviderAccessId); err != nil {
+			return fmt.Errorf("failed to get access #%s record: %w", nodeCfg.ProviderAccessId, err)
+		} else {
+			providerAccessConfig = access.Config
+		}
+	}
+
+	// 模板中可通过 `{{ .approval.approveUrl }}` 等访问审批信息
+	variables := newVariableManager()
+	for _, state := range execCtx.variables.All() {
+		variables.Add(state)
+	}
+	variables.Set(stateVarKeyApprovalApproveUrl, approveUrl, "string")
+	variables.Set(stateVarKeyApprovalRejectUrl, rejectUrl, "string")
+	if !expiresAt.IsZero() {
+		variables.Set(stateVarKeyApprovalExpiresAt, expiresAt, "datetime")
+	}
+
+	subject, message := nodeCfg.Subject, nodeCfg.Message
+	if subject == "" {
+		subject = approvalDefaultSubject
+	}
+	if message == "" {
+		message = approvalDefaultMessage
+	}
+
+	subject, err := renderTemplate(subject, variables, execCtx.inputs)
+	if err != nil {
+		return fmt.Errorf("failed to render notification subject: %w", err)
+	}
+	message, err = renderTemplate(message, variables, execCtx.inputs)
+	if err != nil {
+		return fmt.Errorf("failed to render notification message: %w", err)
+	}
+
+	notifier := notify.NewClient(notify.WithLogger(ne.logger))
+	notifyReq := &notify.SendNotificationRequest{
+		Provider:               nodeCfg.Provider,
+		ProviderAccessConfig:   providerAccessConfig,
+		ProviderExtendedConfig: nodeCfg.ProviderConfig,
+		Subject:                subject,
+		Message:                message,
+	}
+	if _, err := notifier.SendNotification(execCtx.ctx, notifyReq); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 将审批结果写入等待中的运行的状态快照，继续执行时由审批节点读取。
+func ApplyApprovalDecision(snapshot *Snapshot, status domain.WorkflowRunApprovalStatusType, decidedBy string, comment string) error {
+	if snapshot == nil || snapshot.WaitingNodeId == "" {
+		return errors.New("the workflow run is not waiting for approval")
+	}
+
+	variables := newVariableManager()
+	inouts := newInOutManager()
+	restoreSnapshot(snapshot, variables, inouts)
+
+	if state, ok := variables.GetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalStatus); !ok || state.ValueString() != string(domain.WorkflowRunApprovalStatusTypePending) {
+		return errors.New("the workflow run is not waiting for approval")
+	}
+
+	variables.SetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalStatus, string(status), "string")
+	variables.SetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalDecidedBy, decidedBy, "string")
+	variables.SetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalDecidedAt, time.Now(), "datetime")
+	variables.SetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalComment, comment, "string")
+
+	decided := takeSnapshot(variables, inouts)
+	snapshot.Variables = decided.Variables
+	return nil
+}
+
+// 获取等待中的运行的审批过期时间，不限制时返回零值。
+func GetApprovalExpiresAt(snapshot *Snapshot) time.Time {
+	if snapshot == nil || snapshot.WaitingNodeId == "" {
+		return time.Time{}
+	}
+
+	variables := newVariableManager()
+	restoreSnapshot(snapshot, variables, newInOutManager())
+	if state, ok := variables.GetScoped(snapshot.WaitingNodeId, stateVarKeyApprovalExpiresAt); ok {
+		if t, ok := state.Value.(time.Time); ok {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// 校验审批链接的签名。
+func VerifyApprovalLink(workflowId, runId, nodeId, action string, expires int64, signature string) bool {
+	expected, err := signApprovalLink(workflowId, runId, nodeId, action, expires)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
+
+// 生成免登录的审批链接，形如 `{appURL}/api/webhooks/workflows/{workflowId}/runs/{runId}/approval?...`。
+func buildApprovalLink(workflowId, runId, nodeId, action string, expiresAt time.Time) (string, error) {
+	var expires int64
+	if !expiresAt.IsZero() {
+		expires = expiresAt.Unix()
+	}
+
+	signature, err := signApprovalLink(workflowId, runId, nodeId, action, expires)
+	if err != nil {
+		return "", err
+	}
+
+	appUrl := strings.TrimRight(app.GetApp().Settings().Meta.AppURL, "/")
+	if appUrl == "" {
+		return "", errors.New("the application url is not configured")
+	}
+
+	query := url.Values{}
+	query.Set("node", nodeId)
+	query.Set("action", action)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signature)
+	return fmt.Sprintf("%s/api/webhooks/workflows/%s/runs/%s/approval?%s", appUrl, url.PathEscape(workflowId), url.PathEscape(runId), query.Encode()), nil
+}
+
+// 审批链接的签名密钥派生自超级用户的令牌密钥，重置令牌密钥后已发出的链接将失效。
+func signApprovalLink(workflowId, runId, nodeId, action string, expires int64) (string, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	if err != nil {
+		return "", err
+	} else if collection.AuthToken.Secret == "" {
+		return "", errors.New("the signing secret is empty")
+	}
+
+	keyMac := hmac.New(sha256.New, []byte(collection.AuthToken.Secret))
+	keyMac.Write([]byte("workflow-approval"))
+
+	mac := hmac.New(sha256.New, keyMac.Sum(nil))
+	mac.Write([]byte(strings.Join([]string{workflowId, runId, nodeId, action, strconv.FormatInt(expires, 10)}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func newApprovalNodeExecutor() NodeExecutor {
+	return &approvalNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+		accessRepo:   repository.NewAccessRepository(),
+	}
+}
@@ -20,9 +20,12 @@ import (
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certapply"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/eventbus"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/tools/mproc"
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
 var useMultiProc = true
@@ -54,6 +57,11 @@ type bizApplyNodeExecutor struct {
 	accessRepo      accessRepository
 	certificateRepo certificateRepository
 	wfoutputRepo    workflowOutputRepository
+	privateCARepo   privateCARepository
+
+	privateCAIssuer privateCAIssuer
+
+	eventBus eventbus.EventBus
 }
 
 func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
@@ -72,10 +80,12 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 	}
 
 	// 检测是否可以跳过本次执行
-	if skippable, reason := ne.checkCanSkip(execCtx, lastOutput, lastCertificate); skippable {
+	skippable, reason := ne.checkCanSkip(execCtx, lastOutput, lastCertificate)
+	if skippable {
 		ne.logger.Info(fmt.Sprintf("skip this application, because %s", reason))
 
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, "boolean")
+		execCtx.ReportPlan(PlanActionSkip, reason, nil)
 		return execRes, nil
 	} else {
 		if reason != "" {
@@ -87,6 +97,14 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, "boolean")
 	}
 
+	// 试运行时仅报告申请计划，不申请证书
+	if execCtx.IsDryRun() {
+		// 本次将签发新证书，不再向下游节点输出上次签发的证书
+		execRes = newNodeExecutionResult(execCtx.Node)
+		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, "boolean")
+		return execRes, ne.reportObtainPlan(execCtx, &nodeCfg, reason)
+	}
+
 	// 申请证书
 	obtainResp, err := ne.executeObtain(execCtx, &nodeCfg, lastCertificate)
 	if err != nil {
@@ -119,6 +137,11 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		return execRes, err
 	} else {
 		ne.logger.Info("certificate saved", slog.String("recordId", certificate.Id))
+
+		// 发布证书签发事件
+		event := domain.NewCertificateEvent(domain.EventTypeCertificateIssued, certificate)
+		event.Chain = getEventChain(execCtx.ctx, execCtx.WorkflowId)
+		ne.eventBus.Publish(execCtx.ctx, event)
 	}
 
 	// 保存 ARI 替换状态
@@ -203,6 +226,44 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 	return false, ""
 }
 
+func (ne *bizApplyNodeExecutor) reportObtainPlan(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, reason string) error {
+	if _, err := domain.CertificateKeyAlgorithmType(nodeCfg.KeyAlgorithm).KeyType(); err != nil {
+		return err
+	}
+
+	for _, accessId := range []string{nodeCfg.ProviderAccessId, nodeCfg.CAProviderAccessId} {
+		if accessId == "" {
+			continue
+		}
+
+		if _, err := ne.accessRepo.GetById(execCtx.ctx, accessId); err != nil {
+			return fmt.Errorf("failed to get access #%s record: %w", accessId, err)
+		}
+	}
+
+	if nodeCfg.CAProvider == string(domain.CAProviderTypePrivateCA) {
+		privateCAId := xmaps.GetString(nodeCfg.CAProviderConfig, "privateCaId")
+		if _, err := ne.privateCARepo.GetById(execCtx.ctx, privateCAId); err != nil {
+			return fmt.Errorf("failed to get private ca #%s record: %w", privateCAId, err)
+		}
+	}
+
+	if reason == "" {
+		reason = "no found last issued certificate"
+	}
+
+	execCtx.ReportPlan(PlanActionExecute, reason, map[string]any{
+		"domains":            nodeCfg.Domains,
+		"challengeType":      nodeCfg.ChallengeType,
+		"provider":           nodeCfg.Provider,
+		"providerAccessId":   nodeCfg.ProviderAccessId,
+		"caProvider":         nodeCfg.CAProvider,
+		"caProviderAccessId": nodeCfg.CAProviderAccessId,
+		"keyAlgorithm":       nodeCfg.KeyAlgorithm,
+	})
+	return nil
+}
+
 func (ne *bizApplyNodeExecutor) executeObtain(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certapply.ObtainCertificateResponse, error) {
 	// 读取证书算法
 	legoKeyType, err := domain.CertificateKeyAlgorithmType(nodeCfg.KeyAlgorithm).KeyType()
@@ -210,6 +271,21 @@ func (ne *bizApplyNodeExecutor) executeObtain(execCtx *NodeExecutionContext, nod
 		return nil, err
 	}
 
+	// 读取证书有效期
+	validityTo := lo.If(nodeCfg.ValidityLifetime == "", time.Time{}).
+		ElseF(func() time.Time {
+			duration, err := str2duration.ParseDuration(nodeCfg.ValidityLifetime)
+			if err != nil {
+				return time.Time{}
+			}
+			return time.Now().Add(duration)
+		})
+
+	// 私有 CA 直接签发证书，无需质询
+	if nodeCfg.CAProvider == string(domain.CAProviderTypePrivateCA) {
+		return ne.executeIssueByPrivateCA(execCtx, nodeCfg, legoKeyType, validityTo)
+	}
+
 	// 读取质询提供商授权
 	providerAccessConfig := make(map[string]any)
 	if nodeCfg.ProviderAccessId != "" {
@@ -269,15 +345,8 @@ func (ne *bizApplyNodeExecutor) executeObtain(execCtx *NodeExecutionContext, nod
 		DnsPropagationTimeout:  nodeCfg.DnsPropagationTimeout,
 		DnsTTL:                 nodeCfg.DnsTTL,
 		HttpDelayWait:          nodeCfg.HttpDelayWait,
-		ValidityTo: lo.If(nodeCfg.ValidityLifetime == "", time.Time{}).
-			ElseF(func() time.Time {
-				duration, err := str2duration.ParseDuration(nodeCfg.ValidityLifetime)
-				if err != nil {
-					return time.Time{}
-				}
-				return time.Now().Add(duration)
-			}),
-		ACMEProfile: nodeCfg.ACMEProfile,
+		ValidityTo:             validityTo,
+		ACMEProfile:            nodeCfg.ACMEProfile,
 		ARIReplacesAcctUrl: lo.If(lastCertificate == nil, "").
 			ElseF(func() string {
 				if lastCertificate.ACMERenewed {
@@ -360,6 +429,31 @@ func (ne *bizApplyNodeExecutor) executeObtain(execCtx *NodeExecutionContext, nod
 	return obtainResp, nil
 }
 
+func (ne *bizApplyNodeExecutor) executeIssueByPrivateCA(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, keyType certcrypto.KeyType, validityTo time.Time) (*certapply.ObtainCertificateResponse, error) {
+	issueReq := &privateca.IssueCertificateRequest{
+		PrivateCAId:     xmaps.GetString(nodeCfg.CAProviderConfig, "privateCaId"),
+		SubjectAltNames: nodeCfg.Domains,
+		KeyType:         keyType,
+		Profile:         xmaps.GetString(nodeCfg.CAProviderConfig, "profile"),
+		KeyUsages:       privateca.SplitUsages(xmaps.GetString(nodeCfg.CAProviderConfig, "keyUsages")),
+		ExtKeyUsages:    privateca.SplitUsages(xmaps.GetString(nodeCfg.CAProviderConfig, "extKeyUsages")),
+		ValidityTo:      validityTo,
+	}
+	ne.logger.Info("issuing certificate by private ca ...", slog.String("privateCaId", issueReq.PrivateCAId))
+
+	issueResp, err := ne.privateCAIssuer.IssueCertificate(execCtx.ctx, issueReq)
+	if err != nil {
+		ne.logger.Warn("could not issue certificate by private ca")
+		return nil, err
+	}
+
+	return &certapply.ObtainCertificateResponse{
+		FullChainCertificate: issueResp.FullChainCertificate,
+		IssuerCertificate:    issueResp.IssuerCertificate,
+		PrivateKey:           issueResp.PrivateKey,
+	}, nil
+}
+
 func (ne *bizApplyNodeExecutor) setOuputsOfResult(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, certificate *domain.Certificate, persistent bool) {
 	if certificate != nil {
 		key := "certificate"
@@ -413,5 +507,8 @@ func newBizApplyNodeExecutor() NodeExecutor {
 		accessRepo:      repository.NewAccessRepository(),
 		certificateRepo: repository.NewCertificateRepository(),
 		wfoutputRepo:    repository.NewWorkflowOutputRepository(),
+		privateCARepo:   repository.NewPrivateCARepository(),
+		privateCAIssuer: privateca.NewPrivateCAService(repository.NewPrivateCARepository()),
+		eventBus:        eventbus.GetSingletonEventBus(),
 	}
 }
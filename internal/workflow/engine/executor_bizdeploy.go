@@ -55,20 +55,30 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 	if inputCertificate == nil {
-		return execRes, fmt.Errorf("invalid input certificate")
+		// 试运行时前序节点不会签发新证书，此时认为将部署前序节点签发的新证书
+		if !execCtx.IsDryRun() {
+			return execRes, fmt.Errorf("invalid input certificate")
+		}
 	}
 
 	// 检测是否可以跳过本次执行
-	if lastOutput != nil && inputCertificate.CreatedAt.Before(lastOutput.UpdatedAt) {
+	var planReason string
+	if inputCertificate == nil {
+		planReason = fmt.Sprintf("the certificate will be issued by the node #%s", nodeCfg.CertificateOutputNodeId)
+
+		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, "boolean")
+	} else if lastOutput != nil && inputCertificate.CreatedAt.Before(lastOutput.UpdatedAt) {
 		if skippable, reason := ne.checkCanSkip(execCtx, lastOutput); skippable {
 			ne.logger.Info(fmt.Sprintf("skip this deployment, because %s", reason))
 
 			execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, "boolean")
+			execCtx.ReportPlan(PlanActionSkip, reason, nil)
 			return execRes, nil
 		} else if reason != "" {
 			ne.logger.Info(fmt.Sprintf("re-deploy, because %s", reason))
 
 			execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, "boolean")
+			planReason = reason
 		}
 	} else {
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, "boolean")
@@ -84,6 +94,19 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 
+	// 试运行时仅报告部署计划，不部署证书
+	if execCtx.IsDryRun() {
+		details := map[string]any{
+			"provider":         nodeCfg.Provider,
+			"providerAccessId": nodeCfg.ProviderAccessId,
+		}
+		if inputCertificate != nil {
+			details["certificateId"] = inputCertificate.Id
+		}
+		execCtx.ReportPlan(PlanActionExecute, planReason, details)
+		return execRes, nil
+	}
+
 	// 部署证书
 	deployer := certdeploy.NewClient(certdeploy.WithLogger(ne.logger))
 	deployReq := &certdeploy.DeployCertificateRequest{
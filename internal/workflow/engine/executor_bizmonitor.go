@@ -50,27 +50,13 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 
 	ne.logger.Info(fmt.Sprintf("retrieving certificate at %s (domain: %s)", targetAddr, targetDomain))
 
-	const MAX_ATTEMPTS = 3
-	const RETRY_INTERVAL = 2 * time.Second
-	var err error
-	var certs []*x509.Certificate
-	for attempt := 0; attempt < MAX_ATTEMPTS; attempt++ {
-		if attempt > 0 {
-			ne.logger.Info(fmt.Sprintf("retry %d time(s) ...", attempt))
-
-			select {
-			case <-execCtx.ctx.Done():
-				return execRes, execCtx.ctx.Err()
-			case <-time.After(RETRY_INTERVAL):
-			}
-		}
-
-		certs, err = ne.tryRetrievePeerCertificates(execCtx, targetAddr, targetDomain, nodeCfg.RequestPath)
-		if err == nil {
-			break
-		}
-	}
+	// 监控仅读取远端证书，试运行时仍会执行，以便后续条件分支能得到真实的判断结果
+	execCtx.ReportPlan(PlanActionExecute, "", map[string]any{
+		"address": targetAddr,
+		"domain":  targetDomain,
+	})
 
+	certs, err := ne.tryRetrievePeerCertificates(execCtx, targetAddr, targetDomain, nodeCfg.RequestPath)
 	if err != nil {
 		ne.logger.Warn("could not retrieve certificate")
 		return execRes, err
@@ -116,6 +102,15 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 	return execRes, nil
 }
 
+func (ne *bizMonitorNodeExecutor) DefaultRetryPolicy() *RetryPolicy {
+	// 未配置重试策略时，默认最多尝试 3 次，每次间隔 2 秒
+	return &RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: 2,
+		Multiplier:      1,
+	}
+}
+
 func (ne *bizMonitorNodeExecutor) tryRetrievePeerCertificates(execCtx *NodeExecutionContext, addr, domain, requestPath string) ([]*x509.Certificate, error) {
 	transport := xhttp.NewDefaultTransport()
 	transport.TLSClientConfig = xtls.NewInsecureConfig()
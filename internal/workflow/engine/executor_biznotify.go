@@ -3,9 +3,6 @@
 import (
 	"fmt"
 	"log/slog"
-	"regexp"
-	"strings"
-	"time"
 
 	"github.com/certimate-go/certimate/internal/notify"
 	"github.com/certimate-go/certimate/internal/repository"
@@ -27,6 +24,7 @@ func (ne *bizNotifyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	// 检测是否可以跳过本次执行
 	if skippable, reason := ne.checkCanSkip(execCtx); skippable {
 		ne.logger.Info(fmt.Sprintf("skip this application, because %s", reason))
+		execCtx.ReportPlan(PlanActionSkip, reason, nil)
 		return execRes, nil
 	}
 
@@ -41,29 +39,25 @@ func (ne *bizNotifyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	}
 
 	// 渲染通知模板
-	reMustache := regexp.MustCompile(`\{\{\s*(\$[^\s]+)\s*\}\}`)
-	reMustacheReplacer := func(match string) string {
-		mustache := strings.TrimSpace(match[2 : len(match)-2])
-		if mustache == "" {
-			return match
-		}
-
-		key := mustache[1:]
-		if key == "" {
-			return match
-		} else if key == "now" {
-			return time.Now().Format(time.RFC3339)
-		}
-
-		// TODO: 支持作用域变量
-		if state, ok := execCtx.variables.Get(key); ok {
-			return state.ValueString()
-		}
+	subject, err := renderTemplate(nodeCfg.Subject, execCtx.variables, execCtx.inputs)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render notification subject: %w", err)
+	}
+	message, err := renderTemplate(nodeCfg.Message, execCtx.variables, execCtx.inputs)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render notification message: %w", err)
+	}
 
-		return match
+	// 试运行时仅报告通知计划，不推送通知
+	if execCtx.IsDryRun() {
+		execCtx.ReportPlan(PlanActionExecute, "", map[string]any{
+			"provider":         nodeCfg.Provider,
+			"providerAccessId": nodeCfg.ProviderAccessId,
+			"subject":          subject,
+			"message":          message,
+		})
+		return execRes, nil
 	}
-	subject := reMustache.ReplaceAllStringFunc(nodeCfg.Subject, reMustacheReplacer)
-	message := reMustache.ReplaceAllStringFunc(nodeCfg.Message, reMustacheReplacer)
 
 	// 推送通知
 	notifier := notify.NewClient(notify.WithLogger(ne.logger))
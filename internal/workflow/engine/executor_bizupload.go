@@ -63,10 +63,12 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	}
 
 	// 检测是否可以跳过本次执行
-	if skippable, reason := ne.checkCanSkip(execCtx, lastOutput, lastCertificate); skippable {
+	skippable, reason := ne.checkCanSkip(execCtx, lastOutput, lastCertificate)
+	if skippable {
 		ne.logger.Info(fmt.Sprintf("skip this uploading, because %s", reason))
 
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, "boolean")
+		execCtx.ReportPlan(PlanActionSkip, reason, nil)
 		return execRes, nil
 	} else if reason != "" {
 		ne.logger.Info(fmt.Sprintf("re-upload, because %s", reason))
@@ -76,6 +78,14 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		ne.logger.Info("try to upload")
 	}
 
+	// 试运行时仅报告上传计划，不读取及保存证书
+	if execCtx.IsDryRun() {
+		execCtx.ReportPlan(PlanActionExecute, reason, map[string]any{
+			"source": nodeCfg.Source,
+		})
+		return execRes, nil
+	}
+
 	// 获取证书及私钥
 	var certPEM, privkeyPEM string
 	switch nodeCfg.Source {
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 
 	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain/expr"
 )
 
 type conditionNodeExecutor struct {
@@ -24,7 +26,7 @@ func (ne *conditionNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 
 	errs := make([]error, 0)
 	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeBranchBlock })
-	for _, node := range blocks {
+	for _, node := range skipBlocksBeforeResumeNode(&execCtx.WorkflowContext, blocks) {
 		select {
 		case <-execCtx.ctx.Done():
 			return execRes, execCtx.ctx.Err()
@@ -33,7 +35,7 @@ func (ne *conditionNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 
 		err := engine.executeNode(execCtx.Clone(), node)
 		if err != nil {
-			if errors.Is(err, ErrTerminated) {
+			if errors.Is(err, ErrTerminated) || errors.Is(err, ErrSuspended) {
 				return execRes, err
 			}
 			errs = append(errs, err)
@@ -61,30 +63,25 @@ func (ne *branchBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*Node
 	execRes := newNodeExecutionResult(execCtx.Node)
 
 	nodeCfg := execCtx.Node.Data.Config.AsBranchBlock()
-	if nodeCfg.Expression == nil {
+	if containsResumeNode(&execCtx.WorkflowContext, execCtx.Node.Blocks) {
+		// 恢复节点位于此分支中，说明此前的运行已进入该分支，无需重新求值条件
+		ne.logger.Info("enter this branch to resume the run")
+	} else if nodeCfg.Expression == nil {
 		ne.logger.Info("enter this branch without any conditions")
 	} else {
-		variables := lo.Reduce(execCtx.variables.All(), func(acc map[string]map[string]any, state VariableState, _ int) map[string]map[string]any {
-			if _, ok := acc[state.Scope]; !ok {
-				acc[state.Scope] = make(map[string]any)
-			}
-
-			// 这里需要把所有值都转换为字符串形式，因为 Expression.Eval 仅支持字符串类型的值
-			acc[state.Scope][state.Key] = state.ValueString()
-			return acc
-		}, make(map[string]map[string]any))
-
-		rs, err := nodeCfg.Expression.Eval(variables)
+		matched, err := expr.EvalBool(nodeCfg.Expression, toExprVariables(execCtx.variables.All()))
 		if err != nil {
 			ne.logger.Warn(fmt.Sprintf("failed to eval expr: %+v", err))
 			return execRes, err
 		}
 
-		if rs.Value == false {
+		if !matched {
 			ne.logger.Info("skip this branch, because condition not met")
+			execCtx.ReportPlan(PlanActionSkip, "condition not met", nil)
 			return execRes, nil
 		} else {
 			ne.logger.Info("enter this branch, because condition met")
+			execCtx.ReportPlan(PlanActionExecute, "condition met", nil)
 		}
 	}
 
@@ -14,9 +14,18 @@ func (ne *delayNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecut
 	execRes := newNodeExecutionResult(execCtx.Node)
 
 	nodeCfg := execCtx.Node.Data.Config.AsDelay()
+	if execCtx.IsDryRun() {
+		execCtx.ReportPlan(PlanActionExecute, fmt.Sprintf("will delay for %d second(s)", nodeCfg.Wait), map[string]any{"wait": nodeCfg.Wait})
+		return execRes, nil
+	}
+
 	ne.logger.Info(fmt.Sprintf("delay for %d second(s) before continuing ...", nodeCfg.Wait))
 
-	time.Sleep(time.Duration(nodeCfg.Wait) * time.Second)
+	select {
+	case <-execCtx.ctx.Done():
+		return execRes, execCtx.ctx.Err()
+	case <-time.After(time.Duration(nodeCfg.Wait) * time.Second):
+	}
 
 	return execRes, nil
 }
@@ -0,0 +1,234 @@
+﻿package engine
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const (
+	// 读取响应内容的最大长度。
+	httpRequestMaxResponseSize = 1 << 20
+	// 写入变量的响应内容的最大长度。
+	httpRequestMaxResponseBodyVariableSize = 64 << 10
+)
+
+type httpRequestNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *httpRequestNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsHttpRequest()
+
+	// 渲染请求模板
+	method := strings.ToUpper(nodeCfg.Method)
+	reqUrl, err := renderTemplate(nodeCfg.Url, execCtx.variables, execCtx.inputs)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render request url: %w", err)
+	} else if u, err := url.Parse(reqUrl); err != nil {
+		return execRes, fmt.Errorf("failed to parse request url: %w", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return execRes, fmt.Errorf("unsupported request url scheme '%s'", u.Scheme)
+	}
+
+	reqHeaders := make(http.Header)
+	for k, v := range nodeCfg.Headers {
+		value, err := renderTemplate(v, execCtx.variables, execCtx.inputs)
+		if err != nil {
+			return execRes, fmt.Errorf("failed to render request header '%s': %w", k, err)
+		}
+		reqHeaders.Set(k, value)
+	}
+
+	reqBody, err := renderTemplate(nodeCfg.Body, execCtx.variables, execCtx.inputs)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render request body: %w", err)
+	}
+	if reqBody != "" && reqHeaders.Get("Content-Type") == "" {
+		reqHeaders.Set("Content-Type", "application/json")
+	}
+
+	// 试运行时仅报告请求计划，不发送请求
+	if execCtx.IsDryRun() {
+		execCtx.ReportPlan(PlanActionExecute, fmt.Sprintf("will send %s request to %s", method, reqUrl), map[string]any{
+			"method": method,
+			"url":    reqUrl,
+			"body":   reqBody,
+		})
+		return execRes, nil
+	}
+
+	// 生成请求
+	client, err := newHttpRequestClient(nodeCfg)
+	if err != nil {
+		return execRes, err
+	}
+
+	req := client.R().
+		SetContext(execCtx.ctx).
+		SetHeaderMultiValues(reqHeaders).
+		SetDoNotParseResponse(true)
+	if reqBody != "" {
+		req.SetBody(reqBody)
+	}
+
+	// 发送请求
+	ne.logger.Info(fmt.Sprintf("sending %s request to %s ...", method, reqUrl))
+
+	resp, err := req.Execute(method, reqUrl)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to send http request: %w", err)
+	}
+	defer resp.RawBody().Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.RawBody(), httpRequestMaxResponseSize+1))
+	if err != nil {
+		return execRes, fmt.Errorf("failed to read http response: %w", err)
+	} else if len(respBody) > httpRequestMaxResponseSize {
+		return execRes, fmt.Errorf("http response is too large, exceeds %d bytes", httpRequestMaxResponseSize)
+	}
+
+	ne.logger.Info(fmt.Sprintf("http request responded with status code %d", resp.StatusCode()))
+
+	respBodyVariable := string(respBody)
+	if len(respBodyVariable) > httpRequestMaxResponseBodyVariableSize {
+		respBodyVariable = respBodyVariable[:httpRequestMaxResponseBodyVariableSize]
+		ne.logger.Warn(fmt.Sprintf("http response body is truncated to %d bytes in variable '%s'", httpRequestMaxResponseBodyVariableSize, stateVarKeyResponseBody))
+	}
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyResponseStatus, resp.StatusCode(), "number")
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyResponseBody, respBodyVariable, "string")
+
+	// 提取响应字段
+	if len(nodeCfg.ResponseFields) > 0 {
+		var respData any
+		if err := json.Unmarshal(respBody, &respData); err != nil {
+			if !resp.IsSuccess() && !nodeCfg.IgnoreErrorStatus {
+				return execRes, fmt.Errorf("unexpected http response status code: %d", resp.StatusCode())
+			}
+			return execRes, fmt.Errorf("failed to unmarshal http response as json: %w", err)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(nodeCfg.ResponseFields)) {
+			path := nodeCfg.ResponseFields[name]
+			state, ok, err := lookupHttpResponseField(respData, path)
+			if err != nil {
+				return execRes, fmt.Errorf("failed to extract http response field '%s': %w", path, err)
+			} else if !ok {
+				ne.logger.Warn(fmt.Sprintf("field '%s' not found in http response", path))
+			}
+
+			state.Key = stateVarKeyResponsePrefix + name
+			execRes.AddVariableWithScope(execCtx.Node.Id, state.Key, state.Value, state.ValueType)
+			ne.logger.Info(fmt.Sprintf("set node-scoped variable '%s' to '%s'", state.Key, state.ValueString()))
+		}
+	}
+
+	if !resp.IsSuccess() && !nodeCfg.IgnoreErrorStatus {
+		return execRes, fmt.Errorf("unexpected http response status code: %d", resp.StatusCode())
+	}
+
+	ne.logger.Info("http request completed")
+	return execRes, nil
+}
+
+func newHttpRequestClient(nodeCfg domain.WorkflowNodeConfigForHttpRequest) (*resty.Client, error) {
+	client := resty.New().
+		SetTimeout(30 * time.Second)
+	if nodeCfg.Timeout > 0 {
+		client.SetTimeout(time.Duration(nodeCfg.Timeout) * time.Second)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: nodeCfg.AllowInsecureConnections}
+	if nodeCfg.CACertificate != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(nodeCfg.CACertificate)) {
+			return nil, errors.New("failed to parse ca certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if nodeCfg.ClientCertificate != "" || nodeCfg.ClientPrivateKey != "" {
+		clientCert, err := tls.X509KeyPair([]byte(nodeCfg.ClientCertificate), []byte(nodeCfg.ClientPrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	client.SetTLSClientConfig(tlsConfig)
+
+	return client, nil
+}
+
+// 按以半角句点分隔的路径查找 JSON 字段，数组元素以下标表示，如 `data.items.0.status`。
+// 路径为空时返回整个响应。字段不存在时返回空字符串。
+func lookupHttpResponseField(data any, path string) (VariableState, bool, error) {
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch v := data.(type) {
+			case map[string]any:
+				next, ok := v[part]
+				if !ok {
+					return VariableState{Value: "", ValueType: "string"}, false, nil
+				}
+				data = next
+
+			case []any:
+				index, err := strconv.Atoi(part)
+				if err != nil || index < 0 || index >= len(v) {
+					return VariableState{Value: "", ValueType: "string"}, false, nil
+				}
+				data = v[index]
+
+			default:
+				return VariableState{Value: "", ValueType: "string"}, false, nil
+			}
+		}
+	}
+
+	switch v := data.(type) {
+	case nil:
+		return VariableState{Value: "", ValueType: "string"}, true, nil
+	case string:
+		return VariableState{Value: v, ValueType: "string"}, true, nil
+	case bool:
+		return VariableState{Value: v, ValueType: "boolean"}, true, nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+			return VariableState{Value: int64(v), ValueType: "number"}, true, nil
+		}
+		return VariableState{Value: v, ValueType: "number"}, true, nil
+	default:
+		// 对象或数组以 JSON 字符串的形式写入变量
+		buf := &bytes.Buffer{}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return VariableState{}, false, err
+		}
+		return VariableState{Value: strings.TrimSpace(buf.String()), ValueType: "string"}, true, nil
+	}
+}
+
+func newHttpRequestNodeExecutor() NodeExecutor {
+	return &httpRequestNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
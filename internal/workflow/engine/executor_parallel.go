@@ -0,0 +1,223 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+type parallelNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("impossible!")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsParallel()
+	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeParallelBlock })
+
+	// 恢复运行时，跳过此前的运行中已成功完成的分支
+	completedBranches := make([]string, 0)
+	if containsResumeNode(&execCtx.WorkflowContext, blocks) {
+		if state, ok := execCtx.variables.GetScoped(execCtx.Node.Id, stateVarKeyParallelCompleted); ok && state.ValueString() != "" {
+			completedBranches = strings.Split(state.ValueString(), ";")
+			blocks = lo.Filter(blocks, func(n *Node, _ int) bool { return !lo.Contains(completedBranches, n.Id) })
+			ne.logger.Info(fmt.Sprintf("skip %d branch(es) completed in the previous run", len(completedBranches)))
+		}
+	}
+
+	if len(blocks) == 0 {
+		ne.logger.Info("no branches to run")
+		return execRes, nil
+	}
+
+	concurrency := len(blocks)
+	if nodeCfg.MaxConcurrency > 0 && int(nodeCfg.MaxConcurrency) < concurrency {
+		concurrency = int(nodeCfg.MaxConcurrency)
+	}
+	ne.logger.Info(fmt.Sprintf("run %d branch(es) in parallel (concurrency: %d, fail-fast: %v) ...", len(blocks), concurrency, nodeCfg.FailFast))
+
+	// 每个分支使用独立的变量和输入输出状态副本，待全部分支结束后再按分支顺序合并回来
+	varsSnapshot := execCtx.variables.All()
+	iosSnapshot := execCtx.inputs.All()
+
+	branchCtx, branchCancel := context.WithCancel(execCtx.ctx)
+	defer branchCancel()
+
+	type branchResult struct {
+		vars VariableManager
+		ios  InOutManager
+		err  error
+	}
+	results := make([]*branchResult, len(blocks))
+
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var failed bool
+	semaphore := make(chan struct{}, concurrency)
+	for i, node := range blocks {
+		branchVars := newVariableManager()
+		for _, state := range varsSnapshot {
+			branchVars.Add(state)
+		}
+
+		branchIOs := newInOutManager()
+		for _, state := range iosSnapshot {
+			branchIOs.Add(state)
+		}
+
+		results[i] = &branchResult{vars: branchVars, ios: branchIOs}
+
+		wg.Add(1)
+		go func(i int, node *Node) {
+			defer wg.Done()
+
+			select {
+			case <-branchCtx.Done():
+				results[i].err = branchCtx.Err()
+				return
+			case semaphore <- struct{}{}:
+			}
+			defer func() { <-semaphore }()
+
+			wfCtx := execCtx.Clone().
+				SetVariablesManager(results[i].vars).
+				SetInputsManager(results[i].ios).
+				SetContext(branchCtx)
+			err := engine.executeNode(wfCtx, node)
+			results[i].err = err
+
+			if err != nil && !errors.Is(err, ErrTerminated) && nodeCfg.FailFast {
+				failOnce.Do(func() {
+					failed = true
+					branchCancel()
+				})
+			}
+		}(i, node)
+	}
+	wg.Wait()
+
+	// 按分支顺序合并各分支新增或修改的变量和输出
+	// 若多个分支将同一变量或输出写为不同的值，以靠后的分支为准，并给出警告
+	varsOrigin := lo.SliceToMap(varsSnapshot, func(s VariableState) (string, VariableState) { return s.Scope + "/" + s.Key, s })
+	varsMerged := make(map[string]string) // 变量 => 写入该变量的分支
+	iosOrigin := lo.SliceToMap(iosSnapshot, func(s InOutState) (string, InOutState) { return s.NodeId + "/" + s.Name, s })
+	iosMerged := make(map[string]string) // 输出 => 写入该输出的分支
+	for i, result := range results {
+		for _, state := range result.vars.All() {
+			key := state.Scope + "/" + state.Key
+			if origin, ok := varsOrigin[key]; ok && origin.ValueType == state.ValueType && origin.ValueString() == state.ValueString() {
+				continue
+			}
+
+			if branchId, ok := varsMerged[key]; ok {
+				if prev, _ := execCtx.variables.GetScoped(state.Scope, state.Key); prev.ValueType != state.ValueType || prev.ValueString() != state.ValueString() {
+					ne.logger.Warn(fmt.Sprintf("variable '%s' is set to different values by branch #%s and #%s, the latter takes effect", state.Key, branchId, blocks[i].Id))
+				}
+			}
+			varsMerged[key] = blocks[i].Id
+			execCtx.variables.Add(state)
+		}
+
+		for _, state := range result.ios.All() {
+			key := state.NodeId + "/" + state.Name
+			if origin, ok := iosOrigin[key]; ok && origin.ValueType == state.ValueType && origin.ValueString() == state.ValueString() && origin.Persistent == state.Persistent {
+				continue
+			}
+
+			if branchId, ok := iosMerged[key]; ok {
+				if prev, _ := execCtx.inputs.Get(state.NodeId, state.Name); prev.ValueType != state.ValueType || prev.ValueString() != state.ValueString() {
+					ne.logger.Warn(fmt.Sprintf("output '%s' of node #%s is set to different values by branch #%s and #%s, the latter takes effect", state.Name, state.NodeId, branchId, blocks[i].Id))
+				}
+			}
+			iosMerged[key] = blocks[i].Id
+			execCtx.inputs.Add(state)
+		}
+	}
+
+	// 记录已成功完成的分支，以便从失败的分支恢复运行时跳过它们
+	for i, result := range results {
+		if result.err == nil {
+			completedBranches = append(completedBranches, blocks[i].Id)
+		}
+	}
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyParallelCompleted, strings.Join(completedBranches, ";"), "string")
+
+	if execCtx.ctx.Err() != nil {
+		return execRes, execCtx.ctx.Err()
+	}
+
+	errs := make([]error, 0)
+	terminated := false
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+
+		if errors.Is(result.err, ErrTerminated) {
+			terminated = true
+		} else if failed && (errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded)) {
+			// 被快速失败策略取消的分支，不计入错误
+			continue
+		} else {
+			errs = append(errs, result.err)
+		}
+	}
+
+	if len(errs) > 0 {
+		ne.logger.Warn(fmt.Sprintf("%d of %d branch(es) failed", len(errs), len(blocks)))
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(errs...))
+	}
+
+	if terminated {
+		return execRes, ErrTerminated
+	}
+
+	ne.logger.Info("all branches completed")
+	return execRes, nil
+}
+
+func newParallelNodeExecutor() NodeExecutor {
+	return &parallelNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+type parallelBlockNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("impossible!")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	if err := engine.executeBlocks(execCtx.Clone(), execCtx.Node.Blocks); err != nil {
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
+func newParallelBlockNodeExecutor() NodeExecutor {
+	return &parallelBlockNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
@@ -0,0 +1,800 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// 脚本的最大执行步数。
+	scriptMaxExecutionSteps = 10_000_000
+	// 脚本的最大执行时长。
+	scriptMaxExecutionTime = 30 * time.Second
+	// 脚本中通过运算符构造的字符串、列表或元组的最大长度，以及写入变量的值的最大长度。
+	scriptMaxValueLength = 1 << 20
+)
+
+// 运算符改写后调用的内置函数名。以非法标识符命名，脚本无法引用或覆盖。
+const (
+	scriptBuiltinBinaryOp       = "$binop"
+	scriptBuiltinCheckAugmented = "$checkaug"
+	scriptBuiltinCall           = "$call"
+)
+
+var scriptFileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+type scriptNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *scriptNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsScript()
+
+	assigned := make([]VariableState, 0)
+	setVariable := starlark.NewBuiltin("set_variable", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		var value starlark.Value
+		var scoped bool
+		if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "value", &value, "scoped?", &scoped); err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%s: variable name is empty", fn.Name())
+		}
+
+		state, err := newVariableStateFromStarlarkValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: variable '%s': %w", fn.Name(), name, err)
+		}
+
+		state.Key = name
+		if scoped {
+			state.Scope = execCtx.Node.Id
+		}
+		assigned = append(assigned, state)
+		return starlark.None, nil
+	})
+
+	thread := &starlark.Thread{
+		Name: execCtx.Node.Id,
+		Print: func(_ *starlark.Thread, msg string) {
+			ne.logger.Info(msg)
+		},
+		Load: func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+			return nil, fmt.Errorf("load is not allowed in scripts: %s", module)
+		},
+	}
+	thread.SetMaxExecutionSteps(scriptMaxExecutionSteps)
+
+	ctx, cancel := context.WithTimeout(execCtx.ctx, scriptMaxExecutionTime)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		thread.Cancel(ctx.Err().Error())
+	}()
+
+	predeclared := starlark.StringDict{
+		"vars":         newStarlarkGlobalVariables(execCtx.variables),
+		"nodes":        newStarlarkScopedVariables(execCtx.variables),
+		"outputs":      newStarlarkNodeOutputs(execCtx.inputs),
+		"set_variable": setVariable,
+		"json":         json.Module,
+	}
+	if err := execScript(thread, execCtx.Node.Id+".star", nodeCfg.Script, predeclared); err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			ne.logger.Warn(evalErr.Backtrace())
+		}
+		return execRes, fmt.Errorf("failed to run script: %w", err)
+	}
+
+	details := make(map[string]any)
+	for _, state := range assigned {
+		execRes.AddVariableWithScope(state.Scope, state.Key, state.Value, state.ValueType)
+		details[state.Key] = state.ValueString()
+
+		if state.Scope == "" {
+			ne.logger.Info(fmt.Sprintf("set global variable '%s' to '%s'", state.Key, state.ValueString()))
+		} else {
+			ne.logger.Info(fmt.Sprintf("set node-scoped variable '%s' to '%s'", state.Key, state.ValueString()))
+		}
+	}
+
+	// 脚本运行于沙箱中、没有副作用，试运行时也会照常执行
+	execCtx.ReportPlan(PlanActionExecute, fmt.Sprintf("will set %d variable(s)", len(assigned)), details)
+
+	return execRes, nil
+}
+
+// 校验脚本语法。
+func CheckScript(script string) error {
+	isPredeclared := func(name string) bool {
+		switch name {
+		case "vars", "nodes", "outputs", "set_variable", "json":
+			return true
+		}
+		return false
+	}
+
+	if _, _, err := starlark.SourceProgramOptions(scriptFileOptions, "script.star", script, isPredeclared); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func newStarlarkGlobalVariables(variables VariableManager) *starlark.Dict {
+	dict := starlark.NewDict(0)
+	for _, state := range variables.All() {
+		if state.Scope != "" {
+			continue
+		}
+		dict.SetKey(starlark.String(state.Key), toStarlarkValue(state))
+	}
+	dict.Freeze()
+	return dict
+}
+
+func newStarlarkScopedVariables(variables VariableManager) *starlark.Dict {
+	scopes := make(map[string]*starlark.Dict)
+	for _, state := range variables.All() {
+		if state.Scope == "" {
+			continue
+		}
+		if _, ok := scopes[state.Scope]; !ok {
+			scopes[state.Scope] = starlark.NewDict(0)
+		}
+		scopes[state.Scope].SetKey(starlark.String(state.Key), toStarlarkValue(state))
+	}
+
+	dict := starlark.NewDict(len(scopes))
+	for scope, scopeDict := range scopes {
+		dict.SetKey(starlark.String(scope), scopeDict)
+	}
+	dict.Freeze()
+	return dict
+}
+
+func newStarlarkNodeOutputs(inouts InOutManager) *starlark.Dict {
+	nodes := make(map[string]*starlark.Dict)
+	for _, state := range inouts.All() {
+		switch state.ValueType {
+		case "string", "number", "boolean":
+		default:
+			continue
+		}
+
+		if _, ok := nodes[state.NodeId]; !ok {
+			nodes[state.NodeId] = starlark.NewDict(0)
+		}
+		nodes[state.NodeId].SetKey(starlark.String(state.Name), toStarlarkValue(VariableState{Value: state.Value, ValueType: state.ValueType}))
+	}
+
+	dict := starlark.NewDict(len(nodes))
+	for nodeId, nodeDict := range nodes {
+		dict.SetKey(starlark.String(nodeId), nodeDict)
+	}
+	dict.Freeze()
+	return dict
+}
+
+func toStarlarkValue(state VariableState) starlark.Value {
+	switch v := state.Value.(type) {
+	case string:
+		return starlark.String(v)
+	case bool:
+		return starlark.Bool(v)
+	case int:
+		return starlark.MakeInt(v)
+	case int32:
+		return starlark.MakeInt64(int64(v))
+	case int64:
+		return starlark.MakeInt64(v)
+	case float64:
+		return starlark.Float(v)
+	default:
+		// 日期时间等其他类型的值均以字符串形式提供
+		return starlark.String(state.ValueString())
+	}
+}
+
+// 解析、改写并执行脚本。
+// Starlark 解释器自身仅限制执行步数，单步运算（如 `"x" * 10**9`、`s.replace("x", s)`）即可分配大量内存，
+// 因此将构造字符串、列表或元组的 `+`、`*`、`%` 运算符，以及所有函数调用，改写为带长度校验的内置函数调用。
+func execScript(thread *starlark.Thread, filename string, script string, predeclared starlark.StringDict) error {
+	f, err := scriptFileOptions.Parse(filename, script, 0)
+	if err != nil {
+		return err
+	}
+
+	rewriteStarlarkOperators(f)
+
+	predeclared[scriptBuiltinBinaryOp] = starlark.NewBuiltin(scriptBuiltinBinaryOp, starlarkGuardedBinaryOp)
+	predeclared[scriptBuiltinCheckAugmented] = starlark.NewBuiltin(scriptBuiltinCheckAugmented, starlarkCheckAugmentedOp)
+	predeclared[scriptBuiltinCall] = starlark.NewBuiltin(scriptBuiltinCall, starlarkGuardedCall)
+
+	prog, err := starlark.FileProgram(f, predeclared.Has)
+	if err != nil {
+		return err
+	}
+
+	_, err = prog.Init(thread, predeclared)
+	return err
+}
+
+func isGuardedStarlarkOperator(op syntax.Token) bool {
+	switch op {
+	case syntax.PLUS, syntax.STAR, syntax.PERCENT:
+		return true
+	}
+	return false
+}
+
+// 改写语法树中的运算符与函数调用：
+//   - 二元表达式 `x op y` 改写为 `$binop("op", x, y)`；
+//   - 增量赋值 `x op= y` 改写为 `x op= $checkaug("op", x, y)`，左值会被多求值一次；
+//   - 函数调用 `f(args)` 改写为 `$call(f, args)`。
+func rewriteStarlarkOperators(f *syntax.File) {
+	wrap := func(e *syntax.Expr) {
+		if *e == nil {
+			return
+		}
+
+		bin, ok := (*e).(*syntax.BinaryExpr)
+		if !ok || !isGuardedStarlarkOperator(bin.Op) {
+			return
+		}
+
+		*e = &syntax.CallExpr{
+			Fn:     &syntax.Ident{NamePos: bin.OpPos, Name: scriptBuiltinBinaryOp},
+			Lparen: bin.OpPos,
+			Args: []syntax.Expr{
+				&syntax.Literal{Token: syntax.STRING, TokenPos: bin.OpPos, Raw: bin.Op.String(), Value: bin.Op.String()},
+				bin.X,
+				bin.Y,
+			},
+			Rparen: bin.OpPos,
+		}
+	}
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.AssignStmt:
+			var op syntax.Token
+			switch n.Op {
+			case syntax.PLUS_EQ:
+				op = syntax.PLUS
+			case syntax.STAR_EQ:
+				op = syntax.STAR
+			case syntax.PERCENT_EQ:
+				op = syntax.PERCENT
+			}
+			if op != 0 {
+				n.RHS = &syntax.CallExpr{
+					Fn:     &syntax.Ident{NamePos: n.OpPos, Name: scriptBuiltinCheckAugmented},
+					Lparen: n.OpPos,
+					Args: []syntax.Expr{
+						&syntax.Literal{Token: syntax.STRING, TokenPos: n.OpPos, Raw: op.String(), Value: op.String()},
+						n.LHS,
+						n.RHS,
+					},
+					Rparen: n.OpPos,
+				}
+			}
+			wrap(&n.RHS)
+		case *syntax.BinaryExpr:
+			wrap(&n.X)
+			wrap(&n.Y)
+		case *syntax.CallExpr:
+			wrap(&n.Fn)
+			for i := range n.Args {
+				wrap(&n.Args[i])
+			}
+
+			// 跳过改写生成的内置函数调用
+			if ident, ok := n.Fn.(*syntax.Ident); ok && strings.HasPrefix(ident.Name, "$") {
+				break
+			}
+			n.Args = append([]syntax.Expr{n.Fn}, n.Args...)
+			n.Fn = &syntax.Ident{NamePos: n.Lparen, Name: scriptBuiltinCall}
+		case *syntax.Comprehension:
+			wrap(&n.Body)
+		case *syntax.ForClause:
+			wrap(&n.X)
+		case *syntax.IfClause:
+			wrap(&n.Cond)
+		case *syntax.CondExpr:
+			wrap(&n.Cond)
+			wrap(&n.True)
+			wrap(&n.False)
+		case *syntax.DictEntry:
+			wrap(&n.Key)
+			wrap(&n.Value)
+		case *syntax.DotExpr:
+			wrap(&n.X)
+		case *syntax.ExprStmt:
+			wrap(&n.X)
+		case *syntax.ForStmt:
+			wrap(&n.X)
+		case *syntax.IfStmt:
+			wrap(&n.Cond)
+		case *syntax.IndexExpr:
+			wrap(&n.X)
+			wrap(&n.Y)
+		case *syntax.LambdaExpr:
+			wrap(&n.Body)
+		case *syntax.ListExpr:
+			for i := range n.List {
+				wrap(&n.List[i])
+			}
+		case *syntax.ParenExpr:
+			wrap(&n.X)
+		case *syntax.ReturnStmt:
+			wrap(&n.Result)
+		case *syntax.SliceExpr:
+			wrap(&n.X)
+			wrap(&n.Lo)
+			wrap(&n.Hi)
+			wrap(&n.Step)
+		case *syntax.TupleExpr:
+			for i := range n.List {
+				wrap(&n.List[i])
+			}
+		case *syntax.UnaryExpr:
+			wrap(&n.X)
+		case *syntax.WhileStmt:
+			wrap(&n.Cond)
+		case *syntax.DefStmt:
+			for i := range n.Params {
+				wrap(&n.Params[i])
+			}
+		}
+		return true
+	})
+}
+
+func starlarkGuardedBinaryOp(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	op, x, y, err := unpackStarlarkOperands(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStarlarkOperationLength(op, x, y); err != nil {
+		return nil, err
+	}
+
+	z, err := starlark.Binary(op, x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	if n := starlarkValueLength(z); n > scriptMaxValueLength {
+		return nil, fmt.Errorf("result of operator %s is too long (%d > %d)", op, n, scriptMaxValueLength)
+	}
+
+	return z, nil
+}
+
+func starlarkGuardedCall(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s: missing callee", fn.Name())
+	}
+
+	callee, args := args[0], args[1:]
+	builtin, isBuiltin := callee.(*starlark.Builtin)
+	if isBuiltin {
+		if err := checkStarlarkBuiltinCallLength(builtin, args, kwargs); err != nil {
+			return nil, err
+		}
+	}
+
+	z, err := starlark.Call(thread, callee, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 脚本中定义的函数的返回值已在其内部受到校验，仅需校验内置函数的返回值
+	if isBuiltin {
+		if n := starlarkValueLength(z); n > scriptMaxValueLength {
+			return nil, fmt.Errorf("result of %s is too long (%d > %d)", builtin.Name(), n, scriptMaxValueLength)
+		}
+	}
+
+	return z, nil
+}
+
+// 在调用前预估可能构造大量数据的内置函数的结果长度，超出限制时拒绝调用。
+// 参数不合法时不做校验，交由内置函数自身报错。
+func checkStarlarkBuiltinCallLength(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) error {
+	var n int64
+	switch recv := fn.Receiver().(type) {
+	case starlark.String:
+		switch fn.Name() {
+		case "replace":
+			var oldstr, newstr string
+			count := -1
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &oldstr, &newstr, &count); err != nil {
+				return nil
+			}
+
+			times := strings.Count(string(recv), oldstr)
+			if count >= 0 && count < times {
+				times = count
+			}
+			n = int64(len(recv)) + int64(times)*int64(len(newstr)-len(oldstr))
+
+		case "join":
+			if len(args) != 1 {
+				return nil
+			}
+
+			iter := starlark.Iterate(args[0])
+			if iter == nil {
+				return nil
+			}
+			defer iter.Done()
+
+			var x starlark.Value
+			for iter.Next(&x) {
+				str, ok := x.(starlark.String)
+				if !ok {
+					return nil
+				}
+
+				n += int64(len(str)) + int64(len(recv))
+				if n > scriptMaxValueLength {
+					break
+				}
+			}
+
+		case "format":
+			maxArgSize := 0
+			for _, arg := range args {
+				maxArgSize = max(maxArgSize, starlarkValueSize(arg))
+			}
+			for _, kwarg := range kwargs {
+				maxArgSize = max(maxArgSize, starlarkValueSize(kwarg[1]))
+			}
+			n = int64(len(recv)) + int64(strings.Count(string(recv), "{"))*int64(maxArgSize)
+
+		default:
+			return nil
+		}
+
+	case *starlark.List:
+		switch fn.Name() {
+		case "append", "insert":
+			n = int64(recv.Len()) + 1
+
+		case "extend":
+			if len(args) != 1 {
+				return nil
+			}
+			n = int64(recv.Len()) + int64(starlarkIterableLength(args[0]))
+
+		default:
+			return nil
+		}
+
+	case *starlark.Dict:
+		switch fn.Name() {
+		case "update":
+			n = int64(recv.Len())
+			for _, arg := range args {
+				n += int64(starlarkIterableLength(arg))
+			}
+			n += int64(len(kwargs))
+
+		default:
+			return nil
+		}
+
+	case nil:
+		switch fn.Name() {
+		case "list", "tuple", "dict", "set", "sorted", "reversed", "enumerate", "zip":
+			// 由可迭代对象构造新的列表、元组、字典或集合
+			for _, arg := range args {
+				n = max(n, int64(starlarkIterableLength(arg)))
+			}
+
+		case "str", "repr", "print", "encode", "indent":
+			// 将值转换为字符串，包括 json 模块中的 encode、indent 函数
+			for _, arg := range args {
+				n += int64(starlarkValueSize(arg))
+			}
+
+		default:
+			return nil
+		}
+
+	default:
+		return nil
+	}
+
+	if n > scriptMaxValueLength {
+		return fmt.Errorf("result of %s is too long (%d > %d)", fn.Name(), n, scriptMaxValueLength)
+	}
+
+	return nil
+}
+
+func starlarkCheckAugmentedOp(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	op, x, y, err := unpackStarlarkOperands(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStarlarkOperationLength(op, x, y); err != nil {
+		return nil, err
+	}
+
+	// 格式化运算的结果长度无法预先估算，需试算一次
+	if op == syntax.PERCENT {
+		if z, err := starlark.Binary(op, x, y); err == nil {
+			if n := starlarkValueLength(z); n > scriptMaxValueLength {
+				return nil, fmt.Errorf("result of operator %s is too long (%d > %d)", op, n, scriptMaxValueLength)
+			}
+		}
+	}
+
+	return y, nil
+}
+
+func unpackStarlarkOperands(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (syntax.Token, starlark.Value, starlark.Value, error) {
+	var opstr string
+	var x, y starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 3, &opstr, &x, &y); err != nil {
+		return 0, nil, nil, err
+	}
+
+	switch opstr {
+	case syntax.PLUS.String():
+		return syntax.PLUS, x, y, nil
+	case syntax.STAR.String():
+		return syntax.STAR, x, y, nil
+	case syntax.PERCENT.String():
+		return syntax.PERCENT, x, y, nil
+	}
+
+	return 0, nil, nil, fmt.Errorf("%s: unsupported operator '%s'", fn.Name(), opstr)
+}
+
+// 在运算前预估 `+`、`*` 运算结果的长度，超出限制时拒绝运算。
+func checkStarlarkOperationLength(op syntax.Token, x, y starlark.Value) error {
+	var n int64
+	switch op {
+	case syntax.PLUS:
+		xn, yn := starlarkValueLength(x), starlarkValueLength(y)
+		if xn < 0 || yn < 0 {
+			return nil
+		}
+		n = int64(xn) + int64(yn)
+
+	case syntax.STAR:
+		seq, times := x, y
+		if _, ok := seq.(starlark.Int); ok {
+			seq, times = y, x
+		}
+
+		seqn := starlarkValueLength(seq)
+		if seqn <= 0 {
+			return nil
+		}
+
+		timesInt, ok := times.(starlark.Int)
+		if !ok {
+			return nil
+		}
+		t, ok := timesInt.Int64()
+		if !ok || t > scriptMaxValueLength {
+			return fmt.Errorf("result of operator %s is too long", op)
+		}
+		n = int64(seqn) * t
+
+	case syntax.PERCENT:
+		format, ok := x.(starlark.String)
+		if !ok {
+			return nil
+		}
+
+		maxArgSize := 0
+		if tuple, ok := y.(starlark.Tuple); ok {
+			for _, arg := range tuple {
+				maxArgSize = max(maxArgSize, starlarkValueSize(arg))
+			}
+		} else {
+			maxArgSize = starlarkValueSize(y)
+		}
+		n = int64(len(format)) + int64(strings.Count(string(format), "%"))*int64(maxArgSize)
+
+	default:
+		return nil
+	}
+
+	if n > scriptMaxValueLength {
+		return fmt.Errorf("result of operator %s is too long (%d > %d)", op, n, scriptMaxValueLength)
+	}
+
+	return nil
+}
+
+// 返回字符串、字节串、列表或元组的长度，其他类型返回 -1。
+func starlarkValueLength(v starlark.Value) int {
+	switch v := v.(type) {
+	case starlark.String:
+		return len(v)
+	case starlark.Bytes:
+		return len(v)
+	case *starlark.List:
+		return v.Len()
+	case starlark.Tuple:
+		return v.Len()
+	}
+	return -1
+}
+
+// 返回可迭代对象的元素个数。无法直接获取长度时逐一计数，超出限制后即停止计数。
+func starlarkIterableLength(v starlark.Value) int {
+	if n := starlark.Len(v); n >= 0 {
+		return n
+	}
+
+	iter := starlark.Iterate(v)
+	if iter == nil {
+		return 0
+	}
+	defer iter.Done()
+
+	n := 0
+	var x starlark.Value
+	for n <= scriptMaxValueLength && iter.Next(&x) {
+		n++
+	}
+	return n
+}
+
+// 估算值转换为字符串后的长度，超出限制后即停止估算。
+// 循环引用的列表或字典转换为字符串时会以 `[...]`、`{...}` 代替，此处按相同方式处理。
+func starlarkValueSize(v starlark.Value) int {
+	size := 0
+	path := make(map[starlark.Value]bool)
+
+	var walk func(v starlark.Value)
+	walk = func(v starlark.Value) {
+		if size > scriptMaxValueLength {
+			return
+		}
+
+		switch v := v.(type) {
+		case starlark.String:
+			size += len(v) + 2
+		case starlark.Bytes:
+			size += len(v) + 3
+		case starlark.Tuple:
+			size += 2
+			for _, elem := range v {
+				size++
+				walk(elem)
+			}
+		case *starlark.List:
+			if path[v] {
+				size += 5
+				return
+			}
+			path[v] = true
+			defer delete(path, v)
+
+			size += 2
+			for i := 0; i < v.Len(); i++ {
+				size++
+				walk(v.Index(i))
+			}
+		case *starlark.Dict:
+			if path[v] {
+				size += 5
+				return
+			}
+			path[v] = true
+			defer delete(path, v)
+
+			size += 2
+			for _, item := range v.Items() {
+				size += 2
+				walk(item[0])
+				walk(item[1])
+			}
+		default:
+			size += len(v.String())
+		}
+	}
+	walk(v)
+
+	return size
+}
+
+// 将脚本中的值转换为变量。列表或元组会以半角分号连接为字符串，与 certificate.domains 等变量的格式保持一致。
+// 不支持嵌套的列表或元组（列表可以包含自身，递归转换会导致栈溢出）。
+func newVariableStateFromStarlarkValue(value starlark.Value) (VariableState, error) {
+	if v, ok := value.(starlark.Indexable); ok {
+		if _, isString := value.(starlark.String); !isString {
+			if v.Len() > scriptMaxValueLength {
+				return VariableState{}, fmt.Errorf("list is too long (%d > %d)", v.Len(), scriptMaxValueLength)
+			}
+
+			length := 0
+			strs := make([]string, 0, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				if _, ok := v.Index(i).(starlark.Indexable); ok {
+					if _, isString := v.Index(i).(starlark.String); !isString {
+						return VariableState{}, errors.New("nested lists are not supported")
+					}
+				}
+
+				itemState, err := newVariableStateFromStarlarkScalar(v.Index(i))
+				if err != nil {
+					return VariableState{}, err
+				}
+
+				str := itemState.ValueString()
+				length += len(str) + 1
+				if length > scriptMaxValueLength {
+					return VariableState{}, fmt.Errorf("value is too long (> %d)", scriptMaxValueLength)
+				}
+				strs = append(strs, str)
+			}
+			return VariableState{Value: strings.Join(strs, ";"), ValueType: "string"}, nil
+		}
+	}
+
+	return newVariableStateFromStarlarkScalar(value)
+}
+
+func newVariableStateFromStarlarkScalar(value starlark.Value) (VariableState, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return VariableState{Value: "", ValueType: "string"}, nil
+
+	case starlark.String:
+		if len(v) > scriptMaxValueLength {
+			return VariableState{}, fmt.Errorf("string is too long (%d > %d)", len(v), scriptMaxValueLength)
+		}
+		return VariableState{Value: string(v), ValueType: "string"}, nil
+
+	case starlark.Bool:
+		return VariableState{Value: bool(v), ValueType: "boolean"}, nil
+
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return VariableState{}, fmt.Errorf("integer %s is out of range", v.String())
+		}
+		return VariableState{Value: i, ValueType: "number"}, nil
+
+	case starlark.Float:
+		return VariableState{Value: float64(v), ValueType: "number"}, nil
+
+	}
+
+	return VariableState{}, fmt.Errorf("unsupported value type: %s", value.Type())
+}
+
+func newScriptNodeExecutor() NodeExecutor {
+	return &scriptNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
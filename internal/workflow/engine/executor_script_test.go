@@ -0,0 +1,76 @@
+﻿package engine
+
+import (
+	"strings"
+	"testing"
+
+	"go.starlark.net/starlark"
+)
+
+func TestExecScript(t *testing.T) {
+	testCases := []struct {
+		name    string
+		script  string
+		wantErr string
+		want    any
+	}{
+		{name: "arithmetic", script: `set_variable("x", 1 + 2 * 3)`, want: int64(7)},
+		{name: "string concat", script: `s = "a"` + "\n" + `s += "b" * 2` + "\n" + `set_variable("x", s + "%s" % "c")`, want: "abbc"},
+		{name: "list", script: `l = [1, 2]` + "\n" + `l += [3]` + "\n" + `set_variable("x", l)`, want: "1;2;3"},
+		{name: "string repeat too long", script: `set_variable("x", "x" * 1000000000)`, wantErr: "too long"},
+		{name: "list repeat too long", script: `set_variable("x", [1] * (1 << 21))`, wantErr: "too long"},
+		{name: "augmented repeat too long", script: `s = "x" * 1024` + "\n" + `s *= 2048`, wantErr: "too long"},
+		{name: "doubling too long", script: "s = \"xx\"\nfor i in range(30):\n  s += s", wantErr: "too long"},
+		{name: "format too long", script: "s = \"x\" * 1000000\ns = \"%s%s\" % (s, s)", wantErr: "too long"},
+		{name: "builtin calls", script: "l = [\"a\"]\nl.extend([\"b\"])\nl.append(\"{}\".format(\"c\"))\nset_variable(\"x\", \"-\".join(l).replace(\"-\", \"+\") + str(len(l)))", want: "a+b+c3"},
+		{name: "replace too long", script: "s = \"x\"\nfor i in range(30):\n  s = s.replace(\"x\", \"x\" * 100)", wantErr: "too long"},
+		{name: "join too long", script: "s = \"x\" * 1000000\ns = \"\".join([s] * 100)", wantErr: "too long"},
+		{name: "extend too long", script: "l = [1]\nfor i in range(30):\n  l.extend(l)", wantErr: "too long"},
+		{name: "format too long via method", script: "s = \"x\" * 1000000\ns = \"{}{}\".format(s, s)", wantErr: "too long"},
+		{name: "str too long", script: "s = \"x\" * 1000000\ns = str([s] * 100)", wantErr: "too long"},
+		{name: "list constructor too long", script: `l = list(range(1000000000))`, wantErr: "too long"},
+		{name: "self-referencing list to string", script: "l = []\nl.append(l)\nset_variable(\"x\", str(l))", want: "[[...]]"},
+		{name: "self-referencing list", script: "l = []\nl.append(l)\nset_variable(\"x\", l)", wantErr: "nested lists are not supported"},
+		{name: "nested list", script: `set_variable("x", [[1], 2])`, wantErr: "nested lists are not supported"},
+		{name: "huge range", script: `set_variable("x", range(1000000000))`, wantErr: "too long"},
+		{name: "nested expressions", script: "def f(a, b = 1 + 1):\n  return [a + b for a in [a * 2] if a + 1 > 0][0]\nset_variable(\"x\", f(1) if 1 + 1 == 2 else 0)", want: int64(4)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got any
+			setVariable := starlark.NewBuiltin("set_variable", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var name string
+				var value starlark.Value
+				if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "value", &value); err != nil {
+					return nil, err
+				}
+
+				state, err := newVariableStateFromStarlarkValue(value)
+				if err != nil {
+					return nil, err
+				}
+
+				got = state.Value
+				return starlark.None, nil
+			})
+
+			thread := &starlark.Thread{}
+			thread.SetMaxExecutionSteps(scriptMaxExecutionSteps)
+
+			err := execScript(thread, "test.star", tc.script, starlark.StringDict{"set_variable": setVariable})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing '%s', got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %v (%T), got %v (%T)", tc.want, tc.want, got, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+﻿package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/certimate-go/certimate/internal/domain/expr"
+)
+
+type setVariablesNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *setVariablesNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsSetVariables()
+
+	// 后续赋值可以引用先前赋值的结果，因此需要在副本上逐一写入
+	variables := newVariableManager()
+	for _, state := range execCtx.variables.All() {
+		variables.Add(state)
+	}
+
+	details := make(map[string]any)
+	for _, assignment := range nodeCfg.Assignments {
+		if assignment.Name == "" {
+			return execRes, fmt.Errorf("variable name is empty")
+		}
+
+		e, err := expr.UnmarshalExpr(assignment.Expression)
+		if err != nil {
+			return execRes, fmt.Errorf("failed to parse expression of variable '%s': %w", assignment.Name, err)
+		}
+
+		rs, err := e.Eval(toExprVariables(variables.All()))
+		if err != nil {
+			return execRes, fmt.Errorf("failed to evaluate expression of variable '%s': %w", assignment.Name, err)
+		}
+
+		state, err := newVariableStateFromExprResult(rs)
+		if err != nil {
+			return execRes, fmt.Errorf("failed to evaluate expression of variable '%s': %w", assignment.Name, err)
+		}
+
+		if assignment.Mapping != nil {
+			key := state.ValueString()
+			if mapped, ok := assignment.Mapping[key]; ok {
+				state = VariableState{Value: mapped, ValueType: "string"}
+			} else if assignment.MappingDefault != nil {
+				state = VariableState{Value: *assignment.MappingDefault, ValueType: "string"}
+			} else {
+				return execRes, fmt.Errorf("no mapping found for '%s' of variable '%s'", key, assignment.Name)
+			}
+		}
+
+		state.Key = assignment.Name
+		if assignment.Scoped {
+			state.Scope = execCtx.Node.Id
+		}
+
+		variables.Add(state)
+		execRes.AddVariableWithScope(state.Scope, state.Key, state.Value, state.ValueType)
+		details[state.Key] = state.ValueString()
+
+		if state.Scope == "" {
+			ne.logger.Info(fmt.Sprintf("set global variable '%s' to '%s'", state.Key, state.ValueString()))
+		} else {
+			ne.logger.Info(fmt.Sprintf("set node-scoped variable '%s' to '%s'", state.Key, state.ValueString()))
+		}
+	}
+
+	// 赋值没有副作用，试运行时也会照常执行，以便后续节点的计划基于正确的变量值
+	execCtx.ReportPlan(PlanActionExecute, fmt.Sprintf("will set %d variable(s)", len(nodeCfg.Assignments)), details)
+
+	return execRes, nil
+}
+
+// 将表达式的求值结果转换为变量。列表会以半角分号连接为字符串，与 certificate.domains 等变量的格式保持一致。
+func newVariableStateFromExprResult(rs *expr.EvalResult) (VariableState, error) {
+	switch rs.Type {
+	case expr.Number:
+		f, err := rs.GetFloat64()
+		if err != nil {
+			return VariableState{}, err
+		}
+		if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+			return VariableState{Value: int64(f), ValueType: "number"}, nil
+		}
+		return VariableState{Value: f, ValueType: "number"}, nil
+
+	case expr.Boolean:
+		b, err := rs.GetBool()
+		if err != nil {
+			return VariableState{}, err
+		}
+		return VariableState{Value: b, ValueType: "boolean"}, nil
+
+	case expr.Datetime:
+		t, err := rs.GetTime()
+		if err != nil {
+			return VariableState{}, err
+		}
+		return VariableState{Value: t, ValueType: "datetime"}, nil
+
+	case expr.List:
+		items, err := rs.GetList()
+		if err != nil {
+			return VariableState{}, err
+		}
+
+		strs := make([]string, 0, len(items))
+		for _, item := range items {
+			itemState, err := newVariableStateFromExprResult(item)
+			if err != nil {
+				return VariableState{}, err
+			}
+			strs = append(strs, itemState.ValueString())
+		}
+		return VariableState{Value: strings.Join(strs, ";"), ValueType: "string"}, nil
+
+	case expr.String:
+		s, err := rs.GetString()
+		if err != nil {
+			return VariableState{}, err
+		}
+		return VariableState{Value: s, ValueType: "string"}, nil
+
+	default:
+		return VariableState{Value: fmt.Sprintf("%v", rs.Value), ValueType: "string"}, nil
+	}
+}
+
+func newSetVariablesNodeExecutor() NodeExecutor {
+	return &setVariablesNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
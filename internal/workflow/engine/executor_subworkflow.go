@@ -0,0 +1,232 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+// 子工作流的最大嵌套深度（不含最外层工作流）
+const maxSubWorkflowDepth = 5
+
+type subWorkflowCallStackKey struct{}
+
+// 获取当前上下文中的子工作流调用栈，栈底为最外层工作流的 ID。
+func getSubWorkflowCallStack(ctx context.Context, workflowId string) []string {
+	if stack, ok := ctx.Value(subWorkflowCallStackKey{}).([]string); ok {
+		return stack
+	}
+	return []string{workflowId}
+}
+
+/**
+ * Inputs:
+ *   - ref: "certificate": string
+ *
+ * Outputs:
+ *   - 子工作流中各节点最后产生的同名输出，如 ref: "certificate": string
+ *
+ * Variables:
+ *   - 子工作流中产生的全局变量，作用域为本节点
+ *   - "subWorkflow.runId": string
+ */
+type subWorkflowNodeExecutor struct {
+	nodeExecutor
+
+	workflowRepo    workflowRepository
+	workflowRunRepo workflowRunRepository
+}
+
+func (ne *subWorkflowNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("impossible!")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsSubWorkflow()
+	if nodeCfg.WorkflowId == "" {
+		return execRes, errors.New("sub-workflow is not specified")
+	}
+
+	// 检测递归调用及嵌套深度
+	callStack := getSubWorkflowCallStack(execCtx.ctx, execCtx.WorkflowId)
+	if slices.Contains(callStack, nodeCfg.WorkflowId) {
+		return execRes, fmt.Errorf("recursive sub-workflow invocation detected: %s -> %s", strings.Join(callStack, " -> "), nodeCfg.WorkflowId)
+	} else if len(callStack) > maxSubWorkflowDepth {
+		return execRes, fmt.Errorf("sub-workflow nesting depth exceeds the limit (limit: %d)", maxSubWorkflowDepth)
+	}
+
+	// 读取子工作流
+	subWorkflow, err := ne.workflowRepo.GetById(execCtx.ctx, nodeCfg.WorkflowId)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to get sub-workflow #%s record: %w", nodeCfg.WorkflowId, err)
+	} else if subWorkflow.GraphContent == nil {
+		return execRes, fmt.Errorf("sub-workflow #%s graph content is empty", subWorkflow.Id)
+	} else if err := subWorkflow.GraphContent.Verify(); err != nil {
+		return execRes, fmt.Errorf("sub-workflow #%s graph content is invalid: %w", subWorkflow.Id, err)
+	}
+
+	// 获取前序节点输出证书
+	var inputCertificate *InOutState
+	if nodeCfg.CertificateOutputNodeId != "" {
+		if inputState, ok := execCtx.inputs.Get(nodeCfg.CertificateOutputNodeId, "certificate"); ok {
+			inputCertificate = inputState
+		} else {
+			return execRes, fmt.Errorf("invalid input certificate")
+		}
+	}
+
+	// 创建子工作流运行记录，并关联到当前运行
+	parentRun, err := ne.workflowRunRepo.GetById(execCtx.ctx, execCtx.RunId)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to get workflow run #%s record: %w", execCtx.RunId, err)
+	}
+
+	subRun := &domain.WorkflowRun{
+		WorkflowId:  subWorkflow.Id,
+		ParentRunId: parentRun.Id,
+		Status:      domain.WorkflowRunStatusTypeProcessing,
+		Trigger:     parentRun.Trigger,
+		StartedAt:   time.Now(),
+		Graph:       subWorkflow.GraphContent.Clone(),
+		DryRun:      execCtx.IsDryRun(),
+		ClaimedBy:   parentRun.ClaimedBy,
+	}
+	if execCtx.IsDryRun() {
+		// 试运行时不创建子工作流运行记录，其执行计划将并入当前运行的执行计划中
+		execCtx.ReportPlan(PlanActionExecute, "", map[string]any{
+			"workflowId":   subWorkflow.Id,
+			"workflowName": subWorkflow.Name,
+		})
+	} else if subRun, err = ne.workflowRunRepo.Save(execCtx.ctx, subRun); err != nil {
+		return execRes, fmt.Errorf("failed to save sub-workflow run record: %w", err)
+	}
+
+	// 子工作流中的节点日志写入子工作流的运行记录；试运行时不存在子工作流运行记录，仍写入当前运行
+	subExecutingRun := executingRun{WorkflowId: subWorkflow.Id, RunId: subRun.Id}
+	subRunLabel := fmt.Sprintf("run#%s", subRun.Id)
+	if execCtx.IsDryRun() {
+		subExecutingRun = executingRun{WorkflowId: execCtx.WorkflowId, RunId: execCtx.RunId}
+		subRunLabel = "dry run"
+	}
+
+	ne.logger.Info(fmt.Sprintf("invoke sub-workflow #%s (%s) ...", subWorkflow.Id, subRunLabel))
+
+	// 初始化子工作流的变量和输入输出状态
+	subVars := newVariableManager()
+	if nodeCfg.InheritVariables {
+		for _, state := range execCtx.variables.All() {
+			if state.Scope == "" {
+				subVars.Add(state)
+			}
+		}
+	}
+	subVars.Set(stateVarKeyWorkflowId, subWorkflow.Id, "string")
+	subVars.Set(stateVarKeyWorkflowName, subWorkflow.Name, "string")
+	subVars.Set(stateVarKeyRunId, subRun.Id, "string")
+	subVars.Set(stateVarKeyRunTrigger, subRun.Trigger, "string")
+	subVars.Set(stateVarKeyParentWorkflowId, execCtx.WorkflowId, "string")
+	subVars.Set(stateVarKeyParentRunId, execCtx.RunId, "string")
+	subVars.Set(stateVarKeyErrorNodeId, "", "string")
+	subVars.Set(stateVarKeyErrorNodeName, "", "string")
+	subVars.Set(stateVarKeyErrorMessage, "", "string")
+	for key, value := range nodeCfg.Variables {
+		subVars.Set(stateVarKeyInputPrefix+key, value, "string")
+	}
+	subVarsSnapshot := subVars.All()
+
+	subIOs := newInOutManager()
+	if inputCertificate != nil {
+		// 传入的证书视作子工作流开始节点的输出，子工作流中的节点可通过开始节点 ID 引用
+		subIOs.Set(subWorkflow.GraphContent.Nodes[0].Id, inputCertificate.Type, inputCertificate.Name, inputCertificate.Value, inputCertificate.ValueType, false)
+	}
+	subIOsSnapshot := subIOs.All()
+
+	subCtx := (&WorkflowContext{}).
+		SetExecutingWorkflow(subWorkflow.Id, subRun.Id, subRun.Graph).
+		SetEngine(engine).
+		SetVariablesManager(subVars).
+		SetInputsManager(subIOs).
+		SetContext(context.WithValue(context.WithValue(execCtx.ctx, subWorkflowCallStackKey{}, append(slices.Clone(callStack), subWorkflow.Id)), executingRunKey{}, subExecutingRun))
+	subCtx.planner = execCtx.planner
+	subErr := engine.executeBlocks(subCtx, subRun.Graph.Nodes)
+	if subErr != nil && errors.Is(subErr, ErrTerminated) {
+		subErr = nil
+	}
+
+	// 更新子工作流运行记录
+	subRun.EndedAt = time.Now()
+	if subErr == nil {
+		subRun.Status = domain.WorkflowRunStatusTypeSucceeded
+	} else if errors.Is(subErr, context.Canceled) || errors.Is(subErr, context.DeadlineExceeded) {
+		subRun.Status = domain.WorkflowRunStatusTypeCanceled
+	} else {
+		subRun.Status = domain.WorkflowRunStatusTypeFailed
+		subRun.Error = subErr.Error()
+	}
+	if !execCtx.IsDryRun() {
+		if _, err := ne.workflowRunRepo.Save(context.WithoutCancel(execCtx.ctx), subRun); err != nil {
+			ne.logger.Warn("could not save sub-workflow run record", slog.Any("error", err))
+		}
+	}
+
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeySubWorkflowRunId, subRun.Id, "string")
+
+	if subErr != nil {
+		if execCtx.ctx.Err() != nil {
+			return execRes, execCtx.ctx.Err()
+		}
+
+		// 将子工作流中的错误信息传递给当前工作流，以便异常处理分支读取；
+		// 出错节点记为本节点，以便从本节点恢复运行
+		execCtx.variables.Set(stateVarKeyErrorNodeId, execCtx.Node.Id, "string")
+		execCtx.variables.Set(stateVarKeyErrorNodeName, execCtx.Node.Data.Name, "string")
+		if state, ok := subVars.Get(stateVarKeyErrorMessage); ok {
+			execCtx.variables.Add(*state)
+		}
+
+		ne.logger.Warn(fmt.Sprintf("sub-workflow #%s (%s) failed", subWorkflow.Id, subRunLabel))
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, subErr)
+	}
+
+	// 将子工作流的输出作为本节点的输出
+	for _, state := range subIOs.All() {
+		if lo.ContainsBy(subIOsSnapshot, func(s InOutState) bool { return reflect.DeepEqual(s, state) }) {
+			continue
+		}
+		execRes.AddOutput(state.Type, state.Name, state.Value, state.ValueType)
+	}
+
+	// 将子工作流产生的全局变量作为本节点作用域的变量
+	for _, state := range subVars.All() {
+		if state.Scope != "" || lo.ContainsBy(subVarsSnapshot, func(s VariableState) bool { return reflect.DeepEqual(s, state) }) {
+			continue
+		}
+		execRes.AddVariableWithScope(execCtx.Node.Id, state.Key, state.Value, state.ValueType)
+	}
+
+	ne.logger.Info(fmt.Sprintf("sub-workflow #%s (%s) completed", subWorkflow.Id, subRunLabel))
+	return execRes, nil
+}
+
+func newSubWorkflowNodeExecutor() NodeExecutor {
+	return &subWorkflowNodeExecutor{
+		nodeExecutor:    nodeExecutor{logger: slog.Default()},
+		workflowRepo:    repository.NewWorkflowRepository(),
+		workflowRunRepo: repository.NewWorkflowRunRepository(),
+	}
+}
@@ -24,7 +24,16 @@ func (ne *tryCatchNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 
 	tryErrs := make([]error, 0)
 	tryBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeTryBlock })
-	for _, node := range tryBlocks {
+	catchBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeCatchBlock })
+
+	// 恢复节点位于 catch 分支中，说明此前的运行中 try 分支已失败，直接进入 catch 分支
+	resumeInCatch := containsResumeNode(&execCtx.WorkflowContext, catchBlocks)
+	if resumeInCatch {
+		ne.logger.Info("enter the catch branch to resume the run")
+		tryBlocks = nil
+	}
+
+	for _, node := range skipBlocksBeforeResumeNode(&execCtx.WorkflowContext, tryBlocks) {
 		select {
 		case <-execCtx.ctx.Done():
 			return execRes, execCtx.ctx.Err()
@@ -33,17 +42,17 @@ func (ne *tryCatchNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 
 		err := engine.executeNode(execCtx.Clone(), node)
 		if err != nil {
-			if errors.Is(err, ErrTerminated) {
+			// 暂停执行不视为异常，不执行 catch 分支
+			if errors.Is(err, ErrTerminated) || errors.Is(err, ErrSuspended) {
 				return execRes, err
 			}
 			tryErrs = append(tryErrs, err)
 		}
 	}
 
-	if len(tryErrs) > 0 {
+	if len(tryErrs) > 0 || resumeInCatch {
 		catchErrs := make([]error, 0)
-		catchBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeCatchBlock })
-		for _, node := range catchBlocks {
+		for _, node := range skipBlocksBeforeResumeNode(&execCtx.WorkflowContext, catchBlocks) {
 			select {
 			case <-execCtx.ctx.Done():
 				return execRes, execCtx.ctx.Err()
@@ -52,13 +61,18 @@ func (ne *tryCatchNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 
 			err := engine.executeNode(execCtx.Clone(), node)
 			if err != nil {
-				if errors.Is(err, ErrTerminated) {
+				if errors.Is(err, ErrTerminated) || errors.Is(err, ErrSuspended) {
 					return execRes, err
 				}
 				catchErrs = append(catchErrs, err)
 			}
 		}
 
+		if len(tryErrs) == 0 && len(catchErrs) == 0 {
+			// try 分支的异常发生在此前的运行中，此处不作为子节点异常包装，以便再次恢复时从当前节点重新执行
+			return execRes, errors.New("the try branch has failed in the previous run")
+		}
+
 		errs := make([]error, 0)
 		errs = append(errs, tryErrs...)
 		errs = append(errs, catchErrs...)
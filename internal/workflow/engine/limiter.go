@@ -0,0 +1,149 @@
+﻿package engine
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+// 各提供商同时执行的节点数上限，以免触发云服务 API 的频率限制。
+// 通过环境变量配置，形如 "aliyun=2,cloudflare-dns=5"：
+// 键可以是完整的提供商类型（如 "aliyun-cdn"），也可以是授权提供商类型（如 "aliyun"，作用于其下所有提供商）；未配置的提供商不限制。
+// 限制按节点的每次执行计数，包括子工作流中的节点。多实例模式下，该限制作用于单个实例。
+var providerLimiter = newProviderConcurrencyLimiter(parseProviderConcurrency(os.Getenv("CERTIMATE_WORKFLOW_PROVIDER_CONCURRENCY")))
+
+// 获取各提供商的并发数上限。
+func GetProviderConcurrency() map[string]int {
+	return providerLimiter.Limits()
+}
+
+func parseProviderConcurrency(s string) map[string]int {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		provider, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+
+		if n, err := strconv.Atoi(strings.TrimSpace(limit)); err == nil && n > 0 {
+			limits[strings.TrimSpace(provider)] = n
+		}
+	}
+	return limits
+}
+
+type providerConcurrencyLimiter struct {
+	mtx     sync.Mutex
+	limits  map[string]int // Key: 提供商类型或授权提供商类型
+	running map[string]int
+	changed chan struct{} // 每次释放名额时关闭并重建，用于唤醒等待者
+}
+
+func newProviderConcurrencyLimiter(limits map[string]int) *providerConcurrencyLimiter {
+	return &providerConcurrencyLimiter{
+		limits:  limits,
+		running: make(map[string]int),
+		changed: make(chan struct{}),
+	}
+}
+
+func (l *providerConcurrencyLimiter) Limits() map[string]int {
+	return lo.Assign(l.limits)
+}
+
+// 返回提供商所适用的限制键。提供商类型中短横线前的部分始终等于授权提供商类型。
+func (l *providerConcurrencyLimiter) keyOf(provider string) (string, bool) {
+	if _, ok := l.limits[provider]; ok {
+		return provider, true
+	}
+
+	accessProvider := strings.Split(provider, "-")[0]
+	if _, ok := l.limits[accessProvider]; ok {
+		return accessProvider, true
+	}
+
+	return "", false
+}
+
+// 为节点使用的全部提供商占用名额，名额不足时等待。一次性占用全部名额，以免相互等待导致死锁。
+// 名额不足时，首次等待前调用 onWait。
+func (l *providerConcurrencyLimiter) Acquire(ctx context.Context, providers []string, onWait func(key string, limit int)) (func(), error) {
+	keys := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		if key, ok := l.keyOf(provider); ok {
+			keys = append(keys, key)
+		}
+	}
+	keys = lo.Uniq(keys)
+	if len(keys) == 0 {
+		return func() {}, nil
+	}
+
+	waited := false
+	for {
+		l.mtx.Lock()
+		exceededKey := ""
+		for _, key := range keys {
+			if l.running[key] >= l.limits[key] {
+				exceededKey = key
+				break
+			}
+		}
+		if exceededKey == "" {
+			for _, key := range keys {
+				l.running[key]++
+			}
+			l.mtx.Unlock()
+
+			var once sync.Once
+			return func() { once.Do(func() { l.release(keys) }) }, nil
+		}
+		changed := l.changed
+		l.mtx.Unlock()
+
+		if !waited && onWait != nil {
+			onWait(exceededKey, l.limits[exceededKey])
+		}
+		waited = true
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (l *providerConcurrencyLimiter) release(keys []string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	for _, key := range keys {
+		l.running[key]--
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// 获取节点使用的提供商。
+func getNodeProviders(node *Node) []string {
+	providers := make([]string, 0)
+	switch node.Type {
+	case domain.WorkflowNodeTypeBizApply:
+		nodeCfg := node.Data.Config.AsBizApply()
+		providers = append(providers, nodeCfg.Provider, nodeCfg.CAProvider)
+	case domain.WorkflowNodeTypeBizDeploy:
+		providers = append(providers, node.Data.Config.AsBizDeploy().Provider)
+	case domain.WorkflowNodeTypeBizNotify:
+		providers = append(providers, node.Data.Config.AsBizNotify().Provider)
+	}
+
+	return lo.Uniq(lo.Compact(providers))
+}
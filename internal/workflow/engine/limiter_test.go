@@ -0,0 +1,110 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseProviderConcurrency(t *testing.T) {
+	limits := parseProviderConcurrency(" aliyun=2, cloudflare-dns = 5,invalid,zero=0,neg=-1,nan=x")
+	if len(limits) != 2 || limits["aliyun"] != 2 || limits["cloudflare-dns"] != 5 {
+		t.Errorf("unexpected limits: %v", limits)
+	}
+}
+
+func TestProviderConcurrencyLimiter(t *testing.T) {
+	t.Run("KeyOf", func(t *testing.T) {
+		limiter := newProviderConcurrencyLimiter(map[string]int{"aliyun": 1, "tencentcloud-cdn": 1})
+
+		testCases := []struct {
+			provider string
+			key      string
+			limited  bool
+		}{
+			{"aliyun", "aliyun", true},
+			{"aliyun-cdn", "aliyun", true},
+			{"aliyun-oss", "aliyun", true},
+			{"tencentcloud-cdn", "tencentcloud-cdn", true},
+			{"tencentcloud-cos", "", false},
+			{"cloudflare", "", false},
+		}
+		for _, tc := range testCases {
+			key, limited := limiter.keyOf(tc.provider)
+			if key != tc.key || limited != tc.limited {
+				t.Errorf("keyOf(%q): expected (%q, %v), got (%q, %v)", tc.provider, tc.key, tc.limited, key, limited)
+			}
+		}
+	})
+
+	t.Run("Acquire", func(t *testing.T) {
+		limiter := newProviderConcurrencyLimiter(map[string]int{"aliyun": 1})
+
+		release1, err := limiter.Acquire(context.Background(), []string{"aliyun-cdn"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// 不受限的提供商无需等待
+		releaseUnlimited, err := limiter.Acquire(context.Background(), []string{"cloudflare"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		releaseUnlimited()
+
+		// 同一授权提供商下的其他提供商需等待
+		waited := make(chan struct{})
+		acquired := make(chan func())
+		go func() {
+			release2, err := limiter.Acquire(context.Background(), []string{"aliyun-oss", "cloudflare"}, func(key string, limit int) {
+				if key != "aliyun" || limit != 1 {
+					t.Errorf("unexpected wait on %q (limit: %d)", key, limit)
+				}
+				close(waited)
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			acquired <- release2
+		}()
+
+		select {
+		case <-waited:
+		case <-time.After(time.Second):
+			t.Fatal("expected the second acquisition to wait")
+		}
+		select {
+		case <-acquired:
+			t.Fatal("expected the second acquisition to block")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		release1()
+		release1() // 重复释放无影响
+
+		select {
+		case release2 := <-acquired:
+			release2()
+		case <-time.After(time.Second):
+			t.Fatal("expected the second acquisition to succeed after release")
+		}
+
+		if limiter.running["aliyun"] != 0 {
+			t.Errorf("expected no running slots, got %d", limiter.running["aliyun"])
+		}
+	})
+
+	t.Run("AcquireCanceled", func(t *testing.T) {
+		limiter := newProviderConcurrencyLimiter(map[string]int{"aliyun": 1})
+
+		release, _ := limiter.Acquire(context.Background(), []string{"aliyun"}, nil)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := limiter.Acquire(ctx, []string{"aliyun"}, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
@@ -9,19 +9,26 @@ type Node = domain.WorkflowNode
 type NodeType = domain.WorkflowNodeType
 
 const (
-	NodeTypeStart       = domain.WorkflowNodeTypeStart
-	NodeTypeEnd         = domain.WorkflowNodeTypeEnd
-	NodeTypeCondition   = domain.WorkflowNodeTypeCondition
-	NodeTypeBranchBlock = domain.WorkflowNodeTypeBranchBlock
-	NodeTypeTryCatch    = domain.WorkflowNodeTypeTryCatch
-	NodeTypeTryBlock    = domain.WorkflowNodeTypeTryBlock
-	NodeTypeCatchBlock  = domain.WorkflowNodeTypeCatchBlock
-	NodeTypeDelay       = domain.WorkflowNodeTypeDelay
-	NodeTypeBizApply    = domain.WorkflowNodeTypeBizApply
-	NodeTypeBizUpload   = domain.WorkflowNodeTypeBizUpload
-	NodeTypeBizMonitor  = domain.WorkflowNodeTypeBizMonitor
-	NodeTypeBizDeploy   = domain.WorkflowNodeTypeBizDeploy
-	NodeTypeBizNotify   = domain.WorkflowNodeTypeBizNotify
+	NodeTypeStart         = domain.WorkflowNodeTypeStart
+	NodeTypeEnd           = domain.WorkflowNodeTypeEnd
+	NodeTypeCondition     = domain.WorkflowNodeTypeCondition
+	NodeTypeBranchBlock   = domain.WorkflowNodeTypeBranchBlock
+	NodeTypeTryCatch      = domain.WorkflowNodeTypeTryCatch
+	NodeTypeTryBlock      = domain.WorkflowNodeTypeTryBlock
+	NodeTypeCatchBlock    = domain.WorkflowNodeTypeCatchBlock
+	NodeTypeParallel      = domain.WorkflowNodeTypeParallel
+	NodeTypeParallelBlock = domain.WorkflowNodeTypeParallelBlock
+	NodeTypeDelay         = domain.WorkflowNodeTypeDelay
+	NodeTypeSubWorkflow   = domain.WorkflowNodeTypeSubWorkflow
+	NodeTypeSetVariables  = domain.WorkflowNodeTypeSetVariables
+	NodeTypeScript        = domain.WorkflowNodeTypeScript
+	NodeTypeHttpRequest   = domain.WorkflowNodeTypeHttpRequest
+	NodeTypeApproval      = domain.WorkflowNodeTypeApproval
+	NodeTypeBizApply      = domain.WorkflowNodeTypeBizApply
+	NodeTypeBizUpload     = domain.WorkflowNodeTypeBizUpload
+	NodeTypeBizMonitor    = domain.WorkflowNodeTypeBizMonitor
+	NodeTypeBizDeploy     = domain.WorkflowNodeTypeBizDeploy
+	NodeTypeBizNotify     = domain.WorkflowNodeTypeBizNotify
 )
 
 type Graph = domain.WorkflowGraph
@@ -0,0 +1,62 @@
+﻿package engine
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type Plan = domain.WorkflowRunPlan
+
+type PlanAction = domain.WorkflowRunPlanAction
+
+const (
+	PlanActionExecute = domain.WorkflowRunPlanActionExecute
+	PlanActionSkip    = domain.WorkflowRunPlanActionSkip
+	PlanActionFail    = domain.WorkflowRunPlanActionFail
+)
+
+// 试运行时记录各节点的执行计划，并行分支中的节点可能会同时写入
+type planRecorder struct {
+	stepsMtx sync.Mutex
+	steps    []*domain.WorkflowRunPlanStep
+}
+
+func (r *planRecorder) Record(workflowId string, node *Node, action PlanAction, reason string, details map[string]any) {
+	r.stepsMtx.Lock()
+	defer r.stepsMtx.Unlock()
+
+	step := &domain.WorkflowRunPlanStep{
+		WorkflowId: workflowId,
+		NodeId:     node.Id,
+		NodeName:   node.Data.Name,
+		NodeType:   node.Type,
+		Action:     action,
+		Reason:     reason,
+		Details:    details,
+	}
+
+	for i, item := range r.steps {
+		if item.WorkflowId == workflowId && item.NodeId == node.Id {
+			r.steps[i] = step
+			return
+		}
+	}
+	r.steps = append(r.steps, step)
+}
+
+func (r *planRecorder) Plan() *Plan {
+	r.stepsMtx.Lock()
+	defer r.stepsMtx.Unlock()
+
+	return &Plan{
+		Steps: slices.Clone(r.steps),
+	}
+}
+
+func newPlanRecorder() *planRecorder {
+	return &planRecorder{
+		steps: make([]*domain.WorkflowRunPlanStep, 0),
+	}
+}
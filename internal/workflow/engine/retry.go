@@ -0,0 +1,81 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type RetryPolicy = domain.WorkflowNodeRetryPolicy
+
+// 节点执行器可实现此接口，以便在节点未配置重试策略时提供默认的重试策略。
+type withDefaultRetryPolicy interface {
+	DefaultRetryPolicy() *RetryPolicy
+}
+
+func computeRetryBackoff(policy *RetryPolicy, attempt int) time.Duration {
+	if policy == nil || policy.InitialInterval <= 0 {
+		return 0
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	// attempt 从 1 开始计数，第 1 次重试等待 InitialInterval
+	interval := float64(policy.InitialInterval) * math.Pow(multiplier, float64(max(0, attempt-1)))
+	if policy.MaxInterval > 0 {
+		interval = math.Min(interval, float64(policy.MaxInterval))
+	}
+
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		interval = interval * (1 - jitter + rand.Float64()*jitter*2)
+	}
+
+	return time.Duration(interval * float64(time.Second))
+}
+
+func isRetryableError(policy *RetryPolicy, err error) bool {
+	if policy == nil || policy.MaxAttempts <= 1 || err == nil {
+		return false
+	}
+
+	// 工作流被中断或取消时不重试
+	if errors.Is(err, ErrTerminated) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// 暂停执行时不重试
+	if errors.Is(err, ErrSuspended) {
+		return false
+	}
+
+	// 子节点执行异常时不重试，子节点应自行配置重试策略
+	if errors.Is(err, ErrBlocksException) {
+		return false
+	}
+
+	if len(policy.RetryableErrors) == 0 {
+		return true
+	}
+
+	for _, pattern := range policy.RetryableErrors {
+		re, rerr := regexp.Compile(pattern)
+		if rerr != nil {
+			continue
+		}
+
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
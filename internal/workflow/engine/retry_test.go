@@ -0,0 +1,84 @@
+﻿package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestComputeRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{name: "nil policy", policy: nil, attempt: 1, want: 0},
+		{name: "no interval", policy: &RetryPolicy{MaxAttempts: 3}, attempt: 1, want: 0},
+		{name: "first retry", policy: &RetryPolicy{InitialInterval: 5}, attempt: 1, want: 5 * time.Second},
+		{name: "default multiplier", policy: &RetryPolicy{InitialInterval: 5}, attempt: 3, want: 20 * time.Second},
+		{name: "custom multiplier", policy: &RetryPolicy{InitialInterval: 2, Multiplier: 1.5}, attempt: 3, want: 4500 * time.Millisecond},
+		{name: "negative multiplier", policy: &RetryPolicy{InitialInterval: 1, Multiplier: -1}, attempt: 2, want: 2 * time.Second},
+		{name: "max interval", policy: &RetryPolicy{InitialInterval: 5, MaxInterval: 30}, attempt: 10, want: 30 * time.Second},
+		{name: "attempt zero", policy: &RetryPolicy{InitialInterval: 5}, attempt: 0, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeRetryBackoff(tt.policy, tt.attempt); got != tt.want {
+				t.Errorf("computeRetryBackoff() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := &RetryPolicy{InitialInterval: 10, Jitter: 0.2}
+		for range 100 {
+			got := computeRetryBackoff(policy, 1)
+			if got < 8*time.Second || got > 12*time.Second {
+				t.Fatalf("computeRetryBackoff() got = %v, want within [8s, 12s]", got)
+			}
+		}
+	})
+
+	t.Run("jitter greater than 1", func(t *testing.T) {
+		policy := &RetryPolicy{InitialInterval: 10, Jitter: 5}
+		for range 100 {
+			got := computeRetryBackoff(policy, 1)
+			if got < 0 || got > 20*time.Second {
+				t.Fatalf("computeRetryBackoff() got = %v, want within [0s, 20s]", got)
+			}
+		}
+	})
+}
+
+func TestIsRetryableError(t *testing.T) {
+	errTimeout := errors.New("dial tcp: i/o timeout")
+
+	tests := []struct {
+		name   string
+		policy *RetryPolicy
+		err    error
+		want   bool
+	}{
+		{name: "nil policy", policy: nil, err: errTimeout, want: false},
+		{name: "single attempt", policy: &RetryPolicy{MaxAttempts: 1}, err: errTimeout, want: false},
+		{name: "nil error", policy: &RetryPolicy{MaxAttempts: 3}, err: nil, want: false},
+		{name: "any error", policy: &RetryPolicy{MaxAttempts: 3}, err: errTimeout, want: true},
+		{name: "terminated", policy: &RetryPolicy{MaxAttempts: 3}, err: fmt.Errorf("wrapped: %w", ErrTerminated), want: false},
+		{name: "canceled", policy: &RetryPolicy{MaxAttempts: 3}, err: context.Canceled, want: false},
+		{name: "suspended", policy: &RetryPolicy{MaxAttempts: 3}, err: ErrSuspended, want: false},
+		{name: "blocks exception", policy: &RetryPolicy{MaxAttempts: 3}, err: ErrBlocksException, want: false},
+		{name: "matched pattern", policy: &RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"(?i)TIMEOUT"}}, err: errTimeout, want: true},
+		{name: "unmatched pattern", policy: &RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"rate limit"}}, err: errTimeout, want: false},
+		{name: "invalid pattern is skipped", policy: &RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"(", "timeout"}}, err: errTimeout, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.policy, tt.err); got != tt.want {
+				t.Errorf("isRetryableError() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+﻿package engine
+
+import (
+	"math"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type Snapshot = domain.WorkflowRunSnapshot
+
+func takeSnapshot(variables VariableManager, inputs InOutManager) *Snapshot {
+	snapshot := &Snapshot{
+		Variables: lo.Map(variables.All(), func(state VariableState, _ int) *domain.WorkflowRunSnapshotVariable {
+			return &domain.WorkflowRunSnapshotVariable{
+				Scope:     state.Scope,
+				Key:       state.Key,
+				Value:     state.Value,
+				ValueType: state.ValueType,
+			}
+		}),
+		InOuts: lo.Map(inputs.All(), func(state InOutState, _ int) *domain.WorkflowRunSnapshotInOut {
+			return &domain.WorkflowRunSnapshotInOut{
+				NodeId:     state.NodeId,
+				Type:       state.Type,
+				Name:       state.Name,
+				Value:      state.Value,
+				ValueType:  state.ValueType,
+				Persistent: state.Persistent,
+			}
+		}),
+	}
+
+	if state, ok := variables.Get(stateVarKeyErrorNodeId); ok {
+		snapshot.ErrorNodeId, _ = state.Value.(string)
+	}
+	if state, ok := variables.Get(stateVarKeyWaitingNodeId); ok {
+		snapshot.WaitingNodeId, _ = state.Value.(string)
+	}
+
+	return snapshot
+}
+
+func restoreSnapshot(snapshot *Snapshot, variables VariableManager, inputs InOutManager) {
+	for _, item := range snapshot.Variables {
+		if item == nil {
+			continue
+		}
+
+		variables.Add(VariableState{
+			Scope:     item.Scope,
+			Key:       item.Key,
+			Value:     restoreSnapshotValue(item.Value, item.ValueType),
+			ValueType: item.ValueType,
+		})
+	}
+
+	for _, item := range snapshot.InOuts {
+		if item == nil {
+			continue
+		}
+
+		inputs.Add(InOutState{
+			NodeId:     item.NodeId,
+			Type:       item.Type,
+			Name:       item.Name,
+			Value:      restoreSnapshotValue(item.Value, item.ValueType),
+			ValueType:  item.ValueType,
+			Persistent: item.Persistent,
+		})
+	}
+}
+
+// 快照经 JSON 序列化后会丢失部分类型信息，需按 ValueType 还原为原始类型
+func restoreSnapshotValue(value any, valueType string) any {
+	switch valueType {
+	case "number":
+		if f, ok := value.(float64); ok && f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+			return int64(f)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return false
+		}
+	case "datetime":
+		if s, ok := value.(string); ok {
+			t, _ := time.Parse(time.RFC3339Nano, s)
+			return t
+		} else if _, ok := value.(time.Time); !ok {
+			return time.Time{}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return ""
+		}
+	}
+
+	return value
+}
+
+// 查找指定节点（或包含指定节点的容器节点）在同级节点中的位置
+func indexOfBlockContainingNode(blocks []*Node, nodeId string) int {
+	var contains func(node *Node) bool
+	contains = func(node *Node) bool {
+		if node.Id == nodeId {
+			return true
+		}
+		return lo.SomeBy(node.Blocks, func(n *Node) bool { return contains(n) })
+	}
+
+	_, index, _ := lo.FindIndexOf(blocks, contains)
+	return index
+}
+
+// 恢复运行时，跳过恢复节点（或包含恢复节点的容器节点）之前的同级节点
+func skipBlocksBeforeResumeNode(wfCtx *WorkflowContext, blocks []*Node) []*Node {
+	if resumeNodeId := wfCtx.resumingNodeId(); resumeNodeId != "" {
+		if i := indexOfBlockContainingNode(blocks, resumeNodeId); i > 0 {
+			return blocks[i:]
+		}
+	}
+
+	return blocks
+}
+
+// 判断恢复节点是否位于指定节点列表（含其子节点）中
+func containsResumeNode(wfCtx *WorkflowContext, blocks []*Node) bool {
+	if resumeNodeId := wfCtx.resumingNodeId(); resumeNodeId != "" {
+		return indexOfBlockContainingNode(blocks, resumeNodeId) >= 0
+	}
+
+	return false
+}
@@ -2,8 +2,10 @@
 
 import (
 	"fmt"
+	"math"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,6 +22,9 @@ func (s VariableState) ValueString() string {
 	case "string":
 		return fmt.Sprintf("%s", s.Value)
 	case "number":
+		if f, ok := s.Value.(float64); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
 		return fmt.Sprintf("%d", s.Value)
 	case "boolean":
 		return strconv.FormatBool(s.Value.(bool))
@@ -34,6 +39,20 @@ func (s VariableState) ValueString() string {
 	}
 }
 
+// 将变量转换为表达式求值所需的形式，即以作用域（全局变量为空字符串）和键名索引的二级映射。
+func toExprVariables(states []VariableState) map[string]map[string]any {
+	variables := make(map[string]map[string]any)
+	for _, state := range states {
+		if _, ok := variables[state.Scope]; !ok {
+			variables[state.Scope] = make(map[string]any)
+		}
+
+		// 这里统一把所有值都转换为字符串形式，由表达式按照变量声明的类型再行解析
+		variables[state.Scope][state.Key] = state.ValueString()
+	}
+	return variables
+}
+
 type VariableManager interface {
 	All() []VariableState
 	Erase()
@@ -278,6 +297,49 @@ func newInOutManager() InOutManager {
 	}
 }
 
+// 将触发器携带的 JSON 数据展开为全局变量，嵌套的键以半角句点连接。
+// 例如 `{"ingress":{"host":"example.com"}}` 将展开为变量 "trigger.ingress.host"。
+func flattenPayloadVariables(prefix string, payload map[string]any) []VariableState {
+	states := make([]VariableState, 0)
+	if payload == nil {
+		return states
+	}
+
+	var walk func(key string, value any)
+	walk = func(key string, value any) {
+		switch v := value.(type) {
+		case nil:
+			states = append(states, VariableState{Key: key, Value: "", ValueType: "string"})
+		case string:
+			states = append(states, VariableState{Key: key, Value: v, ValueType: "string"})
+		case bool:
+			states = append(states, VariableState{Key: key, Value: v, ValueType: "boolean"})
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+				states = append(states, VariableState{Key: key, Value: int64(v), ValueType: "number"})
+			} else {
+				states = append(states, VariableState{Key: key, Value: v, ValueType: "number"})
+			}
+		case map[string]any:
+			for k, item := range v {
+				walk(key+"."+k, item)
+			}
+		case []any:
+			for i, item := range v {
+				walk(key+"."+strconv.Itoa(i), item)
+			}
+		default:
+			states = append(states, VariableState{Key: key, Value: fmt.Sprintf("%v", v), ValueType: "string"})
+		}
+	}
+	for k, v := range payload {
+		walk(strings.TrimSuffix(prefix, ".")+"."+k, v)
+	}
+
+	slices.SortFunc(states, func(a, b VariableState) int { return strings.Compare(a.Key, b.Key) })
+	return states
+}
+
 const (
 	stateIOTypeRef = "ref"
 )
@@ -287,9 +349,27 @@ const (
 	stateVarKeyWorkflowName         = "workflow.name"         // ValueType: "string"
 	stateVarKeyRunId                = "run.id"                // ValueType: "string"
 	stateVarKeyRunTrigger           = "run.trigger"           // ValueType: "string"
+	stateVarKeyTriggerPrefix        = "trigger."              // 触发器携带的数据，ValueType 视具体值而定
+	stateVarKeyInputPrefix          = "input."                // 父工作流传入的变量，ValueType: "string"
+	stateVarKeyParentWorkflowId     = "parent.workflowId"     // ValueType: "string"
+	stateVarKeyParentRunId          = "parent.runId"          // ValueType: "string"
 	stateVarKeyNodeId               = "node.id"               // ValueType: "string"
 	stateVarKeyNodeName             = "node.name"             // ValueType: "string"
 	stateVarKeyNodeSkipped          = "node.skipped"          // ValueType: "boolean"
+	stateVarKeySubWorkflowRunId     = "subWorkflow.runId"     // ValueType: "string"
+	stateVarKeyResponseStatus       = "response.status"       // ValueType: "number"
+	stateVarKeyResponseBody         = "response.body"         // ValueType: "string"
+	stateVarKeyResponsePrefix       = "response."             // 从响应中提取的字段，ValueType 视具体值而定
+	stateVarKeyApprovalRunId        = "approval.runId"        // ValueType: "string"
+	stateVarKeyApprovalStatus       = "approval.status"       // ValueType: "string"
+	stateVarKeyApprovalExpiresAt    = "approval.expiresAt"    // ValueType: "datetime"
+	stateVarKeyApprovalApproveUrl   = "approval.approveUrl"   // ValueType: "string"
+	stateVarKeyApprovalRejectUrl    = "approval.rejectUrl"    // ValueType: "string"
+	stateVarKeyApprovalDecidedBy    = "approval.decidedBy"    // ValueType: "string"
+	stateVarKeyApprovalDecidedAt    = "approval.decidedAt"    // ValueType: "datetime"
+	stateVarKeyApprovalComment      = "approval.comment"      // ValueType: "string"
+	stateVarKeyParallelCompleted    = "parallel.completed"    // 已成功完成的分支节点 ID，以分号分隔，ValueType: "string"
+	stateVarKeyWaitingNodeId        = "waiting.nodeId"        // ValueType: "string"
 	stateVarKeyErrorNodeId          = "error.nodeId"          // ValueType: "string"
 	stateVarKeyErrorNodeName        = "error.nodeName"        // ValueType: "string"
 	stateVarKeyErrorMessage         = "error.message"         // ValueType: "string"
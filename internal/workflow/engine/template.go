@@ -0,0 +1,469 @@
+﻿package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// 模板渲染结果的最大长度。
+	templateMaxOutputSize = 1 << 20
+	// 模板渲染的最大耗时。
+	templateExecuteTimeout = 5 * time.Second
+)
+
+var (
+	// 旧版本的模板语法，形如 `{{ $certificate.domains }}`
+	reTemplateLegacyMustache = regexp.MustCompile(`\{\{\s*\$(\w[\w.]*)\s*\}\}`)
+	// 模板中声明的变量，形如 `{{ range $i, $domain := ... }}`
+	reTemplateVariableDecl = regexp.MustCompile(`\$(\w+)\s*(?:,\s*\$(\w+)\s*)?:?=`)
+)
+
+// 模板中的日期时间值，输出时格式化为 RFC 3339 格式。
+type templateTime struct {
+	time.Time
+}
+
+func (t templateTime) String() string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// 校验模板语法。
+func CheckTemplate(text string) error {
+	_, err := parseTemplate(text, newVariableManager(), newInOutManager())
+	return err
+}
+
+// 基于运行状态快照渲染模板。
+func RenderTemplateWithSnapshot(text string, snapshot *Snapshot) (string, error) {
+	variables := newVariableManager()
+	inouts := newInOutManager()
+	if snapshot != nil {
+		restoreSnapshot(snapshot, variables, inouts)
+	}
+
+	return renderTemplate(text, variables, inouts)
+}
+
+// 渲染模板。
+//
+// 模板使用 Go `text/template` 语法，数据源仅包括工作流变量与节点输出：
+//   - 全局变量按键名中的半角句点展开为嵌套结构，如 `{{ .certificate.domain }}`、`{{ .error.message }}`；
+//   - 节点作用域变量通过 `{{ (node "nodeId").certificate.domain }}` 访问；
+//   - 节点输出通过 `{{ output "nodeId" "certificate" }}` 访问；
+//   - 兼容旧版本的 `{{ $certificate.domain }}` 语法。
+//
+// 模板内仅可调用 [templateFuncs] 中的函数，且渲染结果长度与耗时均受限。
+//
+// 若模板语法有误（如文本中本身含有 `{{`），则视为普通文本，仅替换其中旧版本语法的插值。
+func renderTemplate(text string, variables VariableManager, inouts InOutManager) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseTemplate(text, variables, inouts)
+	if err != nil {
+		return renderTemplateLegacy(text, variables), nil
+	}
+
+	normalizeTemplateOutput(tmpl)
+	guardTemplate(tmpl, time.Now().Add(templateExecuteTimeout))
+
+	buf := &templateLimitedBuffer{limit: templateMaxOutputSize}
+	if err := tmpl.Execute(buf, newTemplateData(variables)); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// 以旧版本的方式渲染模板，即仅替换形如 `{{ $certificate.domain }}` 的插值，其余文本原样保留。
+func renderTemplateLegacy(text string, variables VariableManager) string {
+	return reTemplateLegacyMustache.ReplaceAllStringFunc(text, func(match string) string {
+		key := reTemplateLegacyMustache.FindStringSubmatch(match)[1]
+		if key == "now" {
+			return time.Now().Format(time.RFC3339)
+		}
+		if state, ok := variables.Get(key); ok {
+			return state.ValueString()
+		}
+		return match
+	})
+}
+
+// 在模板的每个定义及每个循环体的开头插入超时检查，以中止耗时过长的渲染，
+// 如 `{{ range 1000000000000 }}{{ end }}`，或递归调用自身的模板定义。
+func guardTemplate(tmpl *template.Template, deadline time.Time) {
+	const funcName = "templateTick"
+
+	tmpl.Funcs(template.FuncMap{
+		funcName: func() (string, error) {
+			if time.Now().After(deadline) {
+				return "", errors.New("template execution timed out")
+			}
+			return "", nil
+		},
+	})
+
+	newTick := func(pos parse.Pos) parse.Node {
+		return &parse.ActionNode{
+			NodeType: parse.NodeAction,
+			Pos:      pos,
+			Pipe: &parse.PipeNode{
+				NodeType: parse.NodePipe,
+				Pos:      pos,
+				Cmds: []*parse.CommandNode{{
+					NodeType: parse.NodeCommand,
+					Pos:      pos,
+					Args:     []parse.Node{parse.NewIdentifier(funcName).SetPos(pos)},
+				}},
+			},
+		}
+	}
+
+	var guard func(list *parse.ListNode)
+	guard = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.IfNode:
+				guard(n.List)
+				guard(n.ElseList)
+			case *parse.WithNode:
+				guard(n.List)
+				guard(n.ElseList)
+			case *parse.RangeNode:
+				guard(n.List)
+				guard(n.ElseList)
+				n.List.Nodes = append([]parse.Node{newTick(n.List.Pos)}, n.List.Nodes...)
+			}
+		}
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+
+		guard(t.Tree.Root)
+		t.Tree.Root.Nodes = append([]parse.Node{newTick(t.Tree.Root.Pos)}, t.Tree.Root.Nodes...)
+	}
+}
+
+// 在模板的每个输出动作末尾追加一次函数调用，使不存在的键输出为空字符串而非 `<no value>`，与旧版本语法一致。
+func normalizeTemplateOutput(tmpl *template.Template) {
+	const funcName = "templateOutput"
+
+	tmpl.Funcs(template.FuncMap{
+		funcName: func(v any) string {
+			if v == nil {
+				return ""
+			}
+			return fmt.Sprint(v)
+		},
+	})
+
+	var normalize func(list *parse.ListNode)
+	normalize = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.ActionNode:
+				// 变量声明或赋值不产生输出
+				if len(n.Pipe.Decl) > 0 {
+					continue
+				}
+				n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+					NodeType: parse.NodeCommand,
+					Pos:      n.Pos,
+					Args:     []parse.Node{parse.NewIdentifier(funcName).SetPos(n.Pos)},
+				})
+			case *parse.IfNode:
+				normalize(n.List)
+				normalize(n.ElseList)
+			case *parse.WithNode:
+				normalize(n.List)
+				normalize(n.ElseList)
+			case *parse.RangeNode:
+				normalize(n.List)
+				normalize(n.ElseList)
+			}
+		}
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+
+		normalize(t.Tree.Root)
+	}
+}
+
+func parseTemplate(text string, variables VariableManager, inouts InOutManager) (*template.Template, error) {
+	declared := make(map[string]bool)
+	for _, match := range reTemplateVariableDecl.FindAllStringSubmatch(text, -1) {
+		declared[match[1]] = true
+		if match[2] != "" {
+			declared[match[2]] = true
+		}
+	}
+
+	// 将旧版本语法改写为函数调用，但跳过模板中自行声明的变量
+	text = reTemplateLegacyMustache.ReplaceAllStringFunc(text, func(match string) string {
+		key := reTemplateLegacyMustache.FindStringSubmatch(match)[1]
+		if declared[strings.SplitN(key, ".", 2)[0]] {
+			return match
+		}
+		return fmt.Sprintf("{{ var %q }}", key)
+	})
+
+	tmpl, err := template.New("").
+		Option("missingkey=zero").
+		Funcs(templateFuncs(variables, inouts)).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+func newTemplateData(variables VariableManager) map[string]any {
+	data := make(map[string]any)
+	for _, state := range variables.All() {
+		if state.Scope != "" {
+			continue
+		}
+		setTemplateDataValue(data, state.Key, templateValue(state))
+	}
+	return data
+}
+
+func newTemplateNodeData(variables VariableManager, nodeId string) map[string]any {
+	data := make(map[string]any)
+	for _, state := range variables.All() {
+		if state.Scope != nodeId {
+			continue
+		}
+		setTemplateDataValue(data, state.Key, templateValue(state))
+	}
+	return data
+}
+
+// 按键名中的半角句点将值写入嵌套结构，与已有的非嵌套值冲突时忽略。
+func setTemplateDataValue(data map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := data[part]
+		if !ok {
+			next = make(map[string]any)
+			data[part] = next
+		}
+
+		nextMap, ok := next.(map[string]any)
+		if !ok {
+			return
+		}
+		data = nextMap
+	}
+
+	if _, ok := data[parts[len(parts)-1]].(map[string]any); ok {
+		return
+	}
+	data[parts[len(parts)-1]] = value
+}
+
+func templateValue(state VariableState) any {
+	if state.ValueType == "datetime" {
+		if t, ok := state.Value.(time.Time); ok {
+			return templateTime{t}
+		}
+	}
+	return state.Value
+}
+
+func templateFuncs(variables VariableManager, inouts InOutManager) template.FuncMap {
+	toString := func(v any) string {
+		if v == nil {
+			return ""
+		}
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return fmt.Sprintf("%v", v)
+	}
+
+	toTime := func(v any) (time.Time, error) {
+		switch t := v.(type) {
+		case templateTime:
+			return t.Time, nil
+		case time.Time:
+			return t, nil
+		case string:
+			if t == "" || t == "-" {
+				return time.Time{}, nil
+			}
+			for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+				if tt, err := time.ParseInLocation(layout, t, time.Local); err == nil {
+					return tt, nil
+				}
+			}
+		}
+		return time.Time{}, fmt.Errorf("value is not a datetime: %v", v)
+	}
+
+	return template.FuncMap{
+		// 兼容旧版本语法
+		"var": func(key string) string {
+			if key == "now" {
+				return time.Now().Format(time.RFC3339)
+			}
+			if state, ok := variables.Get(key); ok {
+				return state.ValueString()
+			}
+			return fmt.Sprintf("{{ $%s }}", key)
+		},
+
+		"node": func(nodeId string) map[string]any {
+			return newTemplateNodeData(variables, nodeId)
+		},
+
+		"output": func(nodeId, name string) any {
+			if state, ok := inouts.Get(nodeId, name); ok {
+				return state.Value
+			}
+			return nil
+		},
+
+		"now": func() templateTime {
+			return templateTime{time.Now()}
+		},
+
+		// 格式化日期时间，布局可以是 Go 时间布局，或 "rfc3339"、"date"、"datetime" 之一
+		"date": func(layout string, v any) (string, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return "", err
+			}
+			if t.IsZero() {
+				return "-", nil
+			}
+
+			switch strings.ToLower(layout) {
+			case "rfc3339":
+				layout = time.RFC3339
+			case "date":
+				layout = time.DateOnly
+			case "datetime":
+				layout = time.DateTime
+			}
+			return t.Local().Format(layout), nil
+		},
+
+		"split": func(sep string, v any) []string {
+			s := toString(v)
+			if s == "" {
+				return []string{}
+			}
+			return strings.Split(s, sep)
+		},
+
+		"join": func(sep string, v any) (string, error) {
+			switch items := v.(type) {
+			case []string:
+				return strings.Join(items, sep), nil
+			case []any:
+				strs := make([]string, 0, len(items))
+				for _, item := range items {
+					strs = append(strs, toString(item))
+				}
+				return strings.Join(strs, sep), nil
+			default:
+				return "", fmt.Errorf("value is not a list: %v", v)
+			}
+		},
+
+		"default": func(def any, v any) any {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+
+		"upper":     func(v any) string { return strings.ToUpper(toString(v)) },
+		"lower":     func(v any) string { return strings.ToLower(toString(v)) },
+		"trim":      func(v any) string { return strings.TrimSpace(toString(v)) },
+		"replace":   func(old, new string, v any) string { return strings.ReplaceAll(toString(v), old, new) },
+		"hasPrefix": func(prefix string, v any) bool { return strings.HasPrefix(toString(v), prefix) },
+		"hasSuffix": func(suffix string, v any) bool { return strings.HasSuffix(toString(v), suffix) },
+
+		// 列表是否包含元素，与条件表达式中的 contains 含义一致：
+		// 字符串视为以分号分隔的列表（如 certificate.domains），按元素而非子串匹配；如需匹配子串，请使用 matches
+		"contains": func(item any, v any) bool {
+			var items []string
+			switch list := v.(type) {
+			case []string:
+				items = list
+			case []any:
+				for _, e := range list {
+					items = append(items, toString(e))
+				}
+			default:
+				for _, e := range strings.Split(toString(v), ";") {
+					items = append(items, strings.TrimSpace(e))
+				}
+			}
+			return slices.Contains(items, toString(item))
+		},
+
+		// 编码为 JSON 值，字符串会加上引号并转义，适用于在 JSON 请求体中嵌入变量
+		"json": func(v any) (string, error) {
+			buf := &bytes.Buffer{}
+			encoder := json.NewEncoder(buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(v); err != nil {
+				return "", fmt.Errorf("failed to encode value as json: %w", err)
+			}
+			return strings.TrimSpace(buf.String()), nil
+		},
+
+		// 正则表达式匹配
+		"matches": func(pattern string, v any) (bool, error) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return false, fmt.Errorf("invalid regular expression: %w", err)
+			}
+			return re.MatchString(toString(v)), nil
+		},
+	}
+}
+
+type templateLimitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *templateLimitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errors.New("template output is too large")
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,106 @@
+﻿package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	variables := newVariableManager()
+	variables.Set("workflow.name", "demo", "string")
+	variables.Set("workflow.description", `a "quoted" <name>`, "string")
+	variables.Set("certificate.domains", "example.com;www.example.com", "string")
+	variables.Set("certificate.notAfter", time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC), "datetime")
+	variables.SetScoped("node1", "certificate.domain", "example.com", "string")
+
+	inouts := newInOutManager()
+	inouts.Set("node1", "ref", "certificate", "cert-id", "string", false)
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain text", text: "hello", want: "hello"},
+		{name: "global variable", text: "{{ .workflow.name }}", want: "demo"},
+		{name: "missing variable", text: "[{{ .workflow.missing }}]", want: "[]"},
+		{name: "missing variable in pipeline", text: `[{{ .workflow.missing | default "-" }}][{{ range split ";" .certificate.domains }}{{ $.workflow.missing }}{{ end }}]`, want: "[-][]"},
+		{name: "datetime variable", text: "{{ .certificate.notAfter }}", want: "2000-01-02T03:04:05Z"},
+		{name: "node variable", text: `{{ (node "node1").certificate.domain }}`, want: "example.com"},
+		{name: "node output", text: `{{ output "node1" "certificate" }}`, want: "cert-id"},
+		{name: "legacy syntax", text: "{{ $workflow.name }}", want: "demo"},
+		{name: "legacy syntax of missing variable", text: "{{ $workflow.missing }}", want: "{{ $workflow.missing }}"},
+		{name: "declared variable", text: `{{ range $domain := split ";" .certificate.domains }}[{{ $domain }}]{{ end }}`, want: "[example.com][www.example.com]"},
+		{name: "functions", text: `{{ .certificate.domains | split ";" | join "," | upper }}`, want: "EXAMPLE.COM,WWW.EXAMPLE.COM"},
+		{name: "contains element", text: `{{ contains "www.example.com" .certificate.domains }}`, want: "true"},
+		{name: "contains is not substring", text: `{{ contains "example" .certificate.domains }}`, want: "false"},
+		{name: "contains in list", text: `{{ .certificate.domains | split ";" | contains "example.com" }}`, want: "true"},
+		{name: "json string", text: `{"name": {{ .workflow.description | json }}}`, want: `{"name": "a \"quoted\" <name>"}`},
+		{name: "json list", text: `{{ .certificate.domains | split ";" | json }}`, want: `["example.com","www.example.com"]`},
+		{name: "matches", text: `{{ matches "^example\\.com;" .certificate.domains }}`, want: "true"},
+		{name: "default", text: `{{ .workflow.missing | default "-" }}`, want: "-"},
+		{name: "date", text: `{{ date "2006" .certificate.notAfter }}`, want: "2000"},
+		{name: "date of invalid value", text: `{{ date "2006" .workflow.name }}`, wantErr: true},
+
+		// 语法有误时视为普通文本，仅替换旧版本语法的插值
+		{name: "literal braces", text: "use {{ in text", want: "use {{ in text"},
+		{name: "literal braces with legacy syntax", text: "{{ $workflow.name }}: {{ not a template }}", want: "demo: {{ not a template }}"},
+		{name: "unknown function", text: "{{ foo }}", want: "{{ foo }}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate(tt.text, variables, inouts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("renderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("renderTemplate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate_OutputLimit(t *testing.T) {
+	text := `{{ range $i := split "," "` + strings.Repeat(",", 1024) + `" }}` + strings.Repeat("x", 2048) + `{{ end }}`
+	if _, err := renderTemplate(text, newVariableManager(), newInOutManager()); err == nil {
+		t.Errorf("expected output limit error, got nil")
+	}
+}
+
+func TestGuardTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "range over big int", text: `{{ range 1000000000000 }}{{ end }}`},
+		{name: "nested range", text: `{{ range 1000000 }}{{ range 1000000 }}{{ end }}{{ end }}`},
+		{name: "recursive template", text: `{{ define "a" }}{{ template "a" }}{{ template "a" }}{{ end }}{{ template "a" }}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseTemplate(tt.text, newVariableManager(), newInOutManager())
+			if err != nil {
+				t.Fatalf("parseTemplate() error = %v", err)
+			}
+
+			guardTemplate(tmpl, time.Now().Add(100*time.Millisecond))
+
+			done := make(chan error, 1)
+			go func() {
+				done <- tmpl.Execute(&templateLimitedBuffer{limit: templateMaxOutputSize}, nil)
+			}()
+
+			select {
+			case err := <-done:
+				if err == nil || !strings.Contains(err.Error(), "timed out") {
+					t.Errorf("Execute() error = %v, want timed out", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("Execute() did not stop after the deadline")
+			}
+		})
+	}
+}
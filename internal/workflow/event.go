@@ -2,26 +2,65 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"reflect"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/samber/lo"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
-	"github.com/certimate-go/certimate/internal/domain/dtos"
 	"github.com/certimate-go/certimate/internal/repository"
 )
 
 func Register() {
 	pb := app.GetApp()
-	pb.OnRecordCreateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+	pb.OnRecordCreate(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordEvent) error {
+		onWorkflowRecordBeforeSave(e.Record)
+		return e.Next()
+	})
+	pb.OnRecordUpdate(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordEvent) error {
+		onWorkflowRecordBeforeSave(e.Record)
+		return e.Next()
+	})
+	pb.OnRecordEnrich(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordEnrichEvent) error {
 		if err := e.Next(); err != nil {
 			return err
 		}
 
+		// 超级用户默认可见隐藏字段（在后续的钩子中解除隐藏），此处显式隐藏签名密钥，使其仅能通过生成密钥的接口获取
+		e.Record.Hide("webhookSecret")
+		return nil
+	})
+	pb.OnRecordCreateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+		onWorkflowRecordBeforeCreateRequest(e.Record)
+
+		if err := onWorkflowRecordBeforePublish(e.Request.Context(), e.Record); err != nil {
+			return err
+		}
+
+		// 保存工作流与记录版本历史在同一事务中完成
+		if err := e.App.RunInTransaction(func(txApp core.App) error {
+			e.App = txApp
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if err := onWorkflowRecordPublish(repository.WithTransaction(e.Request.Context(), txApp), e); err != nil {
+				app.GetLogger().Error(err.Error())
+				return err
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
 		if err := onWorkflowRecordCreateOrUpdate(e.Request.Context(), e.Record); err != nil {
 			app.GetLogger().Error(err.Error())
 			return err
@@ -30,7 +69,28 @@ func Register() {
 		return nil
 	})
 	pb.OnRecordUpdateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
-		if err := e.Next(); err != nil {
+		if err := onWorkflowRecordBeforeUpdateRequest(e.Record); err != nil {
+			return err
+		}
+
+		if err := onWorkflowRecordBeforePublish(e.Request.Context(), e.Record); err != nil {
+			return err
+		}
+
+		// 保存工作流与记录版本历史在同一事务中完成
+		if err := e.App.RunInTransaction(func(txApp core.App) error {
+			e.App = txApp
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if err := onWorkflowRecordPublish(repository.WithTransaction(e.Request.Context(), txApp), e); err != nil {
+				app.GetLogger().Error(err.Error())
+				return err
+			}
+
+			return nil
+		}); err != nil {
 			return err
 		}
 
@@ -42,6 +102,10 @@ func Register() {
 		return nil
 	})
 	pb.OnRecordDeleteRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := onWorkflowRecordBeforeDeleteRequest(e.Record); err != nil {
+			return err
+		}
+
 		if err := e.Next(); err != nil {
 			return err
 		}
@@ -55,6 +119,92 @@ func Register() {
 	})
 }
 
+func onWorkflowRecordBeforeSave(record *core.Record) {
+	// 以 Webhook 触发的工作流，如果尚未生成密钥，则自动生成
+	if record.GetString("trigger") == string(domain.WorkflowTriggerTypeWebhook) && record.GetString("webhookKey") == "" {
+		record.Set("webhookKey", security.RandomString(32))
+	}
+}
+
+func onWorkflowRecordBeforeCreateRequest(record *core.Record) {
+	// Webhook 签名密钥仅允许通过专用接口生成
+	record.Set("webhookSecret", "")
+
+	// GitOps 相关字段仅允许由同步任务写入
+	record.Set("gitopsRef", "")
+	record.Set("gitopsChecksum", "")
+	record.Set("gitopsDrifted", false)
+}
+
+func onWorkflowRecordBeforeUpdateRequest(record *core.Record) error {
+	original := record.Original()
+
+	// Webhook 签名密钥仅允许通过专用接口生成
+	record.Set("webhookSecret", original.Get("webhookSecret"))
+
+	// GitOps 相关字段仅允许由同步任务写入
+	record.Set("gitopsRef", original.Get("gitopsRef"))
+	record.Set("gitopsChecksum", original.Get("gitopsChecksum"))
+	record.Set("gitopsDrifted", original.Get("gitopsDrifted"))
+
+	// 受 GitOps 管理的工作流，修改受管理的字段时，按模式拒绝修改或标记为偏离清单；草稿不受限制
+	changedFields := lo.Filter(gitopsManagedFields, func(field string, _ int) bool { return isRecordFieldChanged(record, field) })
+	if drifted, err := checkGitOpsChange(original.GetString("gitopsRef"), changedFields); err != nil {
+		return router.NewForbiddenError(err.Error(), nil)
+	} else if drifted {
+		record.Set("gitopsDrifted", true)
+	}
+
+	return nil
+}
+
+func onWorkflowRecordBeforeDeleteRequest(record *core.Record) error {
+	if !IsGitOpsEnabled() || record.GetString("gitopsRef") == "" {
+		return nil
+	}
+
+	if gitopsMode == domain.WorkflowGitOpsModeTypeBlock {
+		return router.NewForbiddenError("The workflow is managed by GitOps and cannot be deleted.", nil)
+	}
+
+	return nil
+}
+
+func onWorkflowRecordBeforePublish(ctx context.Context, record *core.Record) error {
+	// 发布流程图或修改触发器时，先进行静态校验，以免发布无效的流程图
+	graphChanged := isRecordFieldChanged(record, "graphContent")
+	triggerChanged := isRecordFieldChanged(record, "trigger") || isRecordFieldChanged(record, "triggerCron") || isRecordFieldChanged(record, "triggerEvent")
+	if !graphChanged && !triggerChanged {
+		return nil
+	}
+
+	workflow := &domain.Workflow{
+		Meta:        domain.Meta{Id: record.Id},
+		Trigger:     domain.WorkflowTriggerType(record.GetString("trigger")),
+		TriggerCron: record.GetString("triggerCron"),
+	}
+	if err := record.UnmarshalJSONField("triggerEvent", &workflow.TriggerEvent); err != nil {
+		return router.NewBadRequestError("Field 'triggerEvent' is malformed.", nil)
+	}
+
+	var graph *domain.WorkflowGraph
+	if graphChanged {
+		graph = &domain.WorkflowGraph{}
+		if err := record.UnmarshalJSONField("graphContent", graph); err != nil {
+			return router.NewBadRequestError("Field 'graphContent' is malformed.", nil)
+		} else if len(graph.Nodes) == 0 {
+			graph = nil
+		}
+	}
+
+	workflowSrv := NewWorkflowService(repository.NewWorkflowRepository(), repository.NewWorkflowRunRepository(), repository.NewWorkflowVersionRepository(), repository.NewCertificateRepository(), repository.NewAccessRepository(), repository.NewSettingsRepository(), repository.NewPrivateCARepository())
+	if err := workflowSrv.validateGraph(ctx, workflow, graph).Err(); err != nil {
+		return router.NewBadRequestError(err.Error(), nil)
+	}
+
+	return nil
+}
+
 func onWorkflowRecordCreateOrUpdate(ctx context.Context, record *core.Record) error {
 	scheduler := app.GetScheduler()
 
@@ -75,14 +225,8 @@ func onWorkflowRecordCreateOrUpdate(ctx context.Context, record *core.Record) er
 	if job == nil || job.Expression() != triggerCron {
 		workflowId := record.Id
 		err := scheduler.Add(jobId, triggerCron, func() {
-			workflowSrv := NewWorkflowService(repository.NewWorkflowRepository(), repository.NewWorkflowRunRepository(), repository.NewSettingsRepository())
-			_, err := workflowSrv.StartRun(context.Background(), &dtos.WorkflowStartRunReq{
-				WorkflowId: workflowId,
-				RunTrigger: domain.WorkflowTriggerTypeScheduled,
-			})
-			if err != nil {
-				app.GetLogger().Warn(fmt.Sprintf("failed to start scheduled run for workflow #%s", workflowId), slog.Any("error", err))
-			}
+			workflowSrv := NewWorkflowService(repository.NewWorkflowRepository(), repository.NewWorkflowRunRepository(), repository.NewWorkflowVersionRepository(), repository.NewCertificateRepository(), repository.NewAccessRepository(), repository.NewSettingsRepository(), repository.NewPrivateCARepository())
+			workflowSrv.startScheduledRun(context.Background(), workflowId)
 		})
 		if err != nil {
 			app.GetLogger().Error(fmt.Sprintf("failed to register cron job for workflow #%s", workflowId), slog.Any("error", err))
@@ -93,6 +237,24 @@ func onWorkflowRecordCreateOrUpdate(ctx context.Context, record *core.Record) er
 	return nil
 }
 
+func onWorkflowRecordPublish(ctx context.Context, e *core.RecordRequestEvent) error {
+	// 发布流程图时，记录版本历史；修改说明可通过请求体中的 `versionNote` 字段传入
+	var author, note string
+	if e.Auth != nil {
+		author = lo.CoalesceOrEmpty(e.Auth.Email(), e.Auth.Id)
+	}
+	if info, err := e.RequestInfo(); err == nil {
+		note, _ = info.Body["versionNote"].(string)
+	}
+
+	workflowSrv := NewWorkflowService(repository.NewWorkflowRepository(), repository.NewWorkflowRunRepository(), repository.NewWorkflowVersionRepository(), repository.NewCertificateRepository(), repository.NewAccessRepository(), repository.NewSettingsRepository(), repository.NewPrivateCARepository())
+	if _, err := workflowSrv.recordVersion(ctx, e.Record.Id, author, note); err != nil {
+		return fmt.Errorf("failed to record workflow version: %w", err)
+	}
+
+	return nil
+}
+
 func onWorkflowRecordDelete(_ context.Context, record *core.Record) error {
 	scheduler := app.GetScheduler()
 
@@ -102,3 +264,15 @@ func onWorkflowRecordDelete(_ context.Context, record *core.Record) error {
 
 	return nil
 }
+
+func isRecordFieldChanged(record *core.Record, field string) bool {
+	// JSON 字段的原始内容可能仅空白或键序不同，需规范化后再比较
+	normalize := func(value any) any {
+		data, _ := json.Marshal(value)
+		var normalized any
+		_ = json.Unmarshal(data, &normalized)
+		return normalized
+	}
+
+	return !reflect.DeepEqual(normalize(record.Original().Get(field)), normalize(record.Get(field)))
+}
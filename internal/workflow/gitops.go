@@ -0,0 +1,598 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+var (
+	gitopsDir     = ""
+	gitopsMode    = domain.WorkflowGitOpsModeTypeFlag
+	gitopsPrune   = false
+	gitopsGitPull = false
+)
+
+var gitopsSyncMtx sync.Mutex
+
+// 最近一次同步报告持久化为系统设置，以便多实例模式下各实例均能获取。
+const gitopsReportSettingsName = "gitopsReport"
+
+func init() {
+	gitopsDir = os.Getenv("CERTIMATE_GITOPS_DIR")
+
+	envMode := os.Getenv("CERTIMATE_GITOPS_MODE")
+	if envMode == string(domain.WorkflowGitOpsModeTypeBlock) {
+		gitopsMode = domain.WorkflowGitOpsModeTypeBlock
+	}
+
+	envPrune := os.Getenv("CERTIMATE_GITOPS_PRUNE")
+	if envPrune == "1" || envPrune == "true" {
+		gitopsPrune = true
+	}
+
+	envGitPull := os.Getenv("CERTIMATE_GITOPS_GIT_PULL")
+	if envGitPull == "1" || envGitPull == "true" {
+		gitopsGitPull = true
+	}
+}
+
+// 受 GitOps 管理的字段，在界面上修改这些字段将被视为偏离清单。
+var gitopsManagedFields = []string{
+	"name",
+	"description",
+	"trigger",
+	"triggerCron",
+	"triggerEvent",
+	"priority",
+	"maxConcurrentRuns",
+	"enabled",
+	"graphContent",
+}
+
+// GitOps 清单文件。与导出包格式相同，并额外支持声明工作流是否启用。
+type gitopsManifest struct {
+	Version   string                         `json:"version"`
+	Workflows []*gitopsManifestWorkflow      `json:"workflows"`
+	Accesses  []*domain.WorkflowBundleAccess `json:"accesses,omitempty"`
+}
+
+type gitopsManifestWorkflow struct {
+	domain.WorkflowBundleWorkflow
+	Enabled bool `json:"enabled"`
+}
+
+// 工作流的期望状态，其字段名与 `gitopsManagedFields` 一一对应。
+type gitopsWorkflowSpec struct {
+	Name              string                       `json:"name"`
+	Description       string                       `json:"description"`
+	Trigger           domain.WorkflowTriggerType   `json:"trigger"`
+	TriggerCron       string                       `json:"triggerCron"`
+	TriggerEvent      *domain.WorkflowTriggerEvent `json:"triggerEvent"`
+	Priority          int32                        `json:"priority"`
+	MaxConcurrentRuns int32                        `json:"maxConcurrentRuns"`
+	Enabled           bool                         `json:"enabled"`
+	GraphContent      *domain.WorkflowGraph        `json:"graphContent"`
+}
+
+func newGitOpsWorkflowSpec(workflow *domain.Workflow) *gitopsWorkflowSpec {
+	return &gitopsWorkflowSpec{
+		Name:              workflow.Name,
+		Description:       workflow.Description,
+		Trigger:           workflow.Trigger,
+		TriggerCron:       workflow.TriggerCron,
+		TriggerEvent:      workflow.TriggerEvent,
+		Priority:          workflow.Priority,
+		MaxConcurrentRuns: workflow.MaxConcurrentRuns,
+		Enabled:           workflow.Enabled,
+		GraphContent:      workflow.GraphContent,
+	}
+}
+
+func (spec *gitopsWorkflowSpec) Checksum() string {
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// 返回与另一期望状态不一致的字段名。
+func (spec *gitopsWorkflowSpec) Diff(other *gitopsWorkflowSpec) []string {
+	toFields := func(s *gitopsWorkflowSpec) map[string]json.RawMessage {
+		fields := make(map[string]json.RawMessage)
+		data, _ := json.Marshal(s)
+		_ = json.Unmarshal(data, &fields)
+		return fields
+	}
+
+	fields, otherFields := toFields(spec), toFields(other)
+	return lo.Filter(gitopsManagedFields, func(field string, _ int) bool {
+		return !bytes.Equal(fields[field], otherFields[field])
+	})
+}
+
+func IsGitOpsEnabled() bool {
+	return gitopsDir != ""
+}
+
+// 校验对工作流受 GitOps 管理字段的修改。
+// 阻止模式下拒绝修改并返回错误；标记模式下允许修改，并返回工作流是否应被标记为偏离清单。
+func checkGitOpsChange(workflowGitOpsRef string, changedFields []string) (drifted bool, err error) {
+	if !IsGitOpsEnabled() || workflowGitOpsRef == "" || len(changedFields) == 0 {
+		return false, nil
+	}
+
+	if gitopsMode == domain.WorkflowGitOpsModeTypeBlock {
+		return false, fmt.Errorf("The workflow is managed by GitOps, fields %s cannot be modified.", strings.Join(changedFields, ", "))
+	}
+
+	return true, nil
+}
+
+func (s *WorkflowService) GetGitOpsStatus(ctx context.Context) (*dtos.WorkflowGitOpsStatusResp, error) {
+	if !IsGitOpsEnabled() {
+		return &dtos.WorkflowGitOpsStatusResp{Enabled: false}, nil
+	}
+
+	report, err := s.getGitOpsReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowGitOpsStatusResp{
+		Enabled: true,
+		Dir:     gitopsDir,
+		Mode:    gitopsMode,
+		Prune:   gitopsPrune,
+		Report:  report,
+	}, nil
+}
+
+func (s *WorkflowService) SyncGitOps(ctx context.Context) (*dtos.WorkflowGitOpsStatusResp, error) {
+	if !IsGitOpsEnabled() {
+		return nil, domain.NewError(400, "gitops mode is not enabled")
+	}
+
+	// 与定时同步使用相同的租约，以免多个实例同时同步
+	if acquired, err := s.acquireGitOpsSyncLease(ctx); err != nil {
+		return nil, err
+	} else if !acquired {
+		return nil, domain.NewError(409, "gitops sync is running on another instance, please try again later")
+	}
+
+	s.syncGitOps(ctx)
+	return s.GetGitOpsStatus(ctx)
+}
+
+func (s *WorkflowService) startScheduledGitOpsSync(ctx context.Context) {
+	// 多实例模式下仅由获取到租约的实例同步，以免重复创建工作流
+	if acquired, err := s.acquireGitOpsSyncLease(ctx); err != nil {
+		app.GetLogger().Error("failed to acquire lease", slog.Any("error", err))
+		return
+	} else if !acquired {
+		return
+	}
+
+	s.syncGitOps(ctx)
+}
+
+func (s *WorkflowService) acquireGitOpsSyncLease(ctx context.Context) (bool, error) {
+	leaseName := fmt.Sprintf("syncWorkflowGitOps@%s", time.Now().Truncate(time.Minute).Format(time.RFC3339))
+	acquired, err := s.cluster.TryAcquireLease(ctx, leaseName, time.Hour)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease '%s': %w", leaseName, err)
+	}
+
+	return acquired, nil
+}
+
+func (s *WorkflowService) getGitOpsReport(ctx context.Context) (*domain.WorkflowGitOpsReport, error) {
+	settings, err := s.settingsRepo.GetByName(ctx, gitopsReportSettingsName)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	report := &domain.WorkflowGitOpsReport{}
+	data, _ := json.Marshal(settings.Content)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse gitops report: %w", err)
+	}
+
+	return report, nil
+}
+
+func (s *WorkflowService) saveGitOpsReport(ctx context.Context, report *domain.WorkflowGitOpsReport) error {
+	content := make(domain.SettingsContent)
+	data, _ := json.Marshal(report)
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+
+	_, err := s.settingsRepo.Save(ctx, &domain.Settings{Name: gitopsReportSettingsName, Content: content})
+	return err
+}
+
+func (s *WorkflowService) syncGitOps(ctx context.Context) {
+	gitopsSyncMtx.Lock()
+	defer gitopsSyncMtx.Unlock()
+
+	report, err := s.reconcileGitOps(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to sync workflows from gitops manifests", slog.String("dir", gitopsDir), slog.Any("error", err))
+		report.Error = err.Error()
+	}
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case domain.WorkflowGitOpsSyncStatusTypeDrifted, domain.WorkflowGitOpsSyncStatusTypeOrphaned:
+			app.GetLogger().Warn(fmt.Sprintf("gitops: workflow '%s' is %s", item.Ref, item.Status), slog.String("workflowId", item.WorkflowId), slog.Any("changes", item.Changes))
+		case domain.WorkflowGitOpsSyncStatusTypeFailed:
+			app.GetLogger().Error(fmt.Sprintf("gitops: failed to sync workflow '%s'", item.Ref), slog.String("workflowId", item.WorkflowId), slog.String("error", item.Error))
+		case domain.WorkflowGitOpsSyncStatusTypeSynced:
+		default:
+			app.GetLogger().Info(fmt.Sprintf("gitops: workflow '%s' is %s", item.Ref, item.Status), slog.String("workflowId", item.WorkflowId))
+		}
+	}
+
+	if err := s.saveGitOpsReport(ctx, report); err != nil {
+		app.GetLogger().Error("failed to save gitops report", slog.Any("error", err))
+	}
+}
+
+func (s *WorkflowService) reconcileGitOps(ctx context.Context) (*domain.WorkflowGitOpsReport, error) {
+	report := &domain.WorkflowGitOpsReport{
+		SyncedAt: time.Now(),
+		Items:    make([]*domain.WorkflowGitOpsReportItem, 0),
+	}
+
+	if gitopsGitPull {
+		cmd := exec.CommandContext(ctx, "git", "-C", gitopsDir, "pull", "--ff-only")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return report, fmt.Errorf("failed to pull git repository: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	bundle, manifestWorkflows, manifestFiles, err := loadGitOpsManifests(gitopsDir)
+	if err != nil {
+		return report, err
+	}
+
+	// 清单中声明的授权记录按名称和提供商匹配本地记录，须唯一匹配
+	var errs []error
+	accessMappings := make(map[string]string) // Key: AccessRef
+	for _, bundleAccess := range bundle.Accesses {
+		accesses, err := s.accessRepo.ListByNameAndProvider(ctx, bundleAccess.Name, bundleAccess.Provider)
+		if err != nil {
+			return report, err
+		}
+
+		switch len(accesses) {
+		case 0:
+			errs = append(errs, fmt.Errorf("access '%s' (name: '%s', provider: '%s') does not exist", bundleAccess.Ref, bundleAccess.Name, bundleAccess.Provider))
+		case 1:
+			accessMappings[bundleAccess.Ref] = accesses[0].Id
+		default:
+			errs = append(errs, fmt.Errorf("access '%s' (name: '%s', provider: '%s') is ambiguous, %d accesses matched", bundleAccess.Ref, bundleAccess.Name, bundleAccess.Provider, len(accesses)))
+		}
+	}
+	if len(errs) > 0 {
+		return report, errors.Join(errs...)
+	}
+
+	// 先校验全部清单，校验通过后再写入，以免只同步部分工作流
+	accessIds, err := s.verifyBundle(ctx, bundle, accessMappings)
+	if err != nil {
+		return report, err
+	}
+
+	managedWorkflows, err := s.workflowRepo.ListGitOpsManaged(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	workflows := make(map[string]*domain.Workflow) // Key: WorkflowRef
+	for _, workflow := range managedWorkflows {
+		workflows[workflow.GitOpsRef] = workflow
+	}
+
+	// 在同一事务中写入全部变更，任一失败时全部回滚，以免只同步部分工作流；
+	// 定时任务不受事务控制，待事务提交后再更新
+	appliedIds := make([]string, 0)
+	prunedIds := make([]string, 0)
+	err = repository.RunInTransaction(ctx, func(txCtx context.Context) error {
+		// 先创建缺失的工作流记录，以便将子工作流占位符替换为工作流 ID
+		createdRefs := make(map[string]bool)
+		for _, bundleWorkflow := range bundle.Workflows {
+			if _, ok := workflows[bundleWorkflow.Ref]; ok {
+				continue
+			}
+
+			workflow := &domain.Workflow{
+				Name:      bundleWorkflow.Name,
+				Trigger:   bundleWorkflow.Trigger,
+				GitOpsRef: bundleWorkflow.Ref,
+			}
+			if workflow, err = s.workflowRepo.Save(txCtx, workflow); err != nil {
+				return fmt.Errorf("failed to create workflow '%s': %w", bundleWorkflow.Ref, err)
+			}
+
+			workflows[bundleWorkflow.Ref] = workflow
+			createdRefs[bundleWorkflow.Ref] = true
+		}
+
+		workflowIds := lo.MapValues(workflows, func(w *domain.Workflow, _ string) string { return w.Id })
+		for _, bundleWorkflow := range bundle.Workflows {
+			workflow := workflows[bundleWorkflow.Ref]
+			item := &domain.WorkflowGitOpsReportItem{
+				Ref:        bundleWorkflow.Ref,
+				File:       manifestFiles[bundleWorkflow.Ref],
+				WorkflowId: workflow.Id,
+				Name:       bundleWorkflow.Name,
+			}
+			report.Items = append(report.Items, item)
+
+			graph, err := cloneWorkflowGraph(bundleWorkflow.Graph)
+			if err != nil {
+				return err
+			}
+			replaceBundlePlaceholders(graph, accessIds, workflowIds)
+
+			spec := &gitopsWorkflowSpec{
+				Name:              bundleWorkflow.Name,
+				Description:       bundleWorkflow.Description,
+				Trigger:           bundleWorkflow.Trigger,
+				TriggerCron:       bundleWorkflow.TriggerCron,
+				TriggerEvent:      bundleWorkflow.TriggerEvent,
+				Priority:          bundleWorkflow.Priority,
+				MaxConcurrentRuns: bundleWorkflow.MaxConcurrentRuns,
+				Enabled:           manifestWorkflows[bundleWorkflow.Ref].Enabled,
+				GraphContent:      graph,
+			}
+
+			var note string
+			item.Status, item.Changes = resolveGitOpsSyncStatus(workflow, spec, gitopsMode)
+			switch {
+			case createdRefs[bundleWorkflow.Ref]:
+				item.Status = domain.WorkflowGitOpsSyncStatusTypeCreated
+				item.Changes = nil
+				note = fmt.Sprintf("created from %s", item.File)
+
+			case item.Status == domain.WorkflowGitOpsSyncStatusTypeUpdated:
+				note = fmt.Sprintf("synced from %s", item.File)
+
+			case item.Status == domain.WorkflowGitOpsSyncStatusTypeReverted:
+				note = fmt.Sprintf("reverted to %s", item.File)
+
+			default:
+				drifted := item.Status == domain.WorkflowGitOpsSyncStatusTypeDrifted
+				if workflow.GitOpsDrifted != drifted {
+					workflow.GitOpsDrifted = drifted
+					if _, err := s.workflowRepo.Save(txCtx, workflow); err != nil {
+						item.Status = domain.WorkflowGitOpsSyncStatusTypeFailed
+						item.Error = err.Error()
+						return fmt.Errorf("failed to sync workflow '%s': %w", bundleWorkflow.Ref, err)
+					}
+				}
+				continue
+			}
+
+			if err := s.applyGitOpsSpec(txCtx, workflow, spec, note); err != nil {
+				item.Status = domain.WorkflowGitOpsSyncStatusTypeFailed
+				item.Error = err.Error()
+				return fmt.Errorf("failed to sync workflow '%s': %w", bundleWorkflow.Ref, err)
+			}
+
+			appliedIds = append(appliedIds, workflow.Id)
+		}
+
+		// 清单中已不存在的工作流，仅在启用清理时删除，否则仅报告
+		for _, workflow := range findGitOpsOrphanedWorkflows(managedWorkflows, manifestWorkflows) {
+			item := &domain.WorkflowGitOpsReportItem{
+				Ref:        workflow.GitOpsRef,
+				WorkflowId: workflow.Id,
+				Name:       workflow.Name,
+				Status:     domain.WorkflowGitOpsSyncStatusTypeOrphaned,
+			}
+			report.Items = append(report.Items, item)
+
+			if gitopsPrune {
+				if err := s.workflowRepo.DeleteById(txCtx, workflow.Id); err != nil {
+					item.Status = domain.WorkflowGitOpsSyncStatusTypeFailed
+					item.Error = err.Error()
+					return fmt.Errorf("failed to prune workflow '%s': %w", workflow.GitOpsRef, err)
+				}
+
+				item.Status = domain.WorkflowGitOpsSyncStatusTypePruned
+				prunedIds = append(prunedIds, workflow.Id)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// 事务已回滚，此前报告为已写入的变更均未生效
+		for _, item := range report.Items {
+			switch item.Status {
+			case domain.WorkflowGitOpsSyncStatusTypeCreated,
+				domain.WorkflowGitOpsSyncStatusTypeUpdated,
+				domain.WorkflowGitOpsSyncStatusTypeReverted,
+				domain.WorkflowGitOpsSyncStatusTypePruned:
+				item.Status = domain.WorkflowGitOpsSyncStatusTypeFailed
+				item.Error = "rolled back"
+			}
+		}
+		return report, err
+	}
+
+	// 复用记录保存时的逻辑，以同步更新定时任务
+	for _, workflowId := range appliedIds {
+		record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflow, workflowId)
+		if err != nil {
+			return report, err
+		}
+		if err := onWorkflowRecordCreateOrUpdate(ctx, record); err != nil {
+			return report, err
+		}
+	}
+	for _, workflowId := range prunedIds {
+		app.GetScheduler().Remove(fmt.Sprintf("workflow#%s", workflowId))
+	}
+
+	return report, nil
+}
+
+func (s *WorkflowService) applyGitOpsSpec(ctx context.Context, workflow *domain.Workflow, spec *gitopsWorkflowSpec, note string) error {
+	workflow.Name = spec.Name
+	workflow.Description = spec.Description
+	workflow.Trigger = spec.Trigger
+	workflow.TriggerCron = spec.TriggerCron
+	workflow.TriggerEvent = spec.TriggerEvent
+	workflow.Priority = spec.Priority
+	workflow.MaxConcurrentRuns = spec.MaxConcurrentRuns
+	workflow.Enabled = spec.Enabled
+	workflow.GraphDraft = spec.GraphContent
+	workflow.GraphContent = spec.GraphContent
+	workflow.HasDraft = false
+	workflow.HasContent = true
+	workflow.GitOpsChecksum = spec.Checksum()
+	workflow.GitOpsDrifted = false
+	if _, err := s.workflowRepo.Save(ctx, workflow); err != nil {
+		return err
+	}
+
+	if _, err := s.recordVersion(ctx, workflow.Id, "gitops", note); err != nil {
+		return fmt.Errorf("failed to record workflow version: %w", err)
+	}
+
+	return nil
+}
+
+// 比对清单中的期望状态与工作流记录，返回同步状态及偏离清单的字段。
+// 清单有变更时，以清单为准；清单未变更但记录不一致时，说明记录在界面上被修改过，阻止模式下还原，标记模式下仅标记。
+func resolveGitOpsSyncStatus(workflow *domain.Workflow, spec *gitopsWorkflowSpec, mode domain.WorkflowGitOpsModeType) (domain.WorkflowGitOpsSyncStatusType, []string) {
+	changes := spec.Diff(newGitOpsWorkflowSpec(workflow))
+	switch {
+	case workflow.GitOpsChecksum != spec.Checksum():
+		return domain.WorkflowGitOpsSyncStatusTypeUpdated, changes
+	case len(changes) > 0 && mode == domain.WorkflowGitOpsModeTypeBlock:
+		return domain.WorkflowGitOpsSyncStatusTypeReverted, changes
+	case len(changes) > 0:
+		return domain.WorkflowGitOpsSyncStatusTypeDrifted, changes
+	default:
+		return domain.WorkflowGitOpsSyncStatusTypeSynced, nil
+	}
+}
+
+// 返回清单中已不存在的受管理工作流。
+func findGitOpsOrphanedWorkflows(managedWorkflows []*domain.Workflow, manifestWorkflows map[string]*gitopsManifestWorkflow) []*domain.Workflow {
+	return lo.Filter(managedWorkflows, func(workflow *domain.Workflow, _ int) bool {
+		_, ok := manifestWorkflows[workflow.GitOpsRef]
+		return !ok
+	})
+}
+
+// 递归读取目录下的全部 YAML/JSON 清单文件，合并为一个导出包。
+// 返回值依次为：合并后的导出包、工作流引用名到清单声明的映射、工作流引用名到所在文件的映射。
+func loadGitOpsManifests(dir string) (*domain.WorkflowBundle, map[string]*gitopsManifestWorkflow, map[string]string, error) {
+	bundle := &domain.WorkflowBundle{
+		Version:   domain.WorkflowBundleVersion,
+		Workflows: make([]*domain.WorkflowBundleWorkflow, 0),
+		Accesses:  make([]*domain.WorkflowBundleAccess, 0),
+	}
+	manifestWorkflows := make(map[string]*gitopsManifestWorkflow) // Key: WorkflowRef
+	manifestFiles := make(map[string]string)                      // Key: WorkflowRef
+	manifestAccesses := make(map[string]*domain.WorkflowBundleAccess)
+
+	var errs []error
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// 跳过 `.git` 等隐藏目录及文件
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		} else if d.IsDir() {
+			return nil
+		} else if !slices.Contains([]string{".yaml", ".yml", ".json"}, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(dir, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		manifest := &gitopsManifest{}
+		if err := yaml.Unmarshal(data, manifest); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid manifest: %w", relPath, err))
+			return nil
+		} else if manifest.Version != domain.WorkflowBundleVersion {
+			errs = append(errs, fmt.Errorf("%s: unsupported manifest version '%s'", relPath, manifest.Version))
+			return nil
+		}
+
+		// 同一授权记录可在多个文件中重复声明，但声明须一致
+		for _, access := range manifest.Accesses {
+			if declared, ok := manifestAccesses[access.Ref]; ok {
+				if declared.Name != access.Name || declared.Provider != access.Provider {
+					errs = append(errs, fmt.Errorf("%s: access '%s' conflicts with a previous declaration", relPath, access.Ref))
+				}
+				continue
+			}
+
+			manifestAccesses[access.Ref] = access
+			bundle.Accesses = append(bundle.Accesses, access)
+		}
+
+		for _, workflow := range manifest.Workflows {
+			if file, ok := manifestFiles[workflow.Ref]; ok {
+				errs = append(errs, fmt.Errorf("%s: workflow '%s' is already declared in %s", relPath, workflow.Ref, file))
+				continue
+			} else if workflow.Ref == "" {
+				errs = append(errs, fmt.Errorf("%s: workflow ref is required", relPath))
+				continue
+			}
+
+			manifestWorkflows[workflow.Ref] = workflow
+			manifestFiles[workflow.Ref] = relPath
+			bundle.Workflows = append(bundle.Workflows, &workflow.WorkflowBundleWorkflow)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read gitops manifests: %w", err)
+	} else if len(errs) > 0 {
+		return nil, nil, nil, errors.Join(errs...)
+	}
+
+	return bundle, manifestWorkflows, manifestFiles, nil
+}
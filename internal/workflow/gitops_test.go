@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestResolveGitOpsSyncStatus(t *testing.T) {
+	newSpec := func() *gitopsWorkflowSpec {
+		return &gitopsWorkflowSpec{
+			Name:         "demo",
+			Trigger:      domain.WorkflowTriggerTypeManual,
+			Enabled:      true,
+			GraphContent: &domain.WorkflowGraph{Nodes: []*domain.WorkflowNode{{Id: "start", Type: domain.WorkflowNodeTypeStart}}},
+		}
+	}
+
+	// 按清单同步后的工作流记录
+	newSyncedWorkflow := func() *domain.Workflow {
+		spec := newSpec()
+		return &domain.Workflow{
+			Name:           spec.Name,
+			Trigger:        spec.Trigger,
+			Enabled:        spec.Enabled,
+			GraphContent:   spec.GraphContent,
+			GitOpsChecksum: spec.Checksum(),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		workflow    func() *domain.Workflow
+		spec        func() *gitopsWorkflowSpec
+		mode        domain.WorkflowGitOpsModeType
+		wantStatus  domain.WorkflowGitOpsSyncStatusType
+		wantChanges []string
+	}{
+		{
+			name:       "synced",
+			workflow:   newSyncedWorkflow,
+			spec:       newSpec,
+			mode:       domain.WorkflowGitOpsModeTypeFlag,
+			wantStatus: domain.WorkflowGitOpsSyncStatusTypeSynced,
+		},
+		{
+			name:     "manifest changed",
+			workflow: newSyncedWorkflow,
+			spec: func() *gitopsWorkflowSpec {
+				spec := newSpec()
+				spec.Name = "renamed"
+				return spec
+			},
+			mode:        domain.WorkflowGitOpsModeTypeFlag,
+			wantStatus:  domain.WorkflowGitOpsSyncStatusTypeUpdated,
+			wantChanges: []string{"name"},
+		},
+		{
+			name: "never synced",
+			workflow: func() *domain.Workflow {
+				workflow := newSyncedWorkflow()
+				workflow.GitOpsChecksum = ""
+				return workflow
+			},
+			spec:       newSpec,
+			mode:       domain.WorkflowGitOpsModeTypeFlag,
+			wantStatus: domain.WorkflowGitOpsSyncStatusTypeUpdated,
+		},
+		{
+			name: "drifted in flag mode",
+			workflow: func() *domain.Workflow {
+				workflow := newSyncedWorkflow()
+				workflow.Enabled = false
+				workflow.GraphContent = &domain.WorkflowGraph{}
+				return workflow
+			},
+			spec:        newSpec,
+			mode:        domain.WorkflowGitOpsModeTypeFlag,
+			wantStatus:  domain.WorkflowGitOpsSyncStatusTypeDrifted,
+			wantChanges: []string{"enabled", "graphContent"},
+		},
+		{
+			name: "drifted in block mode",
+			workflow: func() *domain.Workflow {
+				workflow := newSyncedWorkflow()
+				workflow.Priority = 10
+				return workflow
+			},
+			spec:        newSpec,
+			mode:        domain.WorkflowGitOpsModeTypeBlock,
+			wantStatus:  domain.WorkflowGitOpsSyncStatusTypeReverted,
+			wantChanges: []string{"priority"},
+		},
+		{
+			name: "unmanaged fields changed",
+			workflow: func() *domain.Workflow {
+				workflow := newSyncedWorkflow()
+				workflow.GraphDraft = &domain.WorkflowGraph{}
+				workflow.HasDraft = true
+				return workflow
+			},
+			spec:       newSpec,
+			mode:       domain.WorkflowGitOpsModeTypeBlock,
+			wantStatus: domain.WorkflowGitOpsSyncStatusTypeSynced,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, changes := resolveGitOpsSyncStatus(tt.workflow(), tt.spec(), tt.mode)
+			if status != tt.wantStatus {
+				t.Errorf("resolveGitOpsSyncStatus() got status = %v, want %v", status, tt.wantStatus)
+			}
+			if !slices.Equal(changes, tt.wantChanges) && (len(changes) > 0 || len(tt.wantChanges) > 0) {
+				t.Errorf("resolveGitOpsSyncStatus() got changes = %v, want %v", changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestFindGitOpsOrphanedWorkflows(t *testing.T) {
+	managedWorkflows := []*domain.Workflow{
+		{Meta: domain.Meta{Id: "w1"}, GitOpsRef: "kept"},
+		{Meta: domain.Meta{Id: "w2"}, GitOpsRef: "removed"},
+		{Meta: domain.Meta{Id: "w3"}, GitOpsRef: "renamed"},
+	}
+
+	tests := []struct {
+		name      string
+		manifests []string
+		want      []string
+	}{
+		{name: "no manifests", manifests: nil, want: []string{"w1", "w2", "w3"}},
+		{name: "partially declared", manifests: []string{"kept", "renamed-new"}, want: []string{"w2", "w3"}},
+		{name: "all declared", manifests: []string{"kept", "removed", "renamed"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestWorkflows := make(map[string]*gitopsManifestWorkflow)
+			for _, ref := range tt.manifests {
+				manifestWorkflows[ref] = &gitopsManifestWorkflow{}
+			}
+
+			got := lo.Map(findGitOpsOrphanedWorkflows(managedWorkflows, manifestWorkflows), func(w *domain.Workflow, _ int) string { return w.Id })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findGitOpsOrphanedWorkflows() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
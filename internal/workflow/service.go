@@ -1,35 +1,64 @@
 package workflow
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/samber/lo"
 
 	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/cluster"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/eventbus"
+	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/workflow/dispatcher"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
 )
 
 type WorkflowService struct {
 	dispatcher dispatcher.WorkflowDispatcher
+	cluster    cluster.Cluster
+	eventBus   eventbus.EventBus
 
-	workflowRepo    workflowRepository
-	workflowRunRepo workflowRunRepository
-	settingsRepo    settingsRepository
+	workflowRepo        workflowRepository
+	workflowRunRepo     workflowRunRepository
+	workflowVersionRepo workflowVersionRepository
+	certificateRepo     certificateRepository
+	accessRepo          accessRepository
+	settingsRepo        settingsRepository
+	privateCARepo       privateCARepository
 }
 
-func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository, settingsRepo settingsRepository) *WorkflowService {
+func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository, workflowVersionRepo workflowVersionRepository, certificateRepo certificateRepository, accessRepo accessRepository, settingsRepo settingsRepository, privateCARepo privateCARepository) *WorkflowService {
 	srv := &WorkflowService{
 		dispatcher: dispatcher.GetSingletonDispatcher(),
+		cluster:    cluster.GetSingletonCluster(),
+		eventBus:   eventbus.GetSingletonEventBus(),
 
-		workflowRepo:    workflowRepo,
-		workflowRunRepo: workflowRunRepo,
-		settingsRepo:    settingsRepo,
+		workflowRepo:        workflowRepo,
+		workflowRunRepo:     workflowRunRepo,
+		workflowVersionRepo: workflowVersionRepo,
+		certificateRepo:     certificateRepo,
+		accessRepo:          accessRepo,
+		settingsRepo:        settingsRepo,
+		privateCARepo:       privateCARepo,
 	}
 	return srv
 }
@@ -40,11 +69,19 @@ func (s *WorkflowService) InitSchedule(ctx context.Context) error {
 		s.cleanupHistoryRuns(context.Background())
 	})
 
+	// 每分钟将超时未审批的运行标记为已过期
+	app.GetScheduler().MustAdd("expireWorkflowApprovals", "* * * * *", func() {
+		s.expireWaitingApprovals(context.Background())
+	})
+
 	// 初始化工作流调度器
 	if err := s.dispatcher.Bootup(ctx); err != nil {
 		panic(err)
 	}
 
+	// 订阅内部事件，以触发事件驱动的工作流
+	s.eventBus.Subscribe(s.onEvent)
+
 	// 注册工作流后台任务
 	{
 		workflows, err := s.workflowRepo.ListEnabledScheduled(ctx)
@@ -56,13 +93,7 @@ func (s *WorkflowService) InitSchedule(ctx context.Context) error {
 			var errs []error
 
 			err := app.GetScheduler().Add(fmt.Sprintf("workflow#%s", workflow.Id), workflow.TriggerCron, func() {
-				_, err := s.StartRun(context.Background(), &dtos.WorkflowStartRunReq{
-					WorkflowId: workflow.Id,
-					RunTrigger: domain.WorkflowTriggerTypeScheduled,
-				})
-				if err != nil {
-					app.GetLogger().Error(fmt.Sprintf("failed to start scheduled run for workflow #%s", workflow.Id), slog.Any("error", err))
-				}
+				s.startScheduledRun(context.Background(), workflow.Id)
 			})
 			if err != nil {
 				app.GetLogger().Error(fmt.Sprintf("failed to register cron job for workflow #%s", workflow.Id), slog.Any("error", err))
@@ -77,14 +108,32 @@ func (s *WorkflowService) InitSchedule(ctx context.Context) error {
 		}
 	}
 
+	// 启用 GitOps 模式时，启动时及每分钟从清单目录同步工作流
+	if IsGitOpsEnabled() {
+		app.GetScheduler().MustAdd("syncWorkflowGitOps", "* * * * *", func() {
+			s.startScheduledGitOpsSync(context.Background())
+		})
+
+		s.startScheduledGitOpsSync(ctx)
+	}
+
+	// 多实例模式下，工作流可能在其他实例上被修改，需定期同步定时任务
+	if s.cluster.IsHAEnabled() {
+		s.cluster.OnHeartbeat(s.syncScheduledJobs)
+	}
+
 	return nil
 }
 
 func (s *WorkflowService) GetStatistics(ctx context.Context) (*dtos.WorkflowStatisticsResp, error) {
 	stats := s.dispatcher.GetStatistics()
 	return &dtos.WorkflowStatisticsResp{
-		Concurrency:      stats.Concurrency,
-		PendingRunIds:    stats.PendingRunIds,
+		Concurrency:         stats.Concurrency,
+		ProviderConcurrency: stats.ProviderConcurrency,
+		PendingRunIds:       stats.PendingRunIds,
+		PendingRunGroups: lo.Map(stats.PendingRunGroups, func(group dispatcher.StatisticsPendingRunGroup, _ int) *dtos.WorkflowStatisticsPendingRunGroup {
+			return &dtos.WorkflowStatisticsPendingRunGroup{Priority: group.Priority, RunIds: group.RunIds}
+		}),
 		ProcessingRunIds: stats.ProcessingRunIds,
 	}, nil
 }
@@ -95,8 +144,8 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 		return nil, err
 	}
 
-	if req.RunTrigger == domain.WorkflowTriggerTypeManual && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing) {
-		return nil, errors.New("workflow is already pending or processing")
+	if req.RunTrigger == domain.WorkflowTriggerTypeManual && workflow.GetMaxConcurrentRuns() <= 1 && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing || workflow.LastRunStatus == domain.WorkflowRunStatusTypeWaiting) {
+		return nil, errors.New("workflow is already pending, processing or waiting")
 	} else if workflow.GraphContent == nil {
 		return nil, errors.New("workflow graph content is empty")
 	} else if err := workflow.GraphContent.Verify(); err != nil {
@@ -104,11 +153,17 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 	}
 
 	workflowRun := &domain.WorkflowRun{
-		WorkflowId: workflow.Id,
-		Status:     domain.WorkflowRunStatusTypePending,
-		Trigger:    req.RunTrigger,
-		StartedAt:  time.Now(),
-		Graph:      workflow.GraphContent.Clone(),
+		WorkflowId:     workflow.Id,
+		Status:         domain.WorkflowRunStatusTypePending,
+		Trigger:        req.RunTrigger,
+		TriggerPayload: req.RunPayload,
+		StartedAt:      time.Now(),
+		Graph:          workflow.GraphContent.Clone(),
+		DryRun:         req.DryRun,
+		Priority:       workflow.Priority,
+	}
+	if req.Priority != nil {
+		workflowRun.Priority = *req.Priority
 	}
 	if resp, err := s.workflowRunRepo.Save(ctx, workflowRun); err != nil {
 		return nil, err
@@ -123,6 +178,164 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 	return &dtos.WorkflowStartRunResp{RunId: workflowRun.Id}, nil
 }
 
+func (s *WorkflowService) TriggerWebhook(ctx context.Context, req *dtos.WorkflowWebhookTriggerReq) (*dtos.WorkflowStartRunResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, domain.ErrWebhookUnauthorized
+		}
+		return nil, err
+	}
+
+	// 未启用或非 Webhook 触发的工作流，统一视为鉴权失败，避免泄露工作流是否存在
+	if !workflow.Enabled || workflow.Trigger != domain.WorkflowTriggerTypeWebhook || workflow.WebhookKey == "" {
+		return nil, domain.ErrWebhookUnauthorized
+	} else if subtle.ConstantTimeCompare([]byte(workflow.WebhookKey), []byte(req.WebhookKey)) != 1 {
+		return nil, domain.ErrWebhookUnauthorized
+	}
+
+	// 配置了签名密钥时，校验请求体的 HMAC-SHA256 签名
+	if workflow.WebhookSecret != "" {
+		signature := strings.TrimPrefix(strings.TrimSpace(req.Signature), "sha256=")
+		signatureBytes, err := hex.DecodeString(signature)
+		if err != nil || signature == "" {
+			return nil, domain.ErrWebhookUnauthorized
+		}
+
+		mac := hmac.New(sha256.New, []byte(workflow.WebhookSecret))
+		mac.Write(req.Body)
+		if !hmac.Equal(mac.Sum(nil), signatureBytes) {
+			return nil, domain.ErrWebhookUnauthorized
+		}
+	}
+
+	payload := make(map[string]any)
+	if len(bytes.TrimSpace(req.Body)) > 0 {
+		if err := json.Unmarshal(req.Body, &payload); err != nil {
+			return nil, domain.NewError(400, "invalid request body: must be a JSON object")
+		}
+	}
+
+	// 重新序列化后的大小可能与原始请求体不同（如转义字符），需再次校验以免超出存储字段的限制
+	if payloadBytes, err := json.Marshal(payload); err != nil {
+		return nil, err
+	} else if len(payloadBytes) > domain.WorkflowRunTriggerPayloadMaxSize {
+		return nil, domain.NewError(400, "invalid request body: the payload is too large")
+	}
+
+	return s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+		WorkflowId: workflow.Id,
+		RunTrigger: domain.WorkflowTriggerTypeWebhook,
+		RunPayload: payload,
+	})
+}
+
+func (s *WorkflowService) onEvent(ctx context.Context, event *domain.Event) {
+	workflows, err := s.workflowRepo.ListEnabledEventTriggered(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to list event-triggered workflows", slog.Any("error", err))
+		return
+	}
+
+	for _, workflow := range workflows {
+		if !workflow.TriggerEvent.Match(event) {
+			continue
+		}
+
+		// 忽略触发链中已包含本工作流的事件（如 A -> B -> A），以及触发链过深的事件，避免循环触发
+		if slices.Contains(event.Chain, workflow.Id) {
+			continue
+		} else if len(event.Chain) >= domain.EventChainMaxDepth {
+			app.GetLogger().Warn(fmt.Sprintf("workflow #%s was not triggered by event '%s': the trigger chain is too deep", workflow.Id, event.Type), slog.String("chain", strings.Join(event.Chain, " -> ")))
+			continue
+		}
+
+		// 同一证书的即将过期事件，对每个工作流只触发一次（在证书上持久化已通知的工作流，续期后的证书为新记录，会再次触发）
+		certificateId, _ := event.Payload["certificateId"].(string)
+		if event.Type == domain.EventTypeCertificateExpiring {
+			marked, err := s.certificateRepo.MarkExpiringNotified(ctx, certificateId, workflow.Id)
+			if err != nil {
+				app.GetLogger().Error(fmt.Sprintf("failed to mark certificate #%s as notified for workflow #%s", certificateId, workflow.Id), slog.Any("error", err))
+				continue
+			} else if !marked {
+				continue
+			}
+		}
+
+		payload := maps.Clone(event.Payload)
+		payload["eventType"] = string(event.Type)
+		payload["eventTimestamp"] = event.Timestamp.Format(time.RFC3339)
+		payload["triggerChain"] = strings.Join(event.Chain, ";")
+
+		_, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+			WorkflowId: workflow.Id,
+			RunTrigger: domain.WorkflowTriggerTypeEvent,
+			RunPayload: payload,
+		})
+		if err != nil {
+			app.GetLogger().Error(fmt.Sprintf("failed to start event-triggered run for workflow #%s", workflow.Id), slog.String("eventType", string(event.Type)), slog.Any("error", err))
+
+			// 未能触发时撤销标记，以便下次发布事件时重试
+			if event.Type == domain.EventTypeCertificateExpiring {
+				if err := s.certificateRepo.UnmarkExpiringNotified(ctx, certificateId, workflow.Id); err != nil {
+					app.GetLogger().Error(fmt.Sprintf("failed to unmark certificate #%s as notified for workflow #%s", certificateId, workflow.Id), slog.Any("error", err))
+				}
+			}
+		} else {
+			app.GetLogger().Info(fmt.Sprintf("workflow #%s was triggered by event '%s'", workflow.Id, event.Type))
+		}
+	}
+}
+
+func (s *WorkflowService) ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != workflow.Id {
+		return nil, domain.ErrRecordNotFound
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypeFailed {
+		return nil, errors.New("workflow run is not failed")
+	} else if workflowRun.Snapshot == nil || workflowRun.Snapshot.ErrorNodeId == "" {
+		return nil, errors.New("workflow run is not resumable, because the failed node is unknown")
+	} else if workflowRun.Graph == nil {
+		return nil, errors.New("workflow run graph is empty")
+	} else if _, ok := workflowRun.Graph.GetNodeById(workflowRun.Snapshot.ErrorNodeId); !ok {
+		return nil, fmt.Errorf("workflow run is not resumable, because the failed node #%s does not exist", workflowRun.Snapshot.ErrorNodeId)
+	}
+
+	if workflow.GetMaxConcurrentRuns() <= 1 && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing || workflow.LastRunStatus == domain.WorkflowRunStatusTypeWaiting) {
+		return nil, errors.New("workflow is already pending, processing or waiting")
+	}
+
+	// 使用原运行的流程图，以确保节点与状态快照一致
+	resumedRun := &domain.WorkflowRun{
+		WorkflowId:     workflow.Id,
+		ResumedFromId:  workflowRun.Id,
+		Status:         domain.WorkflowRunStatusTypePending,
+		Trigger:        domain.WorkflowTriggerTypeManual,
+		TriggerPayload: workflowRun.TriggerPayload,
+		StartedAt:      time.Now(),
+		Graph:          workflowRun.Graph,
+		Priority:       workflow.Priority,
+	}
+	if resp, err := s.workflowRunRepo.Save(ctx, resumedRun); err != nil {
+		return nil, err
+	} else {
+		resumedRun = resp
+	}
+
+	if err := s.dispatcher.Start(ctx, resumedRun.Id); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowResumeRunResp{RunId: resumedRun.Id}, nil
+}
+
 func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCancelRunReq) (*dtos.WorkflowCancelRunResp, error) {
 	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
 	if err != nil {
@@ -134,8 +347,8 @@ func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCance
 		return nil, err
 	} else if workflowRun.WorkflowId != workflow.Id {
 		return nil, errors.New("workflow run not found")
-	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing {
-		return nil, errors.New("workflow run is not pending or processing")
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing && workflowRun.Status != domain.WorkflowRunStatusTypeWaiting {
+		return nil, errors.New("workflow run is not pending, processing or waiting")
 	}
 
 	if err := s.dispatcher.Cancel(ctx, workflowRun.Id); err != nil {
@@ -145,6 +358,163 @@ func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCance
 	return &dtos.WorkflowCancelRunResp{}, nil
 }
 
+func (s *WorkflowService) PreviewTemplate(ctx context.Context, req *dtos.WorkflowPreviewTemplateReq) (*dtos.WorkflowPreviewTemplateResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != workflow.Id {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	subject, message := req.Subject, req.Message
+	if req.NodeId != "" {
+		if workflowRun.Graph == nil {
+			return nil, errors.New("workflow run graph is empty")
+		}
+
+		node, ok := workflowRun.Graph.GetNodeById(req.NodeId)
+		if !ok {
+			return nil, fmt.Errorf("node #%s not found in workflow run", req.NodeId)
+		} else if node.Type != domain.WorkflowNodeTypeBizNotify {
+			return nil, fmt.Errorf("node #%s is not a notification node", req.NodeId)
+		}
+
+		nodeCfg := node.Data.Config.AsBizNotify()
+		if subject == "" {
+			subject = nodeCfg.Subject
+		}
+		if message == "" {
+			message = nodeCfg.Message
+		}
+	}
+
+	// 早期的运行记录可能不存在状态快照，此时仅提供工作流与运行的基本信息
+	snapshot := workflowRun.Snapshot
+	if snapshot == nil {
+		snapshot = &domain.WorkflowRunSnapshot{
+			Variables: []*domain.WorkflowRunSnapshotVariable{
+				{Key: "workflow.id", Value: workflow.Id, ValueType: "string"},
+				{Key: "workflow.name", Value: workflow.Name, ValueType: "string"},
+				{Key: "run.id", Value: workflowRun.Id, ValueType: "string"},
+				{Key: "run.trigger", Value: string(workflowRun.Trigger), ValueType: "string"},
+			},
+		}
+	}
+	if workflowRun.Error != "" && !lo.SomeBy(snapshot.Variables, func(v *domain.WorkflowRunSnapshotVariable) bool { return v.Scope == "" && v.Key == "error.message" }) {
+		snapshot.Variables = append(snapshot.Variables, &domain.WorkflowRunSnapshotVariable{Key: "error.message", Value: workflowRun.Error, ValueType: "string"})
+	}
+
+	renderedSubject, err := engine.RenderTemplateWithSnapshot(subject, snapshot)
+	if err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("subject: %s", err.Error()))
+	}
+
+	renderedMessage, err := engine.RenderTemplateWithSnapshot(message, snapshot)
+	if err != nil {
+		return nil, domain.NewError(400, fmt.Sprintf("message: %s", err.Error()))
+	}
+
+	return &dtos.WorkflowPreviewTemplateResp{
+		Subject: renderedSubject,
+		Message: renderedMessage,
+	}, nil
+}
+
+func (s *WorkflowService) DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error) {
+	fromVersion, err := s.workflowVersionRepo.GetByWorkflowIdAndVersion(ctx, req.WorkflowId, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	toVersion, err := s.workflowVersionRepo.GetByWorkflowIdAndVersion(ctx, req.WorkflowId, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowDiffVersionsResp{
+		From:  fromVersion,
+		To:    toVersion,
+		Nodes: domain.DiffWorkflowGraph(fromVersion.Graph, toVersion.Graph),
+	}, nil
+}
+
+func (s *WorkflowService) RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowVersion, err := s.workflowVersionRepo.GetByWorkflowIdAndVersion(ctx, req.WorkflowId, req.Version)
+	if err != nil {
+		return nil, err
+	} else if workflowVersion.Graph == nil {
+		return nil, errors.New("workflow version graph is empty")
+	} else if err := s.validateGraph(ctx, workflow, workflowVersion.Graph).Err(); err != nil {
+		return nil, domain.NewError(400, err.Error())
+	}
+
+	// 回滚流程图与在界面上发布流程图一样，需遵循 GitOps 的管理模式
+	if !reflect.DeepEqual(workflow.GraphContent, workflowVersion.Graph) {
+		if drifted, err := checkGitOpsChange(workflow.GitOpsRef, []string{"graphContent"}); err != nil {
+			return nil, domain.NewError(403, err.Error())
+		} else if drifted {
+			workflow.GitOpsDrifted = true
+		}
+	}
+
+	// 回滚已发布的流程图；若存在尚未发布的草稿，则保留草稿
+	if !workflow.HasDraft {
+		workflow.GraphDraft = workflowVersion.Graph
+	}
+	workflow.GraphContent = workflowVersion.Graph
+	workflow.HasContent = true
+	workflow.HasDraft = !reflect.DeepEqual(workflow.GraphDraft, workflow.GraphContent)
+
+	note := req.Note
+	if note == "" {
+		note = fmt.Sprintf("rollback to version %d", workflowVersion.Version)
+	}
+
+	var newVersion *domain.WorkflowVersion
+	err = repository.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.workflowRepo.Save(txCtx, workflow); err != nil {
+			return err
+		}
+
+		newVersion, err = s.recordVersion(txCtx, workflow.Id, req.Author, note)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowRollbackVersionResp{Version: newVersion.Version}, nil
+}
+
+func (s *WorkflowService) RotateWebhookSecret(ctx context.Context, req *dtos.WorkflowRotateWebhookSecretReq) (*dtos.WorkflowRotateWebhookSecretResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 签名密钥不会出现在任何查询接口的响应中，仅在此处生成后返回一次
+	if req.Revoke {
+		workflow.WebhookSecret = ""
+	} else {
+		workflow.WebhookSecret = security.RandomString(32)
+	}
+	if _, err := s.workflowRepo.Save(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowRotateWebhookSecretResp{WebhookSecret: workflow.WebhookSecret}, nil
+}
+
 func (s *WorkflowService) Shutdown(ctx context.Context) {
 	s.dispatcher.Shutdown(ctx)
 }
@@ -162,6 +532,7 @@ func (s *WorkflowService) cleanupHistoryRuns(ctx context.Context) error {
 			ctx,
 			dbx.NewExp(fmt.Sprintf("status!='%s'", string(domain.WorkflowRunStatusTypePending))),
 			dbx.NewExp(fmt.Sprintf("status!='%s'", string(domain.WorkflowRunStatusTypeProcessing))),
+			dbx.NewExp(fmt.Sprintf("status!='%s'", string(domain.WorkflowRunStatusTypeWaiting))),
 			dbx.NewExp(fmt.Sprintf("endedAt<DATETIME('now', '-%d days')", persistenceSettings.WorkflowRunsMaxDaysRetention)),
 		)
 		if err != nil {
@@ -176,3 +547,78 @@ func (s *WorkflowService) cleanupHistoryRuns(ctx context.Context) error {
 
 	return nil
 }
+
+func (s *WorkflowService) startScheduledRun(ctx context.Context, workflowId string) {
+	// 多实例模式下各实例的定时任务均会触发，仅由获取到租约的实例启动本次运行
+	leaseName := fmt.Sprintf("workflow#%s@%s", workflowId, time.Now().Truncate(time.Minute).Format(time.RFC3339))
+	if acquired, err := s.cluster.TryAcquireLease(ctx, leaseName, time.Hour); err != nil {
+		app.GetLogger().Error("failed to acquire lease", slog.String("lease", leaseName), slog.Any("error", err))
+		return
+	} else if !acquired {
+		return
+	}
+
+	_, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+		WorkflowId: workflowId,
+		RunTrigger: domain.WorkflowTriggerTypeScheduled,
+	})
+	if err != nil {
+		app.GetLogger().Error(fmt.Sprintf("failed to start scheduled run for workflow #%s", workflowId), slog.Any("error", err))
+	}
+}
+
+func (s *WorkflowService) syncScheduledJobs(ctx context.Context) {
+	workflows, err := s.workflowRepo.ListEnabledScheduled(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to list scheduled workflows", slog.Any("error", err))
+		return
+	}
+
+	scheduler := app.GetScheduler()
+	jobIds := make(map[string]struct{})
+	for _, workflow := range workflows {
+		jobId := fmt.Sprintf("workflow#%s", workflow.Id)
+		jobIds[jobId] = struct{}{}
+
+		job, _ := lo.Find(scheduler.Jobs(), func(j *cron.Job) bool { return j.Id() == jobId })
+		if job == nil || job.Expression() != workflow.TriggerCron {
+			workflowId := workflow.Id
+			err := scheduler.Add(jobId, workflow.TriggerCron, func() {
+				s.startScheduledRun(context.Background(), workflowId)
+			})
+			if err != nil {
+				app.GetLogger().Error(fmt.Sprintf("failed to register cron job for workflow #%s", workflowId), slog.Any("error", err))
+			}
+		}
+	}
+
+	for _, job := range scheduler.Jobs() {
+		if _, ok := jobIds[job.Id()]; !ok && strings.HasPrefix(job.Id(), "workflow#") {
+			scheduler.Remove(job.Id())
+		}
+	}
+}
+
+// 已发布的流程图与最新版本不一致时，记录新的版本，并返回最新版本。
+func (s *WorkflowService) recordVersion(ctx context.Context, workflowId string, author string, note string) (*domain.WorkflowVersion, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, workflowId)
+	if err != nil {
+		return nil, err
+	} else if workflow.GraphContent == nil || len(workflow.GraphContent.Nodes) == 0 {
+		return nil, nil
+	}
+
+	latestVersion, err := s.workflowVersionRepo.GetLatestByWorkflowId(ctx, workflowId)
+	if err != nil && !domain.IsRecordNotFoundError(err) {
+		return nil, err
+	} else if latestVersion != nil && reflect.DeepEqual(latestVersion.Graph, workflow.GraphContent) {
+		return latestVersion, nil
+	}
+
+	return s.workflowVersionRepo.Create(ctx, &domain.WorkflowVersion{
+		WorkflowId: workflow.Id,
+		Graph:      workflow.GraphContent,
+		Author:     author,
+		Note:       note,
+	})
+}
@@ -10,17 +10,44 @@ import (
 
 type workflowRepository interface {
 	ListEnabledScheduled(ctx context.Context) ([]*domain.Workflow, error)
+	ListEnabledEventTriggered(ctx context.Context) ([]*domain.Workflow, error)
+	ListGitOpsManaged(ctx context.Context) ([]*domain.Workflow, error)
 	GetById(ctx context.Context, id string) (*domain.Workflow, error)
 	Save(ctx context.Context, workflow *domain.Workflow) (*domain.Workflow, error)
+	DeleteById(ctx context.Context, id string) error
 }
 
 type workflowRunRepository interface {
 	GetById(ctx context.Context, id string) (*domain.WorkflowRun, error)
 	Save(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
 	SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
+	ResumeWaiting(ctx context.Context, workflowRun *domain.WorkflowRun) (bool, error)
+	ListByStatus(ctx context.Context, status domain.WorkflowRunStatusType) ([]*domain.WorkflowRun, error)
+	CountWhere(ctx context.Context, exprs ...dbx.Expression) (int, error)
 	DeleteWhere(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
 
+type workflowVersionRepository interface {
+	GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error)
+	GetByWorkflowIdAndVersion(ctx context.Context, workflowId string, version int32) (*domain.WorkflowVersion, error)
+	Create(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error)
+}
+
+type certificateRepository interface {
+	MarkExpiringNotified(ctx context.Context, certificateId string, workflowId string) (bool, error)
+	UnmarkExpiringNotified(ctx context.Context, certificateId string, workflowId string) error
+}
+
+type accessRepository interface {
+	ListByNameAndProvider(ctx context.Context, name string, provider string) ([]*domain.Access, error)
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+}
+
 type settingsRepository interface {
 	GetByName(ctx context.Context, name string) (*domain.Settings, error)
+	Save(ctx context.Context, settings *domain.Settings) (*domain.Settings, error)
+}
+
+type privateCARepository interface {
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
 }
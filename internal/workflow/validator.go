@@ -0,0 +1,709 @@
+package workflow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/cron"
+
+	"github.com/certimate-go/certimate/internal/certapply/applicators"
+	"github.com/certimate-go/certimate/internal/certdeploy/deployers"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/domain/expr"
+	"github.com/certimate-go/certimate/internal/notify/notifiers"
+	"github.com/certimate-go/certimate/internal/privateca"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+// 内置的授权提供商，使用时无需授权记录。
+var builtinAccessProviders = []domain.AccessProviderType{
+	domain.AccessProviderTypeLocal,
+	domain.AccessProviderTypeLetsEncrypt,
+	domain.AccessProviderTypeLetsEncryptStaging,
+}
+
+var caProviders = []domain.CAProviderType{
+	domain.CAProviderTypeACMECA,
+	domain.CAProviderTypeActalisSSL,
+	domain.CAProviderTypeGlobalSignAtlas,
+	domain.CAProviderTypeGoogleTrustServices,
+	domain.CAProviderTypeLetsEncrypt,
+	domain.CAProviderTypeLetsEncryptStaging,
+	domain.CAProviderTypePrivateCA,
+	domain.CAProviderTypeSectigo,
+	domain.CAProviderTypeSSLCom,
+	domain.CAProviderTypeZeroSSL,
+}
+
+func (s *WorkflowService) ValidateGraph(ctx context.Context, req *dtos.WorkflowValidateGraphReq) (*dtos.WorkflowValidateGraphResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 未指定流程图时，校验当前的草稿
+	graph := req.Graph
+	if graph == nil {
+		graph = workflow.GraphDraft
+	}
+
+	issues := s.validateGraph(ctx, workflow, graph)
+	return &dtos.WorkflowValidateGraphResp{
+		Valid:  !issues.HasErrors(),
+		Issues: issues,
+	}, nil
+}
+
+// 对流程图进行静态校验，工作流参数用于校验触发器及子工作流引用。
+// 流程图为 nil 时仅校验工作流触发器。
+func (s *WorkflowService) validateGraph(ctx context.Context, workflow *domain.Workflow, graph *domain.WorkflowGraph) domain.WorkflowGraphIssues {
+	v := &graphValidator{
+		ctx:           ctx,
+		accessRepo:    s.accessRepo,
+		workflowRepo:  s.workflowRepo,
+		privateCARepo: s.privateCARepo,
+		workflow:      workflow,
+		graph:         graph,
+		parents:       make(map[string]*domain.WorkflowNode),
+		nodes:         make(map[string]*domain.WorkflowNode),
+		issues:        make(domain.WorkflowGraphIssues, 0),
+	}
+	v.validate()
+	return v.issues
+}
+
+type graphValidator struct {
+	ctx           context.Context
+	accessRepo    accessRepository
+	workflowRepo  workflowRepository
+	privateCARepo privateCARepository
+
+	workflow *domain.Workflow
+	graph    *domain.WorkflowGraph
+	parents  map[string]*domain.WorkflowNode // Key: NodeId
+	nodes    map[string]*domain.WorkflowNode // Key: NodeId
+	issues   domain.WorkflowGraphIssues
+}
+
+func (v *graphValidator) addIssue(node *domain.WorkflowNode, level domain.WorkflowGraphIssueLevelType, field string, format string, args ...any) {
+	issue := &domain.WorkflowGraphIssue{
+		Level:   level,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if node != nil {
+		issue.NodeId = node.Id
+		issue.NodeName = node.Data.Name
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *graphValidator) addError(node *domain.WorkflowNode, field string, format string, args ...any) {
+	v.addIssue(node, domain.WorkflowGraphIssueLevelTypeError, field, format, args...)
+}
+
+func (v *graphValidator) addWarning(node *domain.WorkflowNode, field string, format string, args ...any) {
+	v.addIssue(node, domain.WorkflowGraphIssueLevelTypeWarning, field, format, args...)
+}
+
+func (v *graphValidator) validate() {
+	if v.workflow != nil {
+		v.validateTrigger()
+	}
+
+	// 未指定流程图时，仅校验触发器
+	if v.graph == nil {
+		return
+	} else if err := v.graph.Verify(); err != nil {
+		v.addError(nil, "", "%s", err.Error())
+	}
+
+	// 先建立节点索引，以便后续校验节点间的引用
+	var index func(parent *domain.WorkflowNode, nodes []*domain.WorkflowNode)
+	index = func(parent *domain.WorkflowNode, nodes []*domain.WorkflowNode) {
+		for _, node := range nodes {
+			if node.Id == "" {
+				v.addError(node, "id", "node id is empty")
+			} else if _, ok := v.nodes[node.Id]; ok {
+				v.addError(node, "id", "duplicate node id '%s'", node.Id)
+			} else {
+				v.nodes[node.Id] = node
+				v.parents[node.Id] = parent
+			}
+
+			index(node, node.Blocks)
+		}
+	}
+	index(nil, v.graph.Nodes)
+
+	walkWorkflowNodes(v.graph.Nodes, v.validateNode)
+}
+
+func (v *graphValidator) validateTrigger() {
+	switch v.workflow.Trigger {
+	case domain.WorkflowTriggerTypeScheduled:
+		if v.workflow.TriggerCron == "" {
+			v.addError(nil, "triggerCron", "cron expression is required for scheduled trigger")
+		} else if _, err := cron.NewSchedule(v.workflow.TriggerCron); err != nil {
+			v.addError(nil, "triggerCron", "invalid cron expression '%s': %s", v.workflow.TriggerCron, err.Error())
+		}
+
+	case domain.WorkflowTriggerTypeEvent:
+		if v.workflow.TriggerEvent == nil || v.workflow.TriggerEvent.Type == "" {
+			v.addError(nil, "triggerEvent", "event type is required for event trigger")
+		} else if daysLeft := v.workflow.TriggerEvent.DaysLeft; daysLeft < 0 || daysLeft > domain.CertificateExpiringEventMaxDaysLeft {
+			v.addError(nil, "triggerEvent.daysLeft", "days left must be in range [0, %d]", domain.CertificateExpiringEventMaxDaysLeft)
+		}
+	}
+}
+
+func (v *graphValidator) validateNode(node *domain.WorkflowNode) {
+	if !engine.IsNodeTypeSupported(node.Type) {
+		v.addError(node, "type", "unsupported node type '%s'", node.Type)
+		return
+	}
+
+	if node.Data.Name == "" {
+		v.addWarning(node, "name", "node name is empty")
+	}
+
+	if node.Data.Timeout < 0 {
+		v.addError(node, "timeout", "timeout must not be negative")
+	}
+
+	if retry := node.Data.Retry; retry != nil {
+		if retry.MaxAttempts < 0 {
+			v.addError(node, "retry.maxAttempts", "max attempts must not be negative")
+		}
+		if retry.Jitter < 0 || retry.Jitter > 1 {
+			v.addError(node, "retry.jitter", "jitter must be in range [0, 1]")
+		}
+		for _, pattern := range retry.RetryableErrors {
+			if _, err := regexp.Compile(pattern); err != nil {
+				v.addError(node, "retry.retryableErrors", "invalid regular expression '%s': %s", pattern, err.Error())
+			}
+		}
+	}
+
+	v.validateNodeBlocks(node)
+
+	switch node.Type {
+	case domain.WorkflowNodeTypeBranchBlock:
+		v.validateBranchBlockNode(node)
+	case domain.WorkflowNodeTypeDelay:
+		v.validateDelayNode(node)
+	case domain.WorkflowNodeTypeSetVariables:
+		v.validateSetVariablesNode(node)
+	case domain.WorkflowNodeTypeScript:
+		v.validateScriptNode(node)
+	case domain.WorkflowNodeTypeHttpRequest:
+		v.validateHttpRequestNode(node)
+	case domain.WorkflowNodeTypeApproval:
+		v.validateApprovalNode(node)
+	case domain.WorkflowNodeTypeSubWorkflow:
+		v.validateSubWorkflowNode(node)
+	case domain.WorkflowNodeTypeBizApply:
+		v.validateBizApplyNode(node)
+	case domain.WorkflowNodeTypeBizMonitor:
+		v.validateBizMonitorNode(node)
+	case domain.WorkflowNodeTypeBizDeploy:
+		v.validateBizDeployNode(node)
+	case domain.WorkflowNodeTypeBizNotify:
+		v.validateBizNotifyNode(node)
+	}
+}
+
+func (v *graphValidator) validateNodeBlocks(node *domain.WorkflowNode) {
+	// 容器节点只会执行指定类型的子节点，其余子节点将被忽略
+	var blockTypes []domain.WorkflowNodeType
+	switch node.Type {
+	case domain.WorkflowNodeTypeCondition:
+		blockTypes = []domain.WorkflowNodeType{domain.WorkflowNodeTypeBranchBlock}
+	case domain.WorkflowNodeTypeTryCatch:
+		blockTypes = []domain.WorkflowNodeType{domain.WorkflowNodeTypeTryBlock, domain.WorkflowNodeTypeCatchBlock}
+	case domain.WorkflowNodeTypeParallel:
+		blockTypes = []domain.WorkflowNodeType{domain.WorkflowNodeTypeParallelBlock}
+	case domain.WorkflowNodeTypeBranchBlock, domain.WorkflowNodeTypeTryBlock, domain.WorkflowNodeTypeCatchBlock, domain.WorkflowNodeTypeParallelBlock:
+		for _, block := range node.Blocks {
+			if isWorkflowBlockNodeType(block.Type) {
+				v.addError(block, "type", "node of type '%s' cannot be placed in a '%s' node", block.Type, node.Type)
+			}
+		}
+		return
+	default:
+		if len(node.Blocks) > 0 {
+			v.addWarning(node, "blocks", "node of type '%s' has child nodes, which will be ignored", node.Type)
+		}
+		return
+	}
+
+	if len(node.Blocks) == 0 {
+		v.addError(node, "blocks", "node of type '%s' has no branches", node.Type)
+	}
+	for _, block := range node.Blocks {
+		if !slices.Contains(blockTypes, block.Type) {
+			v.addWarning(block, "type", "node of type '%s' in a '%s' node will be ignored", block.Type, node.Type)
+		}
+	}
+}
+
+func (v *graphValidator) validateBranchBlockNode(node *domain.WorkflowNode) {
+	expression := node.Data.Config["expression"]
+	if expression == nil {
+		return
+	}
+
+	exprRaw, _ := json.Marshal(expression)
+	e, err := expr.UnmarshalExpr(exprRaw)
+	if err != nil {
+		v.addError(node, "config.expression", "invalid expression: %s", err.Error())
+		return
+	}
+
+	v.checkExprSelectors(node, "config.expression", e)
+}
+
+// 校验表达式引用的节点作用域变量。全局变量无法静态确定，不作校验。
+func (v *graphValidator) checkExprSelectors(node *domain.WorkflowNode, field string, e expr.Expr) {
+	for _, selector := range expr.GetSelectors(e) {
+		if selector.Name == "" {
+			v.addError(node, field, "expression references an incomplete variable")
+			continue
+		} else if selector.Id == "" {
+			continue
+		}
+
+		refNode, ok := v.checkNodeReference(node, field, selector.Id)
+		if !ok {
+			continue
+		}
+
+		variables, dynamic := engine.GetNodeScopedVariables(refNode.Type)
+		if valueType, ok := variables[selector.Name]; !ok {
+			if !dynamic {
+				v.addError(node, field, "expression references variable '%s', which node #%s never sets", selector.Name, refNode.Id)
+			}
+		} else if !isExprValueTypeCompatible(valueType, selector.Type) {
+			v.addWarning(node, field, "expression treats variable '%s' of node #%s as %s, but it is %s", selector.Name, refNode.Id, selector.Type, valueType)
+		}
+	}
+}
+
+// 判断变量的实际值类型能否按表达式中声明的类型进行求值。
+func isExprValueTypeCompatible(valueType string, declaredType expr.ExprValueType) bool {
+	switch {
+	case declaredType == "", string(declaredType) == valueType:
+		return true
+	case valueType == string(expr.String):
+		// 字符串可被解析为日期时间或以分号分隔的列表
+		return declaredType == expr.Datetime || declaredType == expr.List
+	case valueType == string(expr.Datetime):
+		return declaredType == expr.String
+	default:
+		return false
+	}
+}
+
+func (v *graphValidator) validateDelayNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsDelay()
+	if nodeCfg.Wait < 0 {
+		v.addError(node, "config.wait", "wait time must not be negative")
+	}
+}
+
+func (v *graphValidator) validateSetVariablesNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsSetVariables()
+	if len(nodeCfg.Assignments) == 0 {
+		v.addError(node, "config.assignments", "at least one assignment is required")
+		return
+	}
+
+	for i, assignment := range nodeCfg.Assignments {
+		field := fmt.Sprintf("config.assignments[%d]", i)
+		if assignment.Name == "" {
+			v.addError(node, field+".name", "variable name is required")
+		}
+
+		if len(assignment.Expression) == 0 {
+			v.addError(node, field+".expression", "expression is required")
+		} else if e, err := expr.UnmarshalExpr(assignment.Expression); err != nil {
+			v.addError(node, field+".expression", "invalid expression: %s", err.Error())
+		} else {
+			v.checkExprSelectors(node, field+".expression", e)
+		}
+
+		if assignment.MappingDefault != nil && assignment.Mapping == nil {
+			v.addWarning(node, field+".mappingDefault", "mapping default is set without a mapping, which will be ignored")
+		}
+	}
+}
+
+func (v *graphValidator) validateScriptNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsScript()
+	if strings.TrimSpace(nodeCfg.Script) == "" {
+		v.addError(node, "config.script", "script is required")
+	} else if err := engine.CheckScript(nodeCfg.Script); err != nil {
+		v.addError(node, "config.script", "invalid script: %s", err.Error())
+	}
+}
+
+func (v *graphValidator) validateHttpRequestNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsHttpRequest()
+	switch strings.ToUpper(nodeCfg.Method) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		v.addError(node, "config.method", "unsupported request method '%s'", nodeCfg.Method)
+	}
+
+	if nodeCfg.Url == "" {
+		v.addError(node, "config.url", "url is required")
+	} else if err := engine.CheckTemplate(nodeCfg.Url); err != nil {
+		v.addError(node, "config.url", "invalid template: %s", err.Error())
+	} else if !strings.Contains(nodeCfg.Url, "{{") {
+		if u, err := url.Parse(nodeCfg.Url); err != nil {
+			v.addError(node, "config.url", "invalid url: %s", err.Error())
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			v.addError(node, "config.url", "unsupported url scheme '%s'", u.Scheme)
+		}
+	}
+
+	for k, value := range nodeCfg.Headers {
+		if err := engine.CheckTemplate(value); err != nil {
+			v.addWarning(node, "config.headers", "invalid template of header '%s', it will be treated as plain text: %s", k, err.Error())
+		}
+	}
+	if err := engine.CheckTemplate(nodeCfg.Body); err != nil {
+		v.addWarning(node, "config.body", "invalid template, it will be treated as plain text: %s", err.Error())
+	}
+
+	if nodeCfg.Timeout < 0 {
+		v.addError(node, "config.timeout", "timeout must not be negative")
+	}
+
+	if nodeCfg.CACertificate != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(nodeCfg.CACertificate)) {
+			v.addError(node, "config.caCertificate", "invalid ca certificate")
+		}
+	}
+	if nodeCfg.ClientCertificate != "" || nodeCfg.ClientPrivateKey != "" {
+		if _, err := tls.X509KeyPair([]byte(nodeCfg.ClientCertificate), []byte(nodeCfg.ClientPrivateKey)); err != nil {
+			v.addError(node, "config.clientCertificate", "invalid client certificate: %s", err.Error())
+		}
+	}
+
+	for name := range nodeCfg.ResponseFields {
+		if name == "" {
+			v.addError(node, "config.responseFields", "variable name of response field is empty")
+		} else if name == "status" || name == "body" {
+			v.addError(node, "config.responseFields", "variable name '%s' of response field is reserved", name)
+		}
+	}
+}
+
+func (v *graphValidator) validateApprovalNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsApproval()
+	if nodeCfg.Provider == "" {
+		v.addWarning(node, "config.provider", "no notification provider is specified, approvers will not be notified")
+	} else if _, err := notifiers.Registries.Get(domain.NotificationProviderType(nodeCfg.Provider)); err != nil {
+		v.addError(node, "config.provider", "unknown notification provider '%s'", nodeCfg.Provider)
+	} else {
+		v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+	}
+
+	if err := engine.CheckTemplate(nodeCfg.Subject); err != nil {
+		v.addWarning(node, "config.subject", "invalid template, it will be treated as plain text: %s", err.Error())
+	}
+	if err := engine.CheckTemplate(nodeCfg.Message); err != nil {
+		v.addWarning(node, "config.message", "invalid template, it will be treated as plain text: %s", err.Error())
+	}
+	if nodeCfg.WaitTimeout < 0 {
+		v.addError(node, "config.waitTimeout", "wait timeout must be greater than or equal to 0")
+	}
+
+	// 并行分支中的节点无法单独暂停
+	for parent := v.parents[node.Id]; parent != nil; parent = v.parents[parent.Id] {
+		if parent.Type == domain.WorkflowNodeTypeParallelBlock {
+			v.addError(node, "", "approval node cannot be placed in parallel branches")
+			break
+		}
+	}
+}
+
+func (v *graphValidator) validateSubWorkflowNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsSubWorkflow()
+	if nodeCfg.WorkflowId == "" {
+		v.addError(node, "config.workflowId", "sub-workflow is required")
+	} else if _, ok := domain.ParseWorkflowBundleWorkflowPlaceholder(nodeCfg.WorkflowId); ok {
+		// 引用导入包中的工作流，导入时才会创建，此处无需校验
+	} else if v.workflow != nil && nodeCfg.WorkflowId == v.workflow.Id {
+		v.addError(node, "config.workflowId", "workflow cannot call itself as a sub-workflow")
+	} else if subWorkflow, err := v.workflowRepo.GetById(v.ctx, nodeCfg.WorkflowId); err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			v.addError(node, "config.workflowId", "sub-workflow #%s does not exist", nodeCfg.WorkflowId)
+		} else {
+			v.addError(node, "config.workflowId", "failed to get sub-workflow #%s: %s", nodeCfg.WorkflowId, err.Error())
+		}
+	} else if !subWorkflow.HasContent {
+		v.addError(node, "config.workflowId", "sub-workflow #%s has not been published", nodeCfg.WorkflowId)
+	}
+
+	if nodeCfg.CertificateOutputNodeId != "" {
+		v.checkCertificateOutputNode(node, nodeCfg.CertificateOutputNodeId)
+	}
+}
+
+func (v *graphValidator) validateBizApplyNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsBizApply()
+	if len(nodeCfg.Domains) == 0 {
+		v.addError(node, "config.domains", "domains are required")
+	}
+
+	// 私有 CA 直接签发证书，无需质询
+	if nodeCfg.CAProvider == string(domain.CAProviderTypePrivateCA) {
+		v.validateBizApplyNodeWithPrivateCA(node, &nodeCfg)
+		return
+	}
+
+	switch nodeCfg.ChallengeType {
+	case "dns-01":
+		if _, err := applicators.ACMEDns01Registries.Get(domain.ACMEDns01ProviderType(nodeCfg.Provider)); err != nil {
+			v.addError(node, "config.provider", "unknown dns-01 provider '%s'", nodeCfg.Provider)
+		} else {
+			v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+		}
+
+	case "http-01":
+		if _, err := applicators.ACMEHttp01Registries.Get(domain.ACMEHttp01ProviderType(nodeCfg.Provider)); err != nil {
+			v.addError(node, "config.provider", "unknown http-01 provider '%s'", nodeCfg.Provider)
+		} else {
+			v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+		}
+
+	case "tls-alpn-01":
+		if _, err := applicators.ACMETlsAlpn01Registries.Get(domain.ACMETlsAlpn01ProviderType(nodeCfg.Provider)); err != nil {
+			v.addError(node, "config.provider", "unknown tls-alpn-01 provider '%s'", nodeCfg.Provider)
+		} else {
+			v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+		}
+
+	default:
+		v.addError(node, "config.challengeType", "unsupported challenge type '%s'", nodeCfg.ChallengeType)
+	}
+
+	// CA 提供商零值时使用全局配置
+	if nodeCfg.CAProvider != "" {
+		if !slices.Contains(caProviders, domain.CAProviderType(nodeCfg.CAProvider)) {
+			v.addError(node, "config.caProvider", "unknown ca provider '%s'", nodeCfg.CAProvider)
+		} else {
+			v.checkProviderAccess(node, "config.caProviderAccessId", nodeCfg.CAProvider, nodeCfg.CAProviderAccessId)
+		}
+	}
+}
+
+func (v *graphValidator) validateBizApplyNodeWithPrivateCA(node *domain.WorkflowNode, nodeCfg *domain.WorkflowNodeConfigForBizApply) {
+	if privateCAId := xmaps.GetString(nodeCfg.CAProviderConfig, "privateCaId"); privateCAId == "" {
+		v.addError(node, "config.caProviderConfig.privateCaId", "private ca is required")
+	} else if _, err := v.privateCARepo.GetById(v.ctx, privateCAId); err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			v.addError(node, "config.caProviderConfig.privateCaId", "private ca #%s does not exist", privateCAId)
+		} else {
+			v.addError(node, "config.caProviderConfig.privateCaId", "failed to get private ca #%s: %s", privateCAId, err.Error())
+		}
+	}
+
+	for _, name := range nodeCfg.Domains {
+		if err := privateca.CheckSubjectAltName(name); err != nil {
+			v.addError(node, "config.domains", "%s", err.Error())
+		}
+	}
+
+	if err := privateca.CheckUsages(
+		xmaps.GetString(nodeCfg.CAProviderConfig, "profile"),
+		privateca.SplitUsages(xmaps.GetString(nodeCfg.CAProviderConfig, "keyUsages")),
+		privateca.SplitUsages(xmaps.GetString(nodeCfg.CAProviderConfig, "extKeyUsages")),
+	); err != nil {
+		v.addError(node, "config.caProviderConfig", "%s", err.Error())
+	}
+}
+
+func (v *graphValidator) validateBizMonitorNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsBizMonitor()
+	if nodeCfg.Host == "" {
+		v.addError(node, "config.host", "host is required")
+	}
+	if nodeCfg.Port <= 0 || nodeCfg.Port > 65535 {
+		v.addError(node, "config.port", "invalid port %d", nodeCfg.Port)
+	}
+}
+
+func (v *graphValidator) validateBizDeployNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsBizDeploy()
+	if nodeCfg.CertificateOutputNodeId == "" {
+		v.addError(node, "config.certificateOutputNodeId", "certificate source node is required")
+	} else {
+		v.checkCertificateOutputNode(node, nodeCfg.CertificateOutputNodeId)
+	}
+
+	if _, err := deployers.Registries.Get(domain.DeploymentProviderType(nodeCfg.Provider)); err != nil {
+		v.addError(node, "config.provider", "unknown deployment provider '%s'", nodeCfg.Provider)
+	} else {
+		v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+	}
+}
+
+func (v *graphValidator) validateBizNotifyNode(node *domain.WorkflowNode) {
+	nodeCfg := node.Data.Config.AsBizNotify()
+	if _, err := notifiers.Registries.Get(domain.NotificationProviderType(nodeCfg.Provider)); err != nil {
+		v.addError(node, "config.provider", "unknown notification provider '%s'", nodeCfg.Provider)
+	} else {
+		v.checkProviderAccess(node, "config.providerAccessId", nodeCfg.Provider, nodeCfg.ProviderAccessId)
+	}
+
+	if nodeCfg.Subject == "" && nodeCfg.Message == "" {
+		v.addWarning(node, "config.message", "notification subject and message are both empty")
+	}
+	if err := engine.CheckTemplate(nodeCfg.Subject); err != nil {
+		v.addWarning(node, "config.subject", "invalid template, it will be treated as plain text: %s", err.Error())
+	}
+	if err := engine.CheckTemplate(nodeCfg.Message); err != nil {
+		v.addWarning(node, "config.message", "invalid template, it will be treated as plain text: %s", err.Error())
+	}
+}
+
+// 校验提供商的授权记录。提供商类型中短横线前的部分始终等于授权提供商类型。
+func (v *graphValidator) checkProviderAccess(node *domain.WorkflowNode, field string, provider string, accessId string) {
+	accessProvider := domain.AccessProviderType(strings.Split(provider, "-")[0])
+	if accessId == "" {
+		if !slices.Contains(builtinAccessProviders, accessProvider) {
+			v.addError(node, field, "access is required for provider '%s'", provider)
+		}
+		return
+	}
+
+	access, err := v.accessRepo.GetById(v.ctx, accessId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			v.addError(node, field, "access #%s does not exist", accessId)
+		} else {
+			v.addError(node, field, "failed to get access #%s: %s", accessId, err.Error())
+		}
+		return
+	}
+
+	if access.Provider != string(accessProvider) {
+		v.addError(node, field, "access #%s is of provider '%s', but provider '%s' requires '%s'", accessId, access.Provider, provider, accessProvider)
+	}
+}
+
+func (v *graphValidator) checkCertificateOutputNode(node *domain.WorkflowNode, refNodeId string) {
+	refNode, ok := v.checkNodeReference(node, "config.certificateOutputNodeId", refNodeId)
+	if !ok {
+		return
+	}
+
+	switch refNode.Type {
+	case domain.WorkflowNodeTypeBizApply, domain.WorkflowNodeTypeBizUpload, domain.WorkflowNodeTypeSubWorkflow:
+	default:
+		v.addError(node, "config.certificateOutputNodeId", "node #%s of type '%s' does not output a certificate", refNode.Id, refNode.Type)
+	}
+}
+
+// 校验被引用的节点存在，且在当前节点之前执行。
+func (v *graphValidator) checkNodeReference(node *domain.WorkflowNode, field string, refNodeId string) (*domain.WorkflowNode, bool) {
+	refNode, ok := v.nodes[refNodeId]
+	if !ok {
+		v.addError(node, field, "referenced node #%s does not exist", refNodeId)
+		return nil, false
+	}
+
+	switch v.getPrecedence(refNodeId, node) {
+	case nodePrecedenceDefinite:
+		if refNode.Data.Disabled {
+			v.addWarning(node, field, "referenced node #%s is disabled", refNodeId)
+		}
+	case nodePrecedencePossible:
+		v.addWarning(node, field, "referenced node #%s is in a branch that may not be executed before this node", refNodeId)
+	default:
+		v.addError(node, field, "referenced node #%s is not executed before this node, it is either after this node or in another branch", refNodeId)
+		return refNode, false
+	}
+
+	return refNode, true
+}
+
+type nodePrecedence int
+
+const (
+	nodePrecedenceNone     nodePrecedence = iota // 不会在目标节点之前执行
+	nodePrecedencePossible                       // 可能在目标节点之前执行，如位于前序条件分支中
+	nodePrecedenceDefinite                       // 一定在目标节点之前执行
+)
+
+// 判断节点是否在目标节点之前执行。
+// 仅顺序执行的节点列表中，位于目标节点（或其祖先节点）之前的兄弟节点一定在目标节点之前执行；
+// 这些兄弟节点的子孙节点，以及异常捕获分支对应的尝试分支中的节点，可能在目标节点之前执行。
+func (v *graphValidator) getPrecedence(nodeId string, target *domain.WorkflowNode) nodePrecedence {
+	precedence := nodePrecedenceNone
+	for current := target; current != nil; current = v.parents[current.Id] {
+		parent := v.parents[current.Id]
+
+		var siblings []*domain.WorkflowNode
+		var sequential bool
+		if parent == nil {
+			siblings, sequential = v.graph.Nodes, true
+		} else {
+			siblings, sequential = parent.Blocks, isWorkflowBlockNodeType(parent.Type)
+		}
+
+		for _, sibling := range siblings {
+			if sibling == current || !sequential {
+				break
+			}
+
+			if sibling.Id == nodeId {
+				return nodePrecedenceDefinite
+			} else if containsWorkflowNode(sibling.Blocks, nodeId) {
+				precedence = nodePrecedencePossible
+			}
+		}
+
+		// 尝试分支中的节点可能在异常捕获分支之前执行
+		if parent != nil && parent.Type == domain.WorkflowNodeTypeTryCatch && current.Type == domain.WorkflowNodeTypeCatchBlock {
+			for _, sibling := range siblings {
+				if sibling.Type == domain.WorkflowNodeTypeTryBlock && containsWorkflowNode(sibling.Blocks, nodeId) {
+					precedence = nodePrecedencePossible
+				}
+			}
+		}
+	}
+
+	return precedence
+}
+
+func isWorkflowBlockNodeType(nodeType domain.WorkflowNodeType) bool {
+	switch nodeType {
+	case domain.WorkflowNodeTypeBranchBlock, domain.WorkflowNodeTypeTryBlock, domain.WorkflowNodeTypeCatchBlock, domain.WorkflowNodeTypeParallelBlock:
+		return true
+	}
+	return false
+}
+
+func containsWorkflowNode(nodes []*domain.WorkflowNode, nodeId string) bool {
+	found := false
+	walkWorkflowNodes(nodes, func(node *domain.WorkflowNode) {
+		if node.Id == nodeId {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,258 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type validatorTestWorkflowRepository struct {
+	workflowRepository
+	workflows map[string]*domain.Workflow
+}
+
+func (r *validatorTestWorkflowRepository) GetById(ctx context.Context, id string) (*domain.Workflow, error) {
+	if workflow, ok := r.workflows[id]; ok {
+		return workflow, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+type validatorTestAccessRepository struct {
+	accessRepository
+	accesses map[string]*domain.Access
+}
+
+func (r *validatorTestAccessRepository) GetById(ctx context.Context, id string) (*domain.Access, error) {
+	if access, ok := r.accesses[id]; ok {
+		return access, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func TestValidateGraph(t *testing.T) {
+	service := &WorkflowService{
+		workflowRepo: &validatorTestWorkflowRepository{
+			workflows: map[string]*domain.Workflow{
+				"published":   {Meta: domain.Meta{Id: "published"}, HasContent: true},
+				"unpublished": {Meta: domain.Meta{Id: "unpublished"}},
+			},
+		},
+		accessRepo: &validatorTestAccessRepository{
+			accesses: map[string]*domain.Access{
+				"email": {Meta: domain.Meta{Id: "email"}, Provider: string(domain.AccessProviderTypeEmail)},
+			},
+		},
+	}
+
+	type wantIssue struct {
+		nodeId string
+		level  domain.WorkflowGraphIssueLevelType
+		field  string
+	}
+
+	const (
+		levelError   = domain.WorkflowGraphIssueLevelTypeError
+		levelWarning = domain.WorkflowGraphIssueLevelTypeWarning
+	)
+
+	tests := []struct {
+		name     string
+		workflow *domain.Workflow
+		graph    string
+		rawGraph bool
+		want     []wantIssue
+	}{
+		{
+			name:  "minimal",
+			graph: `[]`,
+		},
+		{
+			name:     "invalid graph structure",
+			graph:    `[{"id": "n1", "type": "delay", "data": {"name": "n1"}}]`,
+			rawGraph: true,
+			want:     []wantIssue{{"", levelError, ""}},
+		},
+		{
+			name:     "invalid cron expression",
+			workflow: &domain.Workflow{Trigger: domain.WorkflowTriggerTypeScheduled, TriggerCron: "* * *"},
+			graph:    `[]`,
+			want:     []wantIssue{{"", levelError, "triggerCron"}},
+		},
+		{
+			name:     "days left out of range",
+			workflow: &domain.Workflow{Trigger: domain.WorkflowTriggerTypeEvent, TriggerEvent: &domain.WorkflowTriggerEvent{Type: domain.EventTypeCertificateExpiring, DaysLeft: 91}},
+			graph:    `[]`,
+			want:     []wantIssue{{"", levelError, "triggerEvent.daysLeft"}},
+		},
+		{
+			name: "duplicate node id",
+			graph: `[
+				{"id": "n1", "type": "delay", "data": {"name": "n1"}},
+				{"id": "n1", "type": "delay", "data": {"name": "n1"}}
+			]`,
+			want: []wantIssue{{"n1", levelError, "id"}},
+		},
+		{
+			name:  "unsupported node type",
+			graph: `[{"id": "n1", "type": "unknown", "data": {"name": "n1"}}]`,
+			want:  []wantIssue{{"n1", levelError, "type"}},
+		},
+		{
+			name:  "invalid retry policy",
+			graph: `[{"id": "n1", "type": "delay", "data": {"name": "n1", "retry": {"maxAttempts": 3, "jitter": 2, "retryableErrors": ["("]}}}]`,
+			want:  []wantIssue{{"n1", levelError, "retry.jitter"}, {"n1", levelError, "retry.retryableErrors"}},
+		},
+		{
+			name:  "empty node name",
+			graph: `[{"id": "n1", "type": "delay", "data": {"name": ""}}]`,
+			want:  []wantIssue{{"n1", levelWarning, "name"}},
+		},
+		{
+			name:  "condition without branches",
+			graph: `[{"id": "n1", "type": "condition", "data": {"name": "n1"}}]`,
+			want:  []wantIssue{{"n1", levelError, "blocks"}},
+		},
+		{
+			name: "expression references a later node",
+			graph: `[
+				{"id": "n1", "type": "condition", "data": {"name": "n1"}, "blocks": [
+					{"id": "b1", "type": "branchBlock", "data": {"name": "b1", "config": {"expression": "${n2.certificate.daysLeft:number} < 30"}}}
+				]},
+				{"id": "n2", "type": "bizUpload", "data": {"name": "n2"}}
+			]`,
+			want: []wantIssue{{"b1", levelError, "config.expression"}},
+		},
+		{
+			name: "expression references an unknown variable",
+			graph: `[
+				{"id": "n1", "type": "delay", "data": {"name": "n1", "config": {"wait": 1}}},
+				{"id": "n2", "type": "condition", "data": {"name": "n2"}, "blocks": [
+					{"id": "b1", "type": "branchBlock", "data": {"name": "b1", "config": {"expression": "${n1.certificate.daysLeft:number} < 30"}}}
+				]}
+			]`,
+			want: []wantIssue{{"b1", levelError, "config.expression"}},
+		},
+		{
+			name: "expression references a variable with incompatible type",
+			graph: `[
+				{"id": "n1", "type": "bizUpload", "data": {"name": "n1"}},
+				{"id": "n2", "type": "condition", "data": {"name": "n2"}, "blocks": [
+					{"id": "b1", "type": "branchBlock", "data": {"name": "b1", "config": {"expression": "${n1.certificate.validity:number} == 1"}}}
+				]}
+			]`,
+			want: []wantIssue{{"b1", levelWarning, "config.expression"}},
+		},
+		{
+			name: "catch branch references a node in the try branch",
+			graph: `[
+				{"id": "n1", "type": "tryCatch", "data": {"name": "n1"}, "blocks": [
+					{"id": "t1", "type": "tryBlock", "data": {"name": "t1"}, "blocks": [
+						{"id": "n2", "type": "bizUpload", "data": {"name": "n2"}}
+					]},
+					{"id": "c1", "type": "catchBlock", "data": {"name": "c1"}, "blocks": [
+						{"id": "n3", "type": "bizDeploy", "data": {"name": "n3", "config": {"provider": "local", "certificateOutputNodeId": "n2"}}}
+					]}
+				]}
+			]`,
+			want: []wantIssue{{"n3", levelWarning, "config.certificateOutputNodeId"}},
+		},
+		{
+			name: "certificate source does not output a certificate",
+			graph: `[
+				{"id": "n1", "type": "delay", "data": {"name": "n1", "config": {"wait": 1}}},
+				{"id": "n2", "type": "bizDeploy", "data": {"name": "n2", "config": {"provider": "local", "certificateOutputNodeId": "n1"}}}
+			]`,
+			want: []wantIssue{{"n2", levelError, "config.certificateOutputNodeId"}},
+		},
+		{
+			name: "invalid http request",
+			graph: `[
+				{"id": "n1", "type": "httpRequest", "data": {"name": "n1", "config": {"method": "GET", "url": "ftp://example.com", "body": "{{ if }}"}}}
+			]`,
+			want: []wantIssue{{"n1", levelError, "config.url"}, {"n1", levelWarning, "config.body"}},
+		},
+		{
+			name:     "sub-workflow references itself",
+			workflow: &domain.Workflow{Meta: domain.Meta{Id: "self"}, Trigger: domain.WorkflowTriggerTypeManual},
+			graph:    `[{"id": "n1", "type": "subWorkflow", "data": {"name": "n1", "config": {"workflowId": "self"}}}]`,
+			want:     []wantIssue{{"n1", levelError, "config.workflowId"}},
+		},
+		{
+			name:  "sub-workflow does not exist",
+			graph: `[{"id": "n1", "type": "subWorkflow", "data": {"name": "n1", "config": {"workflowId": "missing"}}}]`,
+			want:  []wantIssue{{"n1", levelError, "config.workflowId"}},
+		},
+		{
+			name:  "sub-workflow is not published",
+			graph: `[{"id": "n1", "type": "subWorkflow", "data": {"name": "n1", "config": {"workflowId": "unpublished"}}}]`,
+			want:  []wantIssue{{"n1", levelError, "config.workflowId"}},
+		},
+		{
+			name:  "sub-workflow is published",
+			graph: `[{"id": "n1", "type": "subWorkflow", "data": {"name": "n1", "config": {"workflowId": "published"}}}]`,
+		},
+		{
+			name:  "notification without access",
+			graph: `[{"id": "n1", "type": "bizNotify", "data": {"name": "n1", "config": {"provider": "email", "subject": "s", "message": "m"}}}]`,
+			want:  []wantIssue{{"n1", levelError, "config.providerAccessId"}},
+		},
+		{
+			name:  "notification with access of another provider",
+			graph: `[{"id": "n1", "type": "bizNotify", "data": {"name": "n1", "config": {"provider": "webhook", "providerAccessId": "email", "subject": "s", "message": "m"}}}]`,
+			want:  []wantIssue{{"n1", levelError, "config.providerAccessId"}},
+		},
+		{
+			name:  "notification with literal braces",
+			graph: `[{"id": "n1", "type": "bizNotify", "data": {"name": "n1", "config": {"provider": "email", "providerAccessId": "email", "subject": "s", "message": "{{ not a template"}}}]`,
+			want:  []wantIssue{{"n1", levelWarning, "config.message"}},
+		},
+		{
+			name:  "private ca without ca and with invalid domain",
+			graph: `[{"id": "n1", "type": "bizApply", "data": {"name": "n1", "config": {"domains": "a.*.example.com", "caProvider": "privateca", "caProviderConfig": {"profile": "unknown"}}}}]`,
+			want: []wantIssue{
+				{"n1", levelError, "config.caProviderConfig.privateCaId"},
+				{"n1", levelError, "config.domains"},
+				{"n1", levelError, "config.caProviderConfig"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []*domain.WorkflowNode
+			if err := json.Unmarshal([]byte(tt.graph), &nodes); err != nil {
+				t.Fatalf("failed to unmarshal graph: %v", err)
+			}
+
+			// 除非指定使用原始画布，否则均在首尾补全开始节点与结束节点
+			graph := &domain.WorkflowGraph{Nodes: nodes}
+			if !tt.rawGraph {
+				graph.Nodes = append([]*domain.WorkflowNode{{Id: "start", Type: domain.WorkflowNodeTypeStart, Data: domain.WorkflowNodeData{Name: "start"}}}, graph.Nodes...)
+				graph.Nodes = append(graph.Nodes, &domain.WorkflowNode{Id: "end", Type: domain.WorkflowNodeTypeEnd, Data: domain.WorkflowNodeData{Name: "end"}})
+			}
+
+			issues := service.validateGraph(context.Background(), tt.workflow, graph)
+
+			matched := make([]bool, len(issues))
+			for _, want := range tt.want {
+				found := false
+				for i, issue := range issues {
+					if !matched[i] && issue.NodeId == want.nodeId && issue.Level == want.level && issue.Field == want.field {
+						matched[i], found = true, true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected %s issue on node '%s' field '%s', got issues: %v", want.level, want.nodeId, want.field, issues)
+				}
+			}
+			for i, issue := range issues {
+				if !matched[i] {
+					t.Errorf("unexpected issue: %v", issue)
+				}
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/certimate-go/certimate/cmd"
 	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/rest/routes"
 	"github.com/certimate-go/certimate/internal/scheduler"
 	"github.com/certimate-go/certimate/internal/workflow"
@@ -44,8 +45,13 @@ func main() {
 	})
 
 	app.RootCmd.AddCommand(cmd.NewInternalCommand(app))
+	app.RootCmd.AddCommand(cmd.NewWorkflowCommand())
 
 	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		if err := privateca.Register(); err != nil {
+			return err
+		}
+
 		scheduler.Register()
 		workflow.Register()
 		routes.Register(e.Router)
@@ -69,6 +75,7 @@ func main() {
 
 	app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
 		routes.Unregister()
+		scheduler.Unregister()
 		return e.Next()
 	})
 
@@ -0,0 +1,133 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// update collection `workflow`
+		//   - modify field `trigger` candidates
+		//   - add field `webhookKey`
+		//   - add field `webhookSecret`
+		{
+			collection, err := app.FindCollectionByNameOrId("tovyif5ax6j62ur")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "vqoajwjq",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"webhook"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "nec0uu4f",
+				"max": 0,
+				"min": 0,
+				"name": "webhookKey",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": true,
+				"id": "eeoivmgk",
+				"max": 0,
+				"min": 0,
+				"name": "webhookSecret",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `workflow_run`
+		//   - modify field `trigger` candidates
+		//   - add field `triggerPayload`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "jlroa3fk",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"webhook"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+				"hidden": false,
+				"id": "ccybmcq8",
+				"maxSize": 1000000,
+				"name": "triggerPayload",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// update collection `workflow_run`
+		//   - add field `parentRunRef`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "qjp8lygssgwyqyz",
+				"hidden": false,
+				"id": "imn7sqeo",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "parentRunRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// update collection `workflow_run`
+		//   - add field `resumedFromRef`
+		//   - add field `snapshot`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "qjp8lygssgwyqyz",
+				"hidden": false,
+				"id": "k2w7hbxq",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "resumedFromRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(11, []byte(`{
+				"hidden": false,
+				"id": "ptx4ce0o",
+				"maxSize": 0,
+				"name": "snapshot",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
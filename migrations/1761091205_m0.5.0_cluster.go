@@ -0,0 +1,215 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// create collection `instance`
+		// create collection `lease`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "1yb36fwx",
+							"max": 0,
+							"min": 0,
+							"name": "hostname",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "hxk94w2e",
+							"max": "",
+							"min": "",
+							"name": "heartbeatAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "sy6klyhkscy130v",
+					"indexes": [],
+					"name": "instance",
+					"system": false,
+					"type": "base"
+				},
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "wo6746h7",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "ix63255t",
+							"max": 0,
+							"min": 0,
+							"name": "holder",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "ju8e7852",
+							"max": "",
+							"min": "",
+							"name": "expiresAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "e6nctpm7tovvq4y",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Lq3v8XkRz1` + "`" + ` ON ` + "`" + `lease` + "`" + ` (` + "`" + `name` + "`" + `)"
+					],
+					"name": "lease",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'instance' created")
+			tracer.Printf("collection 'lease' created")
+		}
+
+		// update collection `workflow_run`
+		//   - add field `claimedBy`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(14, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "0qzu27k2",
+				"max": 0,
+				"min": 0,
+				"name": "claimedBy",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,137 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// create collection `workflow_version`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "tovyif5ax6j62ur",
+							"hidden": false,
+							"id": "hq3cfvj0",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "workflowRef",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"hidden": false,
+							"id": "rcl6wifb",
+							"max": null,
+							"min": 1,
+							"name": "version",
+							"onlyInt": true,
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"hidden": false,
+							"id": "836vhi12",
+							"maxSize": 0,
+							"name": "graph",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "7eidgg32",
+							"max": 0,
+							"min": 0,
+							"name": "author",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "jw95e7gp",
+							"max": 0,
+							"min": 0,
+							"name": "note",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "cd0gpbhsq2g7ie0",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_b28u32135a` + "`" + ` ON ` + "`" + `workflow_version` + "`" + ` (` + "`" + `workflowRef` + "`" + `, ` + "`" + `version` + "`" + `)"
+					],
+					"name": "workflow_version",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'workflow_version' created")
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
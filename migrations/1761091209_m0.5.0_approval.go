@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"slices"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// update collection `workflow`
+		//   - modify field `lastRunStatus`: add value `waiting`
+		// update collection `workflow_run`
+		//   - modify field `status`: add value `waiting`
+		for collectionId, fieldName := range map[string]string{
+			"tovyif5ax6j62ur": "lastRunStatus",
+			"qjp8lygssgwyqyz": "status",
+		} {
+			collection, err := app.FindCollectionByNameOrId(collectionId)
+			if err != nil {
+				return err
+			}
+
+			field, ok := collection.Fields.GetByName(fieldName).(*core.SelectField)
+			if !ok {
+				continue
+			} else if slices.Contains(field.Values, "waiting") {
+				continue
+			}
+
+			field.Values = append(field.Values, "waiting")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,193 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// create collection `private_ca`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "q3vk8x2n",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": true,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": false,
+							"collectionId": "h6zr0pca4mqd1ws",
+							"hidden": false,
+							"id": "b7tjw5ue",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "parentRef",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "m1c9fz4y",
+							"max": 0,
+							"min": 0,
+							"name": "commonName",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "d5nh2r8o",
+							"max": 0,
+							"min": 0,
+							"name": "serialNumber",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "x0ga6lke",
+							"max": 100000,
+							"min": 0,
+							"name": "certificate",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": true,
+							"id": "s4pu9cvi",
+							"max": 100000,
+							"min": 0,
+							"name": "privateKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "k2wf7ybt",
+							"max": 0,
+							"min": 0,
+							"name": "keyAlgorithm",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "z8eo3jqm",
+							"max": "",
+							"min": "",
+							"name": "validityNotBefore",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "g9lr1snd",
+							"max": "",
+							"min": "",
+							"name": "validityNotAfter",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "h6zr0pca4mqd1ws",
+					"indexes": [
+						"CREATE INDEX ` + "`" + `idx_p4cr8tvw2x` + "`" + ` ON ` + "`" + `private_ca` + "`" + ` (` + "`" + `parentRef` + "`" + `)"
+					],
+					"name": "private_ca",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'private_ca' created")
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// backfill collection `workflow_version`
+		//   - record the published graph of existing workflows as their initial version
+		{
+			workflowCollection, err := app.FindCollectionByNameOrId("tovyif5ax6j62ur")
+			if err != nil {
+				return err
+			}
+
+			versionCollection, err := app.FindCollectionByNameOrId("cd0gpbhsq2g7ie0")
+			if err != nil {
+				return err
+			}
+
+			records, err := app.FindAllRecords(workflowCollection)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				graphContent := struct {
+					Nodes []any `json:"nodes"`
+				}{}
+				if err := record.UnmarshalJSONField("graphContent", &graphContent); err != nil || len(graphContent.Nodes) == 0 {
+					continue
+				}
+
+				total, err := app.CountRecords(versionCollection, dbx.HashExp{"workflowRef": record.Id})
+				if err != nil {
+					return err
+				} else if total > 0 {
+					continue
+				}
+
+				versionRecord := core.NewRecord(versionCollection)
+				versionRecord.Set("workflowRef", record.Id)
+				versionRecord.Set("version", 1)
+				versionRecord.Set("graph", record.Get("graphContent"))
+				versionRecord.Set("note", "initial version")
+				if err := app.Save(versionRecord); err != nil {
+					return err
+				}
+
+				tracer.Printf("record #%s in collection '%s' created", versionRecord.Id, versionCollection.Name)
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,265 @@
+package internal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/miekg/dns"
+)
+
+const (
+	envNamespace = "RFC2136_"
+
+	EnvNameserver    = envNamespace + "NAMESERVER"
+	EnvZone          = envNamespace + "ZONE"
+	EnvTSIGAlgorithm = envNamespace + "TSIG_ALGORITHM"
+	EnvTSIGKey       = envNamespace + "TSIG_KEY"
+	EnvTSIGSecret    = envNamespace + "TSIG_SECRET"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvDNSTimeout         = envNamespace + "DNS_TIMEOUT"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+type Config struct {
+	Nameserver string
+	Zone       string
+
+	TSIGAlgorithm string
+	TSIGKey       string
+	TSIGSecret    string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	DNSTimeout         time.Duration
+}
+
+type DNSProvider struct {
+	config *Config
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		TSIGAlgorithm:      env.GetOrDefaultString(EnvTSIGAlgorithm, dns.HmacSHA256),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		DNSTimeout:         env.GetOrDefaultSecond(EnvDNSTimeout, 10*time.Second),
+	}
+}
+
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvNameserver)
+	if err != nil {
+		return nil, fmt.Errorf("rfc2136: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Nameserver = values[EnvNameserver]
+	config.Zone = env.GetOrDefaultString(EnvZone, "")
+	config.TSIGKey = env.GetOrFile(EnvTSIGKey)
+	config.TSIGSecret = env.GetOrFile(EnvTSIGSecret)
+
+	return NewDNSProviderConfig(config)
+}
+
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("rfc2136: the configuration of the DNS provider is nil")
+	}
+
+	if config.Nameserver == "" {
+		return nil, errors.New("rfc2136: nameserver missing")
+	}
+
+	// 未指定端口时使用默认端口 53
+	if _, _, err := net.SplitHostPort(config.Nameserver); err != nil {
+		var addrErr *net.AddrError
+		if errors.As(err, &addrErr) && addrErr.Err == "missing port in address" {
+			config.Nameserver = net.JoinHostPort(strings.Trim(config.Nameserver, "[]"), "53")
+		} else {
+			return nil, fmt.Errorf("rfc2136: invalid nameserver '%s': %w", config.Nameserver, err)
+		}
+	}
+
+	if config.Zone != "" {
+		config.Zone = dns.CanonicalName(config.Zone)
+	}
+
+	if config.TSIGKey == "" || config.TSIGSecret == "" {
+		config.TSIGKey = ""
+		config.TSIGSecret = ""
+	} else {
+		// 密钥名称需为规范形式，参考 RFC 4034 Section 6.2
+		config.TSIGKey = dns.CanonicalName(config.TSIGKey)
+
+		if _, err := base64.StdEncoding.DecodeString(config.TSIGSecret); err != nil {
+			return nil, fmt.Errorf("rfc2136: TSIG secret is not a valid base64 string: %w", err)
+		}
+
+		algorithm, err := normalizeTSIGAlgorithm(config.TSIGAlgorithm)
+		if err != nil {
+			return nil, fmt.Errorf("rfc2136: %w", err)
+		}
+		config.TSIGAlgorithm = algorithm
+	}
+
+	return &DNSProvider{config: config}, nil
+}
+
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := d.findZone(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("rfc2136: could not find zone for domain %q: %w", domain, err)
+	}
+
+	msg := new(dns.Msg).SetUpdate(zone)
+	msg.Insert([]dns.RR{d.newTXTRecord(info.EffectiveFQDN, info.Value)})
+	if err := d.sendUpdate(msg); err != nil {
+		return fmt.Errorf("rfc2136: failed to insert record: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := d.findZone(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("rfc2136: could not find zone for domain %q: %w", domain, err)
+	}
+
+	// 仅删除本次质询的记录，不影响同名的其他记录（如同时申请泛域名与主域名时）
+	msg := new(dns.Msg).SetUpdate(zone)
+	msg.Remove([]dns.RR{d.newTXTRecord(info.EffectiveFQDN, info.Value)})
+	if err := d.sendUpdate(msg); err != nil {
+		return fmt.Errorf("rfc2136: failed to remove record: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// 返回用于检查 DNS 传播情况的域名服务器，即接受动态更新的服务器。
+// 区域可能仅在内网可见，公共递归解析器及其权威服务器无法查询到质询记录。
+func (d *DNSProvider) PropagationNameservers() []string {
+	return []string{d.config.Nameserver}
+}
+
+func (d *DNSProvider) newTXTRecord(fqdn, value string) dns.RR {
+	return &dns.TXT{
+		Hdr: dns.RR_Header{Name: fqdn, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: uint32(d.config.TTL)},
+		Txt: []string{value},
+	}
+}
+
+// 向配置的域名服务器逐级查询 SOA 记录以确定所属区域。
+// 与 [dns01.FindZoneByFqdn] 不同，不依赖公共递归解析器，适用于仅在内网可见的区域。
+func (d *DNSProvider) findZone(fqdn string) (string, error) {
+	if d.config.Zone != "" {
+		if !dns.IsSubDomain(d.config.Zone, dns.CanonicalName(fqdn)) {
+			return "", fmt.Errorf("'%s' is not in zone '%s'", fqdn, d.config.Zone)
+		}
+		return d.config.Zone, nil
+	}
+
+	var lastErr error
+	for _, index := range dns.Split(fqdn) {
+		name := dns.CanonicalName(fqdn[index:])
+
+		msg := new(dns.Msg).SetQuestion(name, dns.TypeSOA)
+		reply, err := d.exchange(msg)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		switch reply.Rcode {
+		case dns.RcodeSuccess, dns.RcodeNameError:
+			// 区域顶点返回于应答部分，区域内的其他名称返回于权威部分
+			for _, rr := range append(reply.Answer, reply.Ns...) {
+				if soa, ok := rr.(*dns.SOA); ok {
+					return dns.CanonicalName(soa.Hdr.Name), nil
+				}
+			}
+		default:
+			lastErr = fmt.Errorf("server replied: %s", dns.RcodeToString[reply.Rcode])
+		}
+	}
+
+	if lastErr != nil {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("no SOA record found on nameserver %s", d.config.Nameserver)
+}
+
+func (d *DNSProvider) sendUpdate(msg *dns.Msg) error {
+	reply, err := d.exchange(msg)
+	if err != nil {
+		return err
+	} else if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("server replied: %s", dns.RcodeToString[reply.Rcode])
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) exchange(msg *dns.Msg) (*dns.Msg, error) {
+	client := &dns.Client{Timeout: d.config.DNSTimeout}
+	if d.config.TSIGKey != "" {
+		msg.SetTsig(d.config.TSIGKey, d.config.TSIGAlgorithm, 300, time.Now().Unix())
+		client.TsigSecret = map[string]string{d.config.TSIGKey: d.config.TSIGSecret}
+	}
+
+	reply, _, err := client.Exchange(msg, d.config.Nameserver)
+	if err == nil && reply.Truncated {
+		// 应答被截断时改用 TCP 重试
+		client.Net = "tcp"
+		reply, _, err = client.Exchange(msg, d.config.Nameserver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func normalizeTSIGAlgorithm(algorithm string) (string, error) {
+	if algorithm == "" {
+		return dns.HmacSHA256, nil
+	}
+
+	switch strings.TrimSuffix(strings.ToLower(algorithm), ".") {
+	case "hmac-md5", "hmac-md5.sig-alg.reg.int":
+		return dns.HmacMD5, nil
+	case "hmac-sha1":
+		return dns.HmacSHA1, nil
+	case "hmac-sha224":
+		return dns.HmacSHA224, nil
+	case "hmac-sha256":
+		return dns.HmacSHA256, nil
+	case "hmac-sha384":
+		return dns.HmacSHA384, nil
+	case "hmac-sha512":
+		return dns.HmacSHA512, nil
+	}
+
+	return "", fmt.Errorf("unsupported TSIG algorithm '%s'", algorithm)
+}
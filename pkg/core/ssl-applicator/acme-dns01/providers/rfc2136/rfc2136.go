@@ -0,0 +1,54 @@
+package rfc2136
+
+import (
+	"errors"
+	"time"
+
+	"github.com/certimate-go/certimate/pkg/core"
+	"github.com/certimate-go/certimate/pkg/core/ssl-applicator/acme-dns01/providers/rfc2136/internal"
+)
+
+type ChallengeProviderConfig struct {
+	// 接受动态更新的域名服务器地址，形如 "ns1.example.com"、"10.0.0.53:5353"。
+	Nameserver string `json:"nameserver"`
+	// 区域名称。
+	// 零值时向域名服务器查询 SOA 记录自动检测。
+	Zone string `json:"zone,omitempty"`
+	// TSIG 签名算法。
+	// 可取值 "hmac-md5"、"hmac-sha1"、"hmac-sha224"、"hmac-sha256"、"hmac-sha384"、"hmac-sha512"。
+	// 零值时默认值 "hmac-sha256"。
+	TsigAlgorithm string `json:"tsigAlgorithm,omitempty"`
+	// TSIG 密钥名称。
+	// 零值时不进行 TSIG 签名。
+	TsigKey string `json:"tsigKey,omitempty"`
+	// TSIG 密钥，Base64 编码。
+	TsigSecret            string `json:"tsigSecret,omitempty"`
+	DnsPropagationTimeout int32  `json:"dnsPropagationTimeout,omitempty"`
+	DnsTTL                int32  `json:"dnsTTL,omitempty"`
+}
+
+func NewChallengeProvider(config *ChallengeProviderConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, errors.New("the configuration of the acme challenge provider is nil")
+	}
+
+	providerConfig := internal.NewDefaultConfig()
+	providerConfig.Nameserver = config.Nameserver
+	providerConfig.Zone = config.Zone
+	providerConfig.TSIGAlgorithm = config.TsigAlgorithm
+	providerConfig.TSIGKey = config.TsigKey
+	providerConfig.TSIGSecret = config.TsigSecret
+	if config.DnsPropagationTimeout != 0 {
+		providerConfig.PropagationTimeout = time.Duration(config.DnsPropagationTimeout) * time.Second
+	}
+	if config.DnsTTL != 0 {
+		providerConfig.TTL = int(config.DnsTTL)
+	}
+
+	provider, err := internal.NewDNSProviderConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
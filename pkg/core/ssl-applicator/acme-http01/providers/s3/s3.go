@@ -0,0 +1,105 @@
+﻿package s3
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/pkg/core"
+	s3sdk "github.com/certimate-go/certimate/pkg/sdk3rd/s3"
+)
+
+type ChallengeProviderConfig struct {
+	// S3 兼容服务的端点，如 "https://s3.us-east-1.amazonaws.com"、"http://127.0.0.1:9000"。
+	// 未指定协议时默认使用 HTTPS。
+	Endpoint string `json:"endpoint"`
+	// 区域。
+	Region string `json:"region"`
+	// 访问密钥 ID。
+	AccessKeyId string `json:"accessKeyId"`
+	// 访问密钥。
+	SecretAccessKey string `json:"secretAccessKey"`
+	// 是否使用路径风格访问存储桶。
+	// 为 false 时使用虚拟主机风格。
+	UsePathStyle bool `json:"usePathStyle,omitempty"`
+	// 是否允许不安全的连接。
+	AllowInsecureConnections bool `json:"allowInsecureConnections,omitempty"`
+	// 存储桶名。
+	Bucket string `json:"bucket"`
+	// 对象键前缀，即网站根目录在存储桶中的路径。
+	// 零值时表示存储桶根目录。
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// 质询文件的访问控制策略，即 `x-amz-acl` 请求头，如 "public-read"、"private"。
+	// 零值时默认值 "public-read"；值为 "none" 时不设置，以存储桶的策略为准。
+	ACL string `json:"acl,omitempty"`
+}
+
+// 将质询文件写入 S3 兼容的对象存储，适用于以存储桶作为网站源站的场景。
+func NewChallengeProvider(config *ChallengeProviderConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, errors.New("the configuration of the acme challenge provider is nil")
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("the bucket of the acme challenge provider is empty")
+	}
+
+	client, err := s3sdk.NewClient(config.Endpoint, config.Region, config.AccessKeyId, config.SecretAccessKey, config.UsePathStyle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sdk client: %w", err)
+	} else {
+		client.SetTimeout(30 * time.Second)
+		if config.AllowInsecureConnections {
+			client.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
+		}
+	}
+
+	if config.ACL == "" {
+		config.ACL = "public-read"
+	}
+
+	provider := &provider{config: config, sdkClient: client}
+	return provider, nil
+}
+
+type provider struct {
+	config    *ChallengeProviderConfig
+	sdkClient *s3sdk.Client
+}
+
+func (p *provider) Present(domain, token, keyAuth string) error {
+	objectKey := p.getObjectKey(token)
+	if _, err := p.sdkClient.PutObjectWithContext(context.Background(), &s3sdk.PutObjectRequest{
+		Bucket:      p.config.Bucket,
+		Key:         objectKey,
+		Body:        []byte(keyAuth),
+		ContentType: "text/plain",
+		ACL:         lo.Ternary(p.config.ACL == "none", "", p.config.ACL),
+	}); err != nil {
+		return fmt.Errorf("failed to upload challenge file '%s' to bucket '%s': %w", objectKey, p.config.Bucket, err)
+	}
+
+	return nil
+}
+
+func (p *provider) CleanUp(domain, token, keyAuth string) error {
+	objectKey := p.getObjectKey(token)
+	if _, err := p.sdkClient.DeleteObjectWithContext(context.Background(), &s3sdk.DeleteObjectRequest{
+		Bucket: p.config.Bucket,
+		Key:    objectKey,
+	}); err != nil {
+		return fmt.Errorf("failed to delete challenge file '%s' from bucket '%s': %w", objectKey, p.config.Bucket, err)
+	}
+
+	return nil
+}
+
+func (p *provider) getObjectKey(token string) string {
+	return strings.TrimPrefix(path.Join("/", p.config.PathPrefix, http01.ChallengePath(token)), "/")
+}
@@ -0,0 +1,223 @@
+﻿package standalone
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/log"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengeProviderConfig struct {
+	// 监听地址，形如 "0.0.0.0:80"、":8080"。
+	// 零值时默认值 ":80"。
+	ListenAddress string `json:"listenAddress,omitempty"`
+	// 是否启用转发模式。
+	// 启用后，由反向代理将质询请求转发至监听地址，并以反向代理传递的请求头校验原始主机名。
+	ForwardMode bool `json:"forwardMode,omitempty"`
+	// 转发模式下传递原始主机名的请求头，支持 RFC 7239 定义的 "Forwarded"。
+	// 零值时默认值 "X-Forwarded-Host"。
+	ForwardedHeader string `json:"forwardedHeader,omitempty"`
+	// 监听端口被占用时的最长等待时间（单位：秒）。
+	// 多个申请任务（包括多进程模式下的子进程）使用同一监听地址时，将依次等待端口释放。
+	// 零值时默认值 60。
+	ListenWaitTimeout int32 `json:"listenWaitTimeout,omitempty"`
+}
+
+func NewChallengeProvider(config *ChallengeProviderConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, errors.New("the configuration of the acme challenge provider is nil")
+	}
+
+	if _, _, err := net.SplitHostPort(lookupListenAddress(config)); err != nil {
+		return nil, fmt.Errorf("invalid listen address '%s': %w", config.ListenAddress, err)
+	}
+
+	provider := &provider{
+		config: config,
+		tokens: make(map[string]challengeToken),
+	}
+	return provider, nil
+}
+
+type provider struct {
+	config *ChallengeProviderConfig
+
+	mtx    sync.Mutex
+	tokens map[string]challengeToken // Key: Token
+	server *http.Server
+}
+
+type challengeToken struct {
+	Domain  string
+	KeyAuth string
+}
+
+func (p *provider) Present(domain, token, keyAuth string) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.tokens[token] = challengeToken{Domain: domain, KeyAuth: keyAuth}
+
+	// 同一申请任务的多个域名共用一个监听
+	if p.server == nil {
+		listener, err := p.listen()
+		if err != nil {
+			delete(p.tokens, token)
+			return fmt.Errorf("could not start HTTP server for challenge: %w", err)
+		}
+
+		p.server = &http.Server{
+			Handler:           p,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func(server *http.Server) {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Warnf("[standalone] http server stopped: %v", err)
+			}
+		}(p.server)
+
+		log.Infof("[standalone] serving HTTP challenge on %s", listener.Addr().String())
+	}
+
+	return nil
+}
+
+func (p *provider) CleanUp(domain, token, keyAuth string) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.tokens, token)
+
+	if len(p.tokens) == 0 && p.server != nil {
+		err := p.server.Close()
+		p.server = nil
+		if err != nil {
+			return fmt.Errorf("could not stop HTTP server for challenge: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const CHALLENGE_PATH_PREFIX = "/.well-known/acme-challenge/"
+
+	if !strings.HasPrefix(r.URL.Path, CHALLENGE_PATH_PREFIX) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, CHALLENGE_PATH_PREFIX)
+
+	p.mtx.Lock()
+	challenge, ok := p.tokens[token]
+	p.mtx.Unlock()
+	if !ok || r.URL.Path != http01.ChallengePath(token) {
+		http.NotFound(w, r)
+		return
+	}
+
+	host := p.lookupRequestHost(r)
+	if !strings.EqualFold(strings.TrimSuffix(host, "."), strings.TrimSuffix(challenge.Domain, ".")) {
+		if p.config.ForwardMode {
+			log.Warnf("[standalone] received request for '%s' with host '%s', please ensure the reverse proxy passes the '%s' header", challenge.Domain, host, p.lookupForwardedHeader())
+		} else {
+			log.Warnf("[standalone] received request for '%s' with host '%s'", challenge.Domain, host)
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(challenge.KeyAuth))
+	log.Infof("[%s] served key authentication", challenge.Domain)
+}
+
+func (p *provider) listen() (net.Listener, error) {
+	address := lookupListenAddress(p.config)
+
+	waitTimeout := 60 * time.Second
+	if p.config.ListenWaitTimeout > 0 {
+		waitTimeout = time.Duration(p.config.ListenWaitTimeout) * time.Second
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	waiting := false
+	for {
+		listener, err := net.Listen("tcp", address)
+		if err == nil {
+			return listener, nil
+		} else if !errors.Is(err, syscall.EADDRINUSE) || time.Now().After(deadline) {
+			return nil, err
+		}
+
+		if !waiting {
+			waiting = true
+			log.Infof("[standalone] address %s is in use, waiting for it to be released ...", address)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (p *provider) lookupRequestHost(r *http.Request) string {
+	host := r.Host
+	if p.config.ForwardMode {
+		header := p.lookupForwardedHeader()
+		value := r.Header.Get(header)
+		if strings.EqualFold(header, "Forwarded") {
+			value = parseForwardedHost(value)
+		} else if i := strings.Index(value, ","); i >= 0 {
+			// 经过多级代理时取第一个值，即客户端请求的主机名
+			value = value[:i]
+		}
+		host = strings.TrimSpace(value)
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host
+}
+
+func (p *provider) lookupForwardedHeader() string {
+	if p.config.ForwardedHeader == "" {
+		return "X-Forwarded-Host"
+	}
+	return p.config.ForwardedHeader
+}
+
+func lookupListenAddress(config *ChallengeProviderConfig) string {
+	if config.ListenAddress == "" {
+		return ":80"
+	}
+	return config.ListenAddress
+}
+
+// 解析形如 `for=192.0.2.60;proto=http;host=example.com` 的 Forwarded 请求头，取第一个元素中的 host 参数。
+func parseForwardedHost(value string) string {
+	if i := strings.Index(value, ","); i >= 0 {
+		value = value[:i]
+	}
+
+	for _, pair := range strings.Split(value, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(k, "host") {
+			return strings.Trim(v, "\"")
+		}
+	}
+
+	return ""
+}
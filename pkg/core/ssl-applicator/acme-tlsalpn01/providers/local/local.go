@@ -0,0 +1,36 @@
+﻿package local
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengeProviderConfig struct {
+	// 监听地址，形如 "0.0.0.0:443"、":8443"。
+	// 零值时默认值 ":443"。
+	ListenAddress string `json:"listenAddress,omitempty"`
+}
+
+func NewChallengeProvider(config *ChallengeProviderConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, errors.New("the configuration of the acme challenge provider is nil")
+	}
+
+	listenAddress := config.ListenAddress
+	if listenAddress == "" {
+		listenAddress = ":443"
+	}
+
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address '%s': %w", listenAddress, err)
+	}
+
+	provider := tlsalpn01.NewProviderServer(host, port)
+	return provider, nil
+}
@@ -0,0 +1,290 @@
+﻿package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/certimate-go/certimate/pkg/core"
+	xssh "github.com/certimate-go/certimate/pkg/utils/ssh"
+)
+
+type ServerConfig struct {
+	// SSH 主机。
+	// 零值时默认值 "localhost"。
+	SshHost string `json:"sshHost,omitempty"`
+	// SSH 端口。
+	// 零值时默认值 22。
+	SshPort int32 `json:"sshPort,omitempty"`
+	// SSH 认证方式。
+	// 可取值 "none"、"password"、"key"。
+	// 零值时根据有无密码或私钥字段决定。
+	SshAuthMethod string `json:"sshAuthMethod,omitempty"`
+	// SSH 登录用户名。
+	// 零值时默认值 "root"。
+	SshUsername string `json:"sshUsername,omitempty"`
+	// SSH 登录密码。
+	SshPassword string `json:"sshPassword,omitempty"`
+	// SSH 登录私钥。
+	SshKey string `json:"sshKey,omitempty"`
+	// SSH 登录私钥口令。
+	SshKeyPassphrase string `json:"sshKeyPassphrase,omitempty"`
+}
+
+type ChallengeProviderConfig struct {
+	ServerConfig
+
+	// 跳板机配置数组。
+	JumpServers []ServerConfig `json:"jumpServers,omitempty"`
+	// 远程服务器上质询应答程序的监听端口。
+	// 零值时默认值 443。
+	ListenPort int32 `json:"listenPort,omitempty"`
+	// 远程服务器上存放临时证书的目录。
+	// 零值时默认值 "/tmp"。
+	WorkDir string `json:"workDir,omitempty"`
+	// 远程服务器上 OpenSSL 可执行文件的路径。
+	// 零值时默认值 "openssl"。
+	OpenSSLPath string `json:"opensslPath,omitempty"`
+}
+
+// 通过 SSH 在远程服务器上临时运行 `openssl s_server` 作为 TLS-ALPN-01 质询应答程序。
+// 远程服务器需为类 Unix 系统并已安装 OpenSSL 1.1.0 及以上版本，且监听端口在质询期间未被其他程序占用。
+func NewChallengeProvider(config *ChallengeProviderConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, errors.New("the configuration of the acme challenge provider is nil")
+	}
+
+	provider := &provider{config: config}
+	return provider, nil
+}
+
+type provider struct {
+	config *ChallengeProviderConfig
+}
+
+func (p *provider) Present(domain, token, keyAuth string) error {
+	// 生成带有 acmeIdentifier 扩展的质询证书
+	certPEM, keyPEM, err := tlsalpn01.ChallengeBlocks(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("failed to generate certificate for TLS-ALPN challenge: %w", err)
+	}
+
+	client, closer, err := p.connect()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	listenPort := p.config.ListenPort
+	if listenPort == 0 {
+		listenPort = 443
+	}
+
+	opensslPath := p.config.OpenSSLPath
+	if opensslPath == "" {
+		opensslPath = "openssl"
+	}
+
+	// 写入质询证书并在后台启动应答程序
+	workDir := p.getWorkDir(token)
+	command := strings.Join([]string{
+		"set -e",
+		"umask 077",
+		fmt.Sprintf("mkdir -p %s", shellQuote(workDir)),
+		fmt.Sprintf("cd %s", shellQuote(workDir)),
+		fmt.Sprintf("cat > cert.pem <<'CERTIMATE_EOF'\n%s\nCERTIMATE_EOF", strings.TrimSpace(string(certPEM))),
+		fmt.Sprintf("cat > key.pem <<'CERTIMATE_EOF'\n%s\nCERTIMATE_EOF", strings.TrimSpace(string(keyPEM))),
+		fmt.Sprintf("nohup %s s_server -accept %d -cert cert.pem -key key.pem -alpn %s -www < /dev/null > s_server.log 2>&1 &", shellQuote(opensslPath), listenPort, tlsalpn01.ACMETLS1Protocol),
+		"echo $! > s_server.pid",
+		"sleep 1",
+		"if ! kill -0 \"$(cat s_server.pid)\" 2> /dev/null; then cat s_server.log >&2; exit 1; fi",
+	}, "\n")
+	if stdout, stderr, err := execSshCommand(client, command); err != nil {
+		return fmt.Errorf("failed to start responder for TLS-ALPN challenge: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
+	}
+
+	return nil
+}
+
+func (p *provider) CleanUp(domain, token, keyAuth string) error {
+	client, closer, err := p.connect()
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	// 停止应答程序并删除质询证书
+	workDir := p.getWorkDir(token)
+	command := strings.Join([]string{
+		fmt.Sprintf("if [ -f %s ]; then kill \"$(cat %s)\" 2> /dev/null || true; fi", shellQuote(path.Join(workDir, "s_server.pid")), shellQuote(path.Join(workDir, "s_server.pid"))),
+		fmt.Sprintf("rm -rf %s", shellQuote(workDir)),
+	}, "\n")
+	if stdout, stderr, err := execSshCommand(client, command); err != nil {
+		return fmt.Errorf("failed to stop responder for TLS-ALPN challenge: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
+	}
+
+	return nil
+}
+
+func (p *provider) getWorkDir(token string) string {
+	workDir := p.config.WorkDir
+	if workDir == "" {
+		workDir = "/tmp"
+	}
+
+	return path.Join(workDir, "certimate-tlsalpn01-"+token)
+}
+
+func (p *provider) connect() (*ssh.Client, func(), error) {
+	closers := make([]func() error, 0)
+	closer := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	// 创建 TCP 链接
+	var targetConn net.Conn
+	if len(p.config.JumpServers) > 0 {
+		var jumpClient *ssh.Client
+		for i, jumpServerConf := range p.config.JumpServers {
+			var jumpConn net.Conn
+			var err error
+			// 第一个连接是主机发起，后续通过跳板机发起
+			if jumpClient == nil {
+				jumpConn, err = net.Dial("tcp", net.JoinHostPort(jumpServerConf.SshHost, strconv.Itoa(int(jumpServerConf.SshPort))))
+			} else {
+				jumpConn, err = jumpClient.Dial("tcp", net.JoinHostPort(jumpServerConf.SshHost, strconv.Itoa(int(jumpServerConf.SshPort))))
+			}
+			if err != nil {
+				closer()
+				return nil, nil, fmt.Errorf("failed to connect to jump server [%d]: %w", i+1, err)
+			}
+			closers = append(closers, jumpConn.Close)
+
+			newClient, err := p.createSshClient(
+				jumpConn,
+				jumpServerConf.SshHost,
+				jumpServerConf.SshPort,
+				jumpServerConf.SshAuthMethod,
+				jumpServerConf.SshUsername,
+				jumpServerConf.SshPassword,
+				jumpServerConf.SshKey,
+				jumpServerConf.SshKeyPassphrase,
+			)
+			if err != nil {
+				closer()
+				return nil, nil, fmt.Errorf("failed to create jump server ssh client[%d]: %w", i+1, err)
+			}
+			closers = append(closers, newClient.Close)
+
+			jumpClient = newClient
+		}
+
+		// 通过跳板机发起 TCP 连接到目标服务器
+		conn, err := jumpClient.Dial("tcp", net.JoinHostPort(p.config.SshHost, strconv.Itoa(int(p.config.SshPort))))
+		if err != nil {
+			closer()
+			return nil, nil, fmt.Errorf("failed to connect to target server: %w", err)
+		}
+		targetConn = conn
+	} else {
+		// 直接发起 TCP 连接到目标服务器
+		conn, err := net.Dial("tcp", net.JoinHostPort(p.config.SshHost, strconv.Itoa(int(p.config.SshPort))))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to target server: %w", err)
+		}
+		targetConn = conn
+	}
+	closers = append(closers, targetConn.Close)
+
+	// 创建 SSH 客户端
+	client, err := p.createSshClient(
+		targetConn,
+		p.config.SshHost,
+		p.config.SshPort,
+		p.config.SshAuthMethod,
+		p.config.SshUsername,
+		p.config.SshPassword,
+		p.config.SshKey,
+		p.config.SshKeyPassphrase,
+	)
+	if err != nil {
+		closer()
+		return nil, nil, fmt.Errorf("failed to create ssh client: %w", err)
+	}
+	closers = append(closers, client.Close)
+
+	return client, closer, nil
+}
+
+func (p *provider) createSshClient(conn net.Conn, host string, port int32, authMethod string, username, password, key, keyPassphrase string) (*ssh.Client, error) {
+	if host == "" {
+		host = "localhost"
+	}
+
+	if port == 0 {
+		port = 22
+	}
+
+	if username == "" {
+		username = "root"
+	}
+
+	const AUTH_METHOD_NONE = "none"
+	const AUTH_METHOD_PASSWORD = "password"
+	const AUTH_METHOD_KEY = "key"
+	if authMethod == "" {
+		if key != "" {
+			authMethod = AUTH_METHOD_KEY
+		} else if password != "" {
+			authMethod = AUTH_METHOD_PASSWORD
+		} else {
+			authMethod = AUTH_METHOD_NONE
+		}
+	}
+
+	switch authMethod {
+	case AUTH_METHOD_NONE:
+		return xssh.NewClient(conn, host, int(port), username)
+
+	case AUTH_METHOD_PASSWORD:
+		return xssh.NewClientWithPassword(conn, host, int(port), username, password)
+
+	case AUTH_METHOD_KEY:
+		return xssh.NewClientWithKey(conn, host, int(port), username, key, keyPassphrase)
+
+	default:
+		return nil, fmt.Errorf("unsupported auth method '%s'", authMethod)
+	}
+}
+
+func execSshCommand(sshCli *ssh.Client, command string) (string, string, error) {
+	session, err := sshCli.NewSession()
+	if err != nil {
+		return "", "", err
+	}
+	defer session.Close()
+
+	stdoutBuf := bytes.NewBuffer(nil)
+	session.Stdout = stdoutBuf
+	stderrBuf := bytes.NewBuffer(nil)
+	session.Stderr = stderrBuf
+	err = session.Run(command)
+	if err != nil {
+		return stdoutBuf.String(), stderrBuf.String(), fmt.Errorf("failed to execute ssh command: %w", err)
+	}
+
+	return stdoutBuf.String(), stderrBuf.String(), nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
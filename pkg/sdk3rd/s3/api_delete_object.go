@@ -0,0 +1,32 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+)
+
+type DeleteObjectRequest struct {
+	Bucket string
+	Key    string
+}
+
+type DeleteObjectResponse struct{}
+
+func (c *Client) DeleteObject(req *DeleteObjectRequest) (*DeleteObjectResponse, error) {
+	return c.DeleteObjectWithContext(context.Background(), req)
+}
+
+func (c *Client) DeleteObjectWithContext(ctx context.Context, req *DeleteObjectRequest) (*DeleteObjectResponse, error) {
+	httpreq, err := c.newRequest(http.MethodDelete, req.Bucket, req.Key)
+	if err != nil {
+		return nil, err
+	} else {
+		httpreq.SetContext(ctx)
+	}
+
+	if _, err := c.doRequest(httpreq); err != nil {
+		return nil, err
+	}
+
+	return &DeleteObjectResponse{}, nil
+}
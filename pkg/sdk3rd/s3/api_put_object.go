@@ -0,0 +1,48 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+)
+
+type PutObjectRequest struct {
+	Bucket      string
+	Key         string
+	Body        []byte
+	ContentType string
+	ACL         string
+}
+
+type PutObjectResponse struct {
+	ETag string
+}
+
+func (c *Client) PutObject(req *PutObjectRequest) (*PutObjectResponse, error) {
+	return c.PutObjectWithContext(context.Background(), req)
+}
+
+func (c *Client) PutObjectWithContext(ctx context.Context, req *PutObjectRequest) (*PutObjectResponse, error) {
+	httpreq, err := c.newRequest(http.MethodPut, req.Bucket, req.Key)
+	if err != nil {
+		return nil, err
+	} else {
+		contentType := req.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		httpreq.SetHeader("Content-Type", contentType)
+		if req.ACL != "" {
+			httpreq.SetHeader("x-amz-acl", req.ACL)
+		}
+		httpreq.SetBody(req.Body)
+		httpreq.SetContext(ctx)
+	}
+
+	httpresp, err := c.doRequest(httpreq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PutObjectResponse{ETag: httpresp.Header().Get("ETag")}, nil
+}
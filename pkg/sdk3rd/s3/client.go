@@ -0,0 +1,162 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/go-resty/resty/v2"
+)
+
+type Client struct {
+	client *resty.Client
+
+	endpoint     *url.URL
+	usePathStyle bool
+}
+
+func NewClient(endpoint, region, accessKeyId, secretAccessKey string, usePathStyle bool) (*Client, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("sdkerr: unset endpoint")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointUrl, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("sdkerr: invalid endpoint: %w", err)
+	} else if endpointUrl.Host == "" {
+		return nil, fmt.Errorf("sdkerr: invalid endpoint: missing host")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("sdkerr: unset region")
+	}
+	if accessKeyId == "" {
+		return nil, fmt.Errorf("sdkerr: unset accessKeyId")
+	}
+	if secretAccessKey == "" {
+		return nil, fmt.Errorf("sdkerr: unset secretAccessKey")
+	}
+
+	credentials := aws.Credentials{
+		AccessKeyID:     accessKeyId,
+		SecretAccessKey: secretAccessKey,
+	}
+
+	client := resty.New().
+		SetHeader("User-Agent", "certimate").
+		SetPreRequestHook(func(c *resty.Client, req *http.Request) error {
+			return signRequest(req, credentials, region, time.Now().UTC())
+		})
+
+	return &Client{
+		client:       client,
+		endpoint:     endpointUrl,
+		usePathStyle: usePathStyle,
+	}, nil
+}
+
+func (c *Client) SetTimeout(timeout time.Duration) *Client {
+	c.client.SetTimeout(timeout)
+	return c
+}
+
+func (c *Client) SetTLSConfig(config *tls.Config) *Client {
+	c.client.SetTLSClientConfig(config)
+	return c
+}
+
+func (c *Client) newRequest(method string, bucket string, key string) (*resty.Request, error) {
+	if method == "" {
+		return nil, fmt.Errorf("sdkerr: unset method")
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("sdkerr: unset bucket")
+	}
+	if key == "" {
+		return nil, fmt.Errorf("sdkerr: unset key")
+	}
+
+	// 路径风格形如 `https://{endpoint}/{bucket}/{key}`，
+	// 虚拟主机风格形如 `https://{bucket}.{endpoint}/{key}`
+	var requestUrl string
+	if c.usePathStyle {
+		requestUrl = fmt.Sprintf("%s://%s%s/%s/%s", c.endpoint.Scheme, c.endpoint.Host, c.endpoint.EscapedPath(), escapePath(bucket), escapePath(key))
+	} else {
+		requestUrl = fmt.Sprintf("%s://%s.%s%s/%s", c.endpoint.Scheme, bucket, c.endpoint.Host, c.endpoint.EscapedPath(), escapePath(key))
+	}
+
+	req := c.client.R()
+	req.Method = method
+	req.URL = requestUrl
+	return req, nil
+}
+
+func (c *Client) doRequest(req *resty.Request) (*resty.Response, error) {
+	if req == nil {
+		return nil, fmt.Errorf("sdkerr: nil request")
+	}
+
+	resp, err := req.Send()
+	if err != nil {
+		return resp, fmt.Errorf("sdkerr: failed to send request: %w", err)
+	} else if resp.IsError() {
+		return resp, fmt.Errorf("sdkerr: unexpected status code: %d, resp: %s", resp.StatusCode(), resp.String())
+	}
+
+	return resp, nil
+}
+
+// 以 AWS Signature Version 4 签名请求。
+func signRequest(req *http.Request, credentials aws.Credentials, region string, signTime time.Time) error {
+	payload := make([]byte, 0)
+	if req.Body != nil && req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+
+		defer reader.Close()
+
+		payloadb, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		payload = payloadb
+	}
+
+	payloadHash := sha256.Sum256(payload)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+
+	signer := v4.NewSigner(func(o *v4.SignerOptions) {
+		// S3 的规范 URI 只编码一次
+		o.DisableURIPathEscaping = true
+	})
+	return signer.SignHTTP(req.Context(), credentials, req, payloadHashHex, "s3", region, signTime)
+}
+
+// 按 S3 的规则编码对象键，除未保留字符和路径分隔符外均需百分号编码。
+func escapePath(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || ch == '/' {
+			buf.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", ch)
+		}
+	}
+	return buf.String()
+}
@@ -3,6 +3,7 @@ import { json } from "@codemirror/lang-json";
 import { yaml } from "@codemirror/lang-yaml";
 import { StreamLanguage } from "@codemirror/language";
 import { powerShell } from "@codemirror/legacy-modes/mode/powershell";
+import { python } from "@codemirror/legacy-modes/mode/python";
 import { shell } from "@codemirror/legacy-modes/mode/shell";
 import { basicSetup } from "@uiw/codemirror-extensions-basic-setup";
 import { vscodeDark, vscodeLight } from "@uiw/codemirror-theme-vscode";
@@ -61,6 +62,9 @@ const CodeInput = ({ className, style, disabled, language, readOnly, ...props }:
         case "powershell":
           temp.push(StreamLanguage.define(powerShell));
           break;
+        case "python":
+          temp.push(StreamLanguage.define(python));
+          break;
         case "yaml":
           temp.push(yaml());
           break;
@@ -0,0 +1,132 @@
+import { useEffect, useRef, useState } from "react";
+import { useTranslation } from "react-i18next";
+import { IconCircleMinus, IconCirclePlus } from "@tabler/icons-react";
+import { useControllableValue } from "ahooks";
+import { Button, Input } from "antd";
+import { produce } from "immer";
+import { isEqual } from "radash";
+
+export interface KeyValueInputProps {
+  className?: string;
+  style?: React.CSSProperties;
+  defaultValue?: Record<string, string>;
+  disabled?: boolean;
+  keyPlaceholder?: string;
+  valuePlaceholder?: string;
+  value?: Record<string, string>;
+  onChange?: (value: Record<string, string>) => void;
+}
+
+type KeyValueEntry = { key: string; value: string };
+
+const toEntries = (value?: Record<string, string>): KeyValueEntry[] => {
+  return Object.entries(value ?? {}).map(([key, value]) => ({ key, value }));
+};
+
+const toRecord = (entries: KeyValueEntry[]): Record<string, string> => {
+  // 键为空的条目视为尚在编辑中，不计入结果
+  return entries.reduce(
+    (acc, { key, value }) => {
+      if (key.trim()) {
+        acc[key.trim()] = value;
+      }
+      return acc;
+    },
+    {} as Record<string, string>
+  );
+};
+
+const KeyValueInput = ({ className, style, disabled, keyPlaceholder, valuePlaceholder, ...props }: KeyValueInputProps) => {
+  const { t } = useTranslation();
+
+  const [value, setValue] = useControllableValue<Record<string, string>>(props, {
+    valuePropName: "value",
+    defaultValue: {},
+    defaultValuePropName: "defaultValue",
+    trigger: "onChange",
+  });
+
+  const [entries, setEntries] = useState<KeyValueEntry[]>(() => toEntries(value));
+  const lastEmittedRef = useRef<Record<string, string>>(toRecord(entries));
+  useEffect(() => {
+    // 仅在外部值发生变化时同步，避免覆盖编辑中的条目
+    if (!isEqual(value ?? {}, lastEmittedRef.current)) {
+      const newEntries = toEntries(value);
+      lastEmittedRef.current = toRecord(newEntries);
+      setEntries(newEntries);
+    }
+  }, [value]);
+
+  const updateEntries = (newEntries: KeyValueEntry[]) => {
+    setEntries(newEntries);
+
+    const newValue = toRecord(newEntries);
+    lastEmittedRef.current = newValue;
+    setValue(newValue);
+  };
+
+  const handleCreate = (index: number) => {
+    updateEntries(
+      produce(entries, (draft) => {
+        draft.splice(index, 0, { key: "", value: "" });
+      })
+    );
+  };
+
+  const handleChange = (index: number, entry: Partial<KeyValueEntry>) => {
+    updateEntries(
+      produce(entries, (draft) => {
+        draft[index] = { ...draft[index], ...entry };
+      })
+    );
+  };
+
+  const handleRemove = (index: number) => {
+    updateEntries(
+      produce(entries, (draft) => {
+        draft.splice(index, 1);
+      })
+    );
+  };
+
+  return (
+    <div className={className} style={style}>
+      {entries.length === 0 ? (
+        <Button block color="primary" disabled={disabled} variant="dashed" onClick={() => handleCreate(0)}>
+          {t("common.button.add")}
+        </Button>
+      ) : (
+        <div className="flex flex-col gap-2">
+          {entries.map((entry, index) => (
+            <div key={index} className="flex items-center gap-2">
+              <Input
+                className="w-2/5"
+                disabled={disabled}
+                placeholder={keyPlaceholder}
+                value={entry.key}
+                onChange={(e) => handleChange(index, { key: e.target.value })}
+              />
+              <Input
+                className="flex-1"
+                disabled={disabled}
+                placeholder={valuePlaceholder}
+                value={entry.value}
+                onChange={(e) => handleChange(index, { value: e.target.value })}
+              />
+              <Button
+                color="primary"
+                disabled={disabled}
+                icon={<IconCirclePlus size="1.25em" />}
+                type="text"
+                onClick={() => handleCreate(index + 1)}
+              />
+              <Button color="default" disabled={disabled} icon={<IconCircleMinus size="1.25em" />} type="text" onClick={() => handleRemove(index)} />
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default KeyValueInput;
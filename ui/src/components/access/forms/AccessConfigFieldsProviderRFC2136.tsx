@@ -0,0 +1,115 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Select } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const TSIG_ALGORITHMS = ["hmac-md5", "hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512"] as const;
+
+const AccessConfigFormFieldsProviderRFC2136 = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "nameserver"]}
+        initialValue={initialValues.nameserver}
+        label={t("access.form.rfc2136_nameserver.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("access.form.rfc2136_nameserver.tooltip") }}></span>}
+      >
+        <Input placeholder={t("access.form.rfc2136_nameserver.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "tsigAlgorithm"]}
+        initialValue={initialValues.tsigAlgorithm}
+        label={t("access.form.rfc2136_tsig_algorithm.label")}
+        rules={[formRule]}
+      >
+        <Select
+          options={TSIG_ALGORITHMS.map((s) => ({
+            key: s,
+            label: s,
+            value: s,
+          }))}
+          placeholder={t("access.form.rfc2136_tsig_algorithm.placeholder")}
+        />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "tsigKey"]}
+        initialValue={initialValues.tsigKey}
+        label={t("access.form.rfc2136_tsig_key.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("access.form.rfc2136_tsig_key.tooltip") }}></span>}
+      >
+        <Input autoComplete="new-password" placeholder={t("access.form.rfc2136_tsig_key.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "tsigSecret"]}
+        initialValue={initialValues.tsigSecret}
+        label={t("access.form.rfc2136_tsig_secret.label")}
+        rules={[formRule]}
+      >
+        <Input.Password autoComplete="new-password" placeholder={t("access.form.rfc2136_tsig_secret.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    nameserver: "",
+    tsigAlgorithm: "hmac-sha256",
+    tsigKey: "",
+    tsigSecret: "",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z
+    .object({
+      nameserver: z
+        .string()
+        .min(1, t("access.form.rfc2136_nameserver.placeholder"))
+        .max(256, t("common.errmsg.string_max", { max: 256 })),
+      tsigAlgorithm: z.enum(TSIG_ALGORITHMS, t("access.form.rfc2136_tsig_algorithm.placeholder")).nullish(),
+      tsigKey: z
+        .string()
+        .max(256, t("common.errmsg.string_max", { max: 256 }))
+        .nullish(),
+      tsigSecret: z
+        .string()
+        .max(256, t("common.errmsg.string_max", { max: 256 }))
+        .nullish(),
+    })
+    .superRefine((values, ctx) => {
+      // 密钥名称与密钥须同时填写或同时留空
+      if (!!values.tsigKey !== !!values.tsigSecret) {
+        ctx.addIssue({
+          code: "custom",
+          message: t("access.form.rfc2136_tsig_secret.errmsg.pair_required"),
+          path: [values.tsigKey ? "tsigSecret" : "tsigKey"],
+        });
+      }
+    });
+};
+
+const _default = Object.assign(AccessConfigFormFieldsProviderRFC2136, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,119 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Switch } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const AccessConfigFormFieldsProviderS3 = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "endpoint"]}
+        initialValue={initialValues.endpoint}
+        label={t("access.form.s3_endpoint.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("access.form.s3_endpoint.tooltip") }}></span>}
+      >
+        <Input placeholder={t("access.form.s3_endpoint.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "region"]}
+        initialValue={initialValues.region}
+        label={t("access.form.s3_region.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("access.form.s3_region.tooltip") }}></span>}
+      >
+        <Input placeholder={t("access.form.s3_region.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "accessKeyId"]}
+        initialValue={initialValues.accessKeyId}
+        label={t("access.form.s3_access_key_id.label")}
+        rules={[formRule]}
+      >
+        <Input autoComplete="new-password" placeholder={t("access.form.s3_access_key_id.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "secretAccessKey"]}
+        initialValue={initialValues.secretAccessKey}
+        label={t("access.form.s3_secret_access_key.label")}
+        rules={[formRule]}
+      >
+        <Input.Password autoComplete="new-password" placeholder={t("access.form.s3_secret_access_key.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "usePathStyle"]}
+        initialValue={initialValues.usePathStyle}
+        label={t("access.form.s3_use_path_style.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("access.form.s3_use_path_style.tooltip") }}></span>}
+      >
+        <Switch />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "allowInsecureConnections"]}
+        initialValue={initialValues.allowInsecureConnections}
+        label={t("access.form.shared_allow_insecure_conns.label")}
+        rules={[formRule]}
+      >
+        <Switch
+          checkedChildren={t("access.form.shared_allow_insecure_conns.switch.on")}
+          unCheckedChildren={t("access.form.shared_allow_insecure_conns.switch.off")}
+        />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    endpoint: "",
+    region: "us-east-1",
+    accessKeyId: "",
+    secretAccessKey: "",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    endpoint: z.string().min(1, t("access.form.s3_endpoint.placeholder")),
+    region: z
+      .string()
+      .min(1, t("access.form.s3_region.placeholder"))
+      .max(64, t("common.errmsg.string_max", { max: 64 })),
+    accessKeyId: z
+      .string()
+      .min(1, t("access.form.s3_access_key_id.placeholder"))
+      .max(128, t("common.errmsg.string_max", { max: 128 })),
+    secretAccessKey: z
+      .string()
+      .min(1, t("access.form.s3_secret_access_key.placeholder"))
+      .max(128, t("common.errmsg.string_max", { max: 128 })),
+    usePathStyle: z.boolean().nullish(),
+    allowInsecureConnections: z.boolean().nullish(),
+  });
+};
+
+const _default = Object.assign(AccessConfigFormFieldsProviderS3, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,77 @@
+import { useState } from "react";
+import { useRequest } from "ahooks";
+import { Select, type SelectProps, Typography, theme } from "antd";
+import dayjs from "dayjs";
+
+import { type PrivateCAModel } from "@/domain/privateCA";
+import { list as listPrivateCAs } from "@/repository/privateCA";
+
+export interface PrivateCASelectProps
+  extends Omit<SelectProps, "filterOption" | "filterSort" | "labelRender" | "loading" | "options" | "optionFilterProp" | "optionLabelProp" | "optionRender"> {
+  onFilter?: (value: string, option: PrivateCAModel) => boolean;
+}
+
+const PrivateCASelect = ({ onFilter, ...props }: PrivateCASelectProps) => {
+  const { token: themeToken } = theme.useToken();
+
+  const [privateCAs, setPrivateCAs] = useState<PrivateCAModel[]>([]);
+  const { loading } = useRequest(
+    () => {
+      return listPrivateCAs();
+    },
+    {
+      onSuccess: (res) => {
+        setPrivateCAs(res.items);
+      },
+    }
+  );
+
+  const options = (onFilter != null ? privateCAs.filter((item) => onFilter(item.id, item)) : privateCAs).map((item) => ({
+    key: item.id,
+    value: item.id,
+    label: item.name || item.commonName,
+    data: item,
+  }));
+
+  const renderOption = (key: string) => {
+    const privateCA = privateCAs.find((e) => e.id === key);
+    if (!privateCA) {
+      return <Typography.Text ellipsis>{key}</Typography.Text>;
+    }
+
+    return (
+      <div className="flex items-center justify-between gap-2 truncate overflow-hidden">
+        <Typography.Text ellipsis>{privateCA.name || privateCA.commonName}</Typography.Text>
+        <Typography.Text className="text-xs" type="secondary" ellipsis>
+          {privateCA.keyAlgorithm} · {dayjs(privateCA.validityNotAfter).format("YYYY-MM-DD")}
+        </Typography.Text>
+      </div>
+    );
+  };
+
+  return (
+    <Select
+      {...props}
+      filterOption={(inputValue, option) => {
+        if (!option) return false;
+
+        const value = inputValue.toLowerCase();
+        return option.label.toLowerCase().includes(value) || option.data.commonName.toLowerCase().includes(value);
+      }}
+      labelRender={({ value }) => {
+        if (value != null) {
+          return renderOption(value as string);
+        }
+
+        return <span style={{ color: themeToken.colorTextPlaceholder }}>{props.placeholder}</span>;
+      }}
+      loading={loading}
+      options={options}
+      optionFilterProp="label"
+      optionLabelProp={void 0}
+      optionRender={(option) => renderOption(option.data.value)}
+    />
+  );
+};
+
+export default PrivateCASelect;
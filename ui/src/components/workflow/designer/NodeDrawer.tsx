@@ -5,6 +5,7 @@ import { useControllableValue } from "ahooks";
 import Show from "@/components/Show";
 import { useTriggerElement } from "@/hooks";
 
+import ApprovalNodeConfigDrawer from "./forms/ApprovalNodeConfigDrawer";
 import BizApplyNodeConfigDrawer from "./forms/BizApplyNodeConfigDrawer";
 import BizDeployNodeConfigDrawer from "./forms/BizDeployNodeConfigDrawer";
 import BizMonitorNodeConfigDrawer from "./forms/BizMonitorNodeConfigDrawer";
@@ -12,7 +13,10 @@ import BizNotifyNodeConfigDrawer from "./forms/BizNotifyNodeConfigDrawer";
 import BizUploadNodeConfigDrawer from "./forms/BizUploadNodeConfigDrawer";
 import BranchBlockNodeConfigDrawer from "./forms/BranchBlockNodeConfigDrawer";
 import DelayNodeConfigDrawer from "./forms/DelayNodeConfigDrawer";
+import HttpRequestNodeConfigDrawer from "./forms/HttpRequestNodeConfigDrawer";
+import ScriptNodeConfigDrawer from "./forms/ScriptNodeConfigDrawer";
 import StartNodeConfigDrawer from "./forms/StartNodeConfigDrawer";
+import SubWorkflowNodeConfigDrawer from "./forms/SubWorkflowNodeConfigDrawer";
 import { NodeType } from "./nodes/typings";
 
 export interface NodeDrawerProps {
@@ -57,6 +61,15 @@ const NodeDrawer = ({ node, trigger, ...props }: NodeDrawerProps) => {
         <Show.Case when={node?.flowNodeType === NodeType.Delay}>
           <DelayNodeConfigDrawer {...drawerProps} />
         </Show.Case>
+        <Show.Case when={node?.flowNodeType === NodeType.Script}>
+          <ScriptNodeConfigDrawer {...drawerProps} />
+        </Show.Case>
+        <Show.Case when={node?.flowNodeType === NodeType.HttpRequest}>
+          <HttpRequestNodeConfigDrawer {...drawerProps} />
+        </Show.Case>
+        <Show.Case when={node?.flowNodeType === NodeType.Approval}>
+          <ApprovalNodeConfigDrawer {...drawerProps} />
+        </Show.Case>
         <Show.Case when={node?.flowNodeType === NodeType.BranchBlock}>
           <BranchBlockNodeConfigDrawer {...drawerProps} />
         </Show.Case>
@@ -75,6 +88,9 @@ const NodeDrawer = ({ node, trigger, ...props }: NodeDrawerProps) => {
         <Show.Case when={node?.flowNodeType === NodeType.BizNotify}>
           <BizNotifyNodeConfigDrawer {...drawerProps} />
         </Show.Case>
+        <Show.Case when={node?.flowNodeType === NodeType.SubWorkflow}>
+          <SubWorkflowNodeConfigDrawer {...drawerProps} />
+        </Show.Case>
         <Show.Default>
           <></>
         </Show.Default>
@@ -0,0 +1,40 @@
+import { useTranslation } from "react-i18next";
+import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
+import { Form } from "antd";
+
+import { NodeConfigDrawer } from "./_shared";
+import ApprovalNodeConfigForm from "./ApprovalNodeConfigForm";
+import { NodeType } from "../nodes/typings";
+
+export interface ApprovalNodeConfigDrawerProps {
+  afterClose?: () => void;
+  loading?: boolean;
+  node: FlowNodeEntity;
+  open?: boolean;
+  onOpenChange?: (open: boolean) => void;
+}
+
+const ApprovalNodeConfigDrawer = ({ node, ...props }: ApprovalNodeConfigDrawerProps) => {
+  if (node.flowNodeType !== NodeType.Approval) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.Approval}`);
+  }
+
+  const { i18n } = useTranslation();
+
+  const [formInst] = Form.useForm();
+
+  return (
+    <NodeConfigDrawer
+      anchor={{
+        items: ApprovalNodeConfigForm.getAnchorItems({ i18n }),
+      }}
+      form={formInst}
+      node={node}
+      {...props}
+    >
+      <ApprovalNodeConfigForm form={formInst} node={node} />
+    </NodeConfigDrawer>
+  );
+};
+
+export default ApprovalNodeConfigDrawer;
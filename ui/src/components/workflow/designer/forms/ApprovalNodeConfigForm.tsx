@@ -0,0 +1,260 @@
+import { useEffect, useMemo } from "react";
+import { getI18n, useTranslation } from "react-i18next";
+import { type FlowNodeEntity, getNodeForm } from "@flowgram.ai/fixed-layout-editor";
+import { IconPlus } from "@tabler/icons-react";
+import { type AnchorProps, Button, Divider, Form, type FormInstance, Input, InputNumber, Typography } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import AccessEditDrawer from "@/components/access/AccessEditDrawer";
+import AccessSelect from "@/components/access/AccessSelect";
+import NotificationProviderSelect from "@/components/provider/NotificationProviderSelect";
+import Tips from "@/components/Tips";
+import { type AccessModel } from "@/domain/access";
+import { NOTIFICATION_PROVIDERS, notificationProvidersMap } from "@/domain/provider";
+import { type WorkflowNodeConfigForApproval, defaultNodeConfigForApproval } from "@/domain/workflow";
+import { useAntdForm, useZustandShallowSelector } from "@/hooks";
+import { useAccessesStore } from "@/stores/access";
+
+import { FormNestedFieldsContextProvider, NodeFormContextProvider } from "./_context";
+import BizNotifyNodeConfigFieldsProviderDiscordBot from "./BizNotifyNodeConfigFieldsProviderDiscordBot";
+import BizNotifyNodeConfigFieldsProviderEmail from "./BizNotifyNodeConfigFieldsProviderEmail";
+import BizNotifyNodeConfigFieldsProviderMattermost from "./BizNotifyNodeConfigFieldsProviderMattermost";
+import BizNotifyNodeConfigFieldsProviderSlackBot from "./BizNotifyNodeConfigFieldsProviderSlackBot";
+import BizNotifyNodeConfigFieldsProviderTelegramBot from "./BizNotifyNodeConfigFieldsProviderTelegramBot";
+import BizNotifyNodeConfigFieldsProviderWebhook from "./BizNotifyNodeConfigFieldsProviderWebhook";
+import { NodeType } from "../nodes/typings";
+
+export interface ApprovalNodeConfigFormProps {
+  form: FormInstance;
+  node: FlowNodeEntity;
+}
+
+const ApprovalNodeConfigForm = ({ node, ...props }: ApprovalNodeConfigFormProps) => {
+  if (node.flowNodeType !== NodeType.Approval) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.Approval}`);
+  }
+
+  const { i18n, t } = useTranslation();
+
+  const { accesses } = useAccessesStore(useZustandShallowSelector("accesses"));
+  const accessOptionFilter = (_: string, option: AccessModel) => {
+    if (option.reserve !== "notif") return false;
+    return notificationProvidersMap.get(fieldProvider)?.provider === option.provider;
+  };
+
+  const initialValues = useMemo(() => {
+    return getNodeForm(node)?.getValueIn("config") as WorkflowNodeConfigForApproval | undefined;
+  }, [node]);
+
+  const formSchema = getSchema({ i18n });
+  const formRule = createSchemaFieldRule(formSchema);
+  const { form: formInst, formProps } = useAntdForm<z.infer<typeof formSchema>>({
+    form: props.form,
+    name: "workflowNodeApprovalConfigForm",
+    initialValues: initialValues ?? getInitialValues(),
+  });
+
+  const fieldProvider = Form.useWatch<string>("provider", { form: formInst, preserve: true });
+  const fieldProviderAccessId = Form.useWatch<string>("providerAccessId", { form: formInst, preserve: true });
+
+  const NestedProviderConfigFields = useMemo(() => {
+    /*
+      注意：如果追加新的子组件，请保持以 ASCII 排序。
+      NOTICE: If you add new child component, please keep ASCII order.
+      */
+    switch (fieldProvider) {
+      case NOTIFICATION_PROVIDERS.DISCORDBOT: {
+        return BizNotifyNodeConfigFieldsProviderDiscordBot;
+      }
+      case NOTIFICATION_PROVIDERS.EMAIL: {
+        return BizNotifyNodeConfigFieldsProviderEmail;
+      }
+      case NOTIFICATION_PROVIDERS.MATTERMOST: {
+        return BizNotifyNodeConfigFieldsProviderMattermost;
+      }
+      case NOTIFICATION_PROVIDERS.SLACKBOT: {
+        return BizNotifyNodeConfigFieldsProviderSlackBot;
+      }
+      case NOTIFICATION_PROVIDERS.TELEGRAMBOT: {
+        return BizNotifyNodeConfigFieldsProviderTelegramBot;
+      }
+      case NOTIFICATION_PROVIDERS.WEBHOOK: {
+        return BizNotifyNodeConfigFieldsProviderWebhook;
+      }
+    }
+  }, [fieldProvider]);
+
+  useEffect(() => {
+    // 如果未选择通知渠道，则清空授权信息
+    if (!fieldProvider && fieldProviderAccessId) {
+      formInst.setFieldValue("providerAccessId", void 0);
+      return;
+    }
+
+    // 如果已选择通知渠道只有一个授权信息，则自动选择该授权信息
+    if (fieldProvider && !fieldProviderAccessId) {
+      const availableAccesses = accesses.filter((access) => accessOptionFilter(access.provider, access));
+      if (availableAccesses.length === 1) {
+        formInst.setFieldValue("providerAccessId", availableAccesses[0].id);
+      }
+    }
+  }, [fieldProvider, fieldProviderAccessId]);
+
+  const handleProviderSelect = (value?: string | undefined) => {
+    // 切换通知渠道时重置表单，避免其他通知渠道的配置字段影响当前通知渠道
+    if (initialValues?.provider === value) {
+      formInst.setFieldValue("providerAccessId", void 0);
+      formInst.resetFields(["providerConfig"]);
+    } else {
+      formInst.setFieldValue("providerAccessId", void 0);
+      formInst.setFieldValue("providerConfig", void 0);
+    }
+  };
+
+  return (
+    <NodeFormContextProvider value={{ node }}>
+      <Form {...formProps} clearOnDestroy={true} form={formInst} layout="vertical" preserve={false} scrollToFirstError>
+        <div id="parameters" data-anchor="parameters">
+          <Form.Item
+            name="waitTimeout"
+            label={t("workflow_node.approval.form.wait_timeout.label")}
+            extra={t("workflow_node.approval.form.wait_timeout.help")}
+            rules={[formRule]}
+          >
+            <InputNumber
+              style={{ width: "100%" }}
+              min={0}
+              placeholder={t("workflow_node.approval.form.wait_timeout.placeholder")}
+              addonAfter={t("workflow_node.approval.form.wait_timeout.unit")}
+            />
+          </Form.Item>
+        </div>
+
+        <div id="channel" data-anchor="channel">
+          <Divider size="small">
+            <Typography.Text className="text-xs font-normal" type="secondary">
+              {t("workflow_node.approval.form_anchor.channel.title")}
+            </Typography.Text>
+          </Divider>
+
+          <Form.Item
+            name="provider"
+            label={t("workflow_node.approval.form.provider.label")}
+            extra={t("workflow_node.approval.form.provider.help")}
+            rules={[formRule]}
+          >
+            <NotificationProviderSelect
+              allowClear
+              placeholder={t("workflow_node.approval.form.provider.placeholder")}
+              showAvailability
+              showSearch
+              onSelect={handleProviderSelect}
+              onClear={handleProviderSelect}
+            />
+          </Form.Item>
+
+          <Form.Item label={t("workflow_node.approval.form.provider_access.label")} hidden={!fieldProvider}>
+            <div className="absolute -top-[6px] right-0 -translate-y-full">
+              <AccessEditDrawer
+                data={{ provider: notificationProvidersMap.get(fieldProvider!)?.provider }}
+                mode="create"
+                trigger={
+                  <Button size="small" type="link">
+                    {t("workflow_node.approval.form.provider_access.button")}
+                    <IconPlus size="1.25em" />
+                  </Button>
+                }
+                usage="notification"
+                afterSubmit={(record) => {
+                  if (!accessOptionFilter(record.provider, record)) return;
+                  formInst.setFieldValue("providerAccessId", record.id);
+                }}
+              />
+            </div>
+            <Form.Item name="providerAccessId" dependencies={["provider"]} noStyle rules={[formRule]}>
+              <AccessSelect
+                disabled={!fieldProvider}
+                placeholder={t("workflow_node.approval.form.provider_access.placeholder")}
+                showSearch
+                onFilter={accessOptionFilter}
+              />
+            </Form.Item>
+          </Form.Item>
+
+          <FormNestedFieldsContextProvider value={{ parentNamePath: "providerConfig" }}>
+            {NestedProviderConfigFields && <NestedProviderConfigFields />}
+          </FormNestedFieldsContextProvider>
+
+          <Form.Item name="subject" label={t("workflow_node.approval.form.subject.label")} hidden={!fieldProvider} rules={[formRule]}>
+            <Input placeholder={t("workflow_node.approval.form.subject.placeholder")} />
+          </Form.Item>
+
+          <Form.Item name="message" label={t("workflow_node.approval.form.message.label")} hidden={!fieldProvider} rules={[formRule]}>
+            <Input.TextArea autoSize={{ minRows: 3, maxRows: 10 }} placeholder={t("workflow_node.approval.form.message.placeholder")} />
+          </Form.Item>
+
+          <Form.Item hidden={!fieldProvider}>
+            <Tips message={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.approval.form.template.guide") }}></span>} />
+          </Form.Item>
+        </div>
+      </Form>
+    </NodeFormContextProvider>
+  );
+};
+
+const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
+  const { t } = i18n;
+
+  return ["parameters", "channel"].map((key) => ({
+    key: key,
+    title: t(`workflow_node.approval.form_anchor.${key}.tab`),
+    href: "#" + key,
+  }));
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    ...defaultNodeConfigForApproval(),
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z
+    .object({
+      waitTimeout: z.preprocess(
+        (v) => (v == null || v === "" ? void 0 : Number(v)),
+        z.number().int(t("workflow_node.approval.form.wait_timeout.placeholder")).gte(0, t("workflow_node.approval.form.wait_timeout.placeholder")).nullish()
+      ),
+      provider: z.string().nullish(),
+      providerAccessId: z.string().nullish(),
+      providerConfig: z.any().nullish(),
+      subject: z
+        .string()
+        .max(20480, t("common.errmsg.string_max", { max: 20480 }))
+        .nullish(),
+      message: z
+        .string()
+        .max(20480, t("common.errmsg.string_max", { max: 20480 }))
+        .nullish(),
+    })
+    .superRefine((values, ctx) => {
+      // 通知渠道可不指定（此时不会通知审批人），但指定后须选择授权信息
+      if (values.provider && !values.providerAccessId) {
+        ctx.addIssue({
+          code: "custom",
+          message: t("workflow_node.approval.form.provider_access.placeholder"),
+          path: ["providerAccessId"],
+        });
+      }
+    });
+};
+
+const _default = Object.assign(ApprovalNodeConfigForm, {
+  getAnchorItems,
+  getSchema,
+});
+
+export default _default;
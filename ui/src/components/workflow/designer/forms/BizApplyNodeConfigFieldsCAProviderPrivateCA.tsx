@@ -0,0 +1,107 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Select } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import PrivateCASelect from "@/components/privateca/PrivateCASelect";
+import { PRIVATE_CA_PROFILES } from "@/domain/privateCA";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const USAGES_SEPARATOR = ";";
+
+const KEY_USAGES = ["digitalSignature", "contentCommitment", "keyEncipherment", "dataEncipherment", "keyAgreement"];
+const EXT_KEY_USAGES = ["any", "serverAuth", "clientAuth", "codeSigning", "emailProtection", "timeStamping", "ocspSigning"];
+
+const BizApplyNodeConfigFieldsCAProviderPrivateCA = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "privateCaId"]}
+        initialValue={initialValues.privateCaId}
+        label={t("workflow_node.apply.form.privateca_private_ca.label")}
+        rules={[formRule]}
+      >
+        <PrivateCASelect placeholder={t("workflow_node.apply.form.privateca_private_ca.placeholder")} showSearch />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "profile"]}
+        initialValue={initialValues.profile}
+        label={t("workflow_node.apply.form.privateca_profile.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.privateca_profile.tooltip") }}></span>}
+      >
+        <Select
+          options={Object.values(PRIVATE_CA_PROFILES).map((e) => ({
+            label: t(`workflow_node.apply.form.privateca_profile.option.${e}.label`),
+            value: e,
+          }))}
+          placeholder={t("workflow_node.apply.form.privateca_profile.placeholder")}
+        />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "keyUsages"]}
+        initialValue={initialValues.keyUsages}
+        label={t("workflow_node.apply.form.privateca_key_usages.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.privateca_key_usages.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.privateca_key_usages.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "extKeyUsages"]}
+        initialValue={initialValues.extKeyUsages}
+        label={t("workflow_node.apply.form.privateca_ext_key_usages.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.privateca_ext_key_usages.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.privateca_ext_key_usages.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    profile: PRIVATE_CA_PROFILES.SERVER,
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  const usagesRefine = (allowed: string[]) => (v: string | null | undefined) => {
+    if (!v) return true;
+    return v
+      .split(USAGES_SEPARATOR)
+      .map((e) => e.trim())
+      .filter((e) => !!e)
+      .every((e) => allowed.includes(e));
+  };
+
+  return z.object({
+    privateCaId: z.string(t("workflow_node.apply.form.privateca_private_ca.placeholder")).nonempty(t("workflow_node.apply.form.privateca_private_ca.placeholder")),
+    profile: z.enum(PRIVATE_CA_PROFILES, t("workflow_node.apply.form.privateca_profile.placeholder")).nullish(),
+    keyUsages: z.string().nullish().refine(usagesRefine(KEY_USAGES), t("workflow_node.apply.form.privateca_key_usages.errmsg.invalid")),
+    extKeyUsages: z.string().nullish().refine(usagesRefine(EXT_KEY_USAGES), t("workflow_node.apply.form.privateca_ext_key_usages.errmsg.invalid")),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsCAProviderPrivateCA, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
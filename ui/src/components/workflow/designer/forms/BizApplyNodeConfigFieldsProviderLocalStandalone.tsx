@@ -0,0 +1,113 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Switch } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import Show from "@/components/Show";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderLocalStandalone = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const formInst = Form.useFormInstance();
+  const initialValues = getInitialValues();
+
+  const fieldForwardMode = Form.useWatch([parentNamePath, "forwardMode"], formInst);
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "listenAddress"]}
+        initialValue={initialValues.listenAddress}
+        label={t("workflow_node.apply.form.local_standalone_listen_address.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_standalone_listen_address.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.local_standalone_listen_address.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "forwardMode"]}
+        initialValue={initialValues.forwardMode}
+        label={t("workflow_node.apply.form.local_standalone_forward_mode.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_standalone_forward_mode.tooltip") }}></span>}
+      >
+        <Switch />
+      </Form.Item>
+
+      <Show when={!!fieldForwardMode}>
+        <Form.Item
+          name={[parentNamePath, "forwardedHeader"]}
+          initialValue={initialValues.forwardedHeader}
+          label={t("workflow_node.apply.form.local_standalone_forwarded_header.label")}
+          rules={[formRule]}
+          tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_standalone_forwarded_header.tooltip") }}></span>}
+        >
+          <Input allowClear placeholder={t("workflow_node.apply.form.local_standalone_forwarded_header.placeholder")} />
+        </Form.Item>
+      </Show>
+
+      <Form.Item
+        name={[parentNamePath, "listenWaitTimeout"]}
+        initialValue={initialValues.listenWaitTimeout}
+        label={t("workflow_node.apply.form.local_standalone_listen_wait_timeout.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_standalone_listen_wait_timeout.tooltip") }}></span>}
+      >
+        <Input
+          type="number"
+          allowClear
+          min={0}
+          max={3600}
+          placeholder={t("workflow_node.apply.form.local_standalone_listen_wait_timeout.placeholder")}
+          addonAfter={t("workflow_node.apply.form.local_standalone_listen_wait_timeout.unit")}
+        />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    listenAddress: ":80",
+    forwardMode: false,
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    listenAddress: z
+      .string()
+      .nullish()
+      .refine((v) => {
+        if (!v) return true;
+        return /^[^\s]*:\d{1,5}$/.test(v);
+      }, t("workflow_node.apply.form.local_standalone_listen_address.placeholder")),
+    forwardMode: z.boolean().nullish(),
+    forwardedHeader: z.string().nullish(),
+    listenWaitTimeout: z.preprocess(
+      (v) => (v == null || v === "" ? void 0 : Number(v)),
+      z
+        .number()
+        .int(t("workflow_node.apply.form.local_standalone_listen_wait_timeout.placeholder"))
+        .gte(0, t("workflow_node.apply.form.local_standalone_listen_wait_timeout.placeholder"))
+        .nullish()
+    ),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderLocalStandalone, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
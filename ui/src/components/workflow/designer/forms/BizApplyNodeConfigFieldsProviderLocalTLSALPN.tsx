@@ -0,0 +1,58 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderLocalTLSALPN = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "listenAddress"]}
+        initialValue={initialValues.listenAddress}
+        label={t("workflow_node.apply.form.local_tlsalpn_listen_address.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_tlsalpn_listen_address.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.local_tlsalpn_listen_address.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    listenAddress: ":443",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    listenAddress: z
+      .string()
+      .nullish()
+      .refine((v) => {
+        if (!v) return true;
+        return /^[^\s]*:\d{1,5}$/.test(v);
+      }, t("workflow_node.apply.form.local_tlsalpn_listen_address.placeholder")),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderLocalTLSALPN, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,114 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Select, Switch } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderQiniuKodo = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "region"]}
+        initialValue={initialValues.region}
+        label={t("workflow_node.apply.form.qiniu_kodo_region.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.qiniu_kodo_region.tooltip") }}></span>}
+      >
+        <Input placeholder={t("workflow_node.apply.form.qiniu_kodo_region.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "endpoint"]}
+        initialValue={initialValues.endpoint}
+        label={t("workflow_node.apply.form.qiniu_kodo_endpoint.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.qiniu_kodo_endpoint.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.qiniu_kodo_endpoint.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "usePathStyle"]}
+        initialValue={initialValues.usePathStyle}
+        label={t("workflow_node.apply.form.qiniu_kodo_use_path_style.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.qiniu_kodo_use_path_style.tooltip") }}></span>}
+      >
+        <Switch />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "bucket"]}
+        initialValue={initialValues.bucket}
+        label={t("workflow_node.apply.form.qiniu_kodo_bucket.label")}
+        rules={[formRule]}
+      >
+        <Input placeholder={t("workflow_node.apply.form.qiniu_kodo_bucket.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "pathPrefix"]}
+        initialValue={initialValues.pathPrefix}
+        label={t("workflow_node.apply.form.qiniu_kodo_path_prefix.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.qiniu_kodo_path_prefix.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.qiniu_kodo_path_prefix.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "acl"]}
+        initialValue={initialValues.acl}
+        label={t("workflow_node.apply.form.qiniu_kodo_acl.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.qiniu_kodo_acl.tooltip") }}></span>}
+      >
+        <Select
+          options={["public-read", "private", "none"].map((s) => ({
+            label: s,
+            value: s,
+          }))}
+          placeholder={t("workflow_node.apply.form.qiniu_kodo_acl.placeholder")}
+        />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    region: "cn-east-1",
+    bucket: "",
+    acl: "public-read",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    region: z.string().nonempty(t("workflow_node.apply.form.qiniu_kodo_region.placeholder")),
+    endpoint: z.string().nullish(),
+    usePathStyle: z.boolean().nullish(),
+    bucket: z.string().nonempty(t("workflow_node.apply.form.qiniu_kodo_bucket.placeholder")),
+    pathPrefix: z.string().nullish(),
+    acl: z.string().nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderQiniuKodo, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
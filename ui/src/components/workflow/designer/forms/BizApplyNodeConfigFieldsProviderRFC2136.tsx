@@ -0,0 +1,67 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderRFC2136 = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "zone"]}
+        initialValue={initialValues.zone}
+        label={t("workflow_node.apply.form.rfc2136_zone.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.rfc2136_zone.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.rfc2136_zone.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "nameserver"]}
+        initialValue={initialValues.nameserver}
+        label={t("workflow_node.apply.form.rfc2136_nameserver.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.rfc2136_nameserver.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.rfc2136_nameserver.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {};
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    zone: z
+      .string()
+      .max(256, t("common.errmsg.string_max", { max: 256 }))
+      .nullish(),
+    nameserver: z
+      .string()
+      .max(256, t("common.errmsg.string_max", { max: 256 }))
+      .nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderRFC2136, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,84 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { validPortNumber } from "@/utils/validators";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderSSHTLSALPN = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "listenPort"]}
+        initialValue={initialValues.listenPort}
+        label={t("workflow_node.apply.form.ssh_tlsalpn_listen_port.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_tlsalpn_listen_port.tooltip") }}></span>}
+      >
+        <Input type="number" allowClear min={1} max={65535} placeholder={t("workflow_node.apply.form.ssh_tlsalpn_listen_port.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "workDir"]}
+        initialValue={initialValues.workDir}
+        label={t("workflow_node.apply.form.ssh_tlsalpn_work_dir.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_tlsalpn_work_dir.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.ssh_tlsalpn_work_dir.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "opensslPath"]}
+        initialValue={initialValues.opensslPath}
+        label={t("workflow_node.apply.form.ssh_tlsalpn_openssl_path.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_tlsalpn_openssl_path.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.ssh_tlsalpn_openssl_path.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    listenPort: 443,
+    workDir: "/tmp",
+    opensslPath: "openssl",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    listenPort: z.preprocess(
+      (v) => (v == null || v === "" ? void 0 : Number(v)),
+      z
+        .number()
+        .nullish()
+        .refine((v) => v == null || validPortNumber(v), t("common.errmsg.port_invalid"))
+    ),
+    workDir: z.string().nullish(),
+    opensslPath: z.string().nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderSSHTLSALPN, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,114 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Select, Switch } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderTencentCloudCOS = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "region"]}
+        initialValue={initialValues.region}
+        label={t("workflow_node.apply.form.tencentcloud_cos_region.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tencentcloud_cos_region.tooltip") }}></span>}
+      >
+        <Input placeholder={t("workflow_node.apply.form.tencentcloud_cos_region.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "endpoint"]}
+        initialValue={initialValues.endpoint}
+        label={t("workflow_node.apply.form.tencentcloud_cos_endpoint.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tencentcloud_cos_endpoint.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.tencentcloud_cos_endpoint.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "usePathStyle"]}
+        initialValue={initialValues.usePathStyle}
+        label={t("workflow_node.apply.form.tencentcloud_cos_use_path_style.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tencentcloud_cos_use_path_style.tooltip") }}></span>}
+      >
+        <Switch />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "bucket"]}
+        initialValue={initialValues.bucket}
+        label={t("workflow_node.apply.form.tencentcloud_cos_bucket.label")}
+        rules={[formRule]}
+      >
+        <Input placeholder={t("workflow_node.apply.form.tencentcloud_cos_bucket.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "pathPrefix"]}
+        initialValue={initialValues.pathPrefix}
+        label={t("workflow_node.apply.form.tencentcloud_cos_path_prefix.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tencentcloud_cos_path_prefix.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.tencentcloud_cos_path_prefix.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "acl"]}
+        initialValue={initialValues.acl}
+        label={t("workflow_node.apply.form.tencentcloud_cos_acl.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tencentcloud_cos_acl.tooltip") }}></span>}
+      >
+        <Select
+          options={["public-read", "private", "none"].map((s) => ({
+            label: s,
+            value: s,
+          }))}
+          placeholder={t("workflow_node.apply.form.tencentcloud_cos_acl.placeholder")}
+        />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    region: "ap-guangzhou",
+    bucket: "",
+    acl: "public-read",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    region: z.string().nonempty(t("workflow_node.apply.form.tencentcloud_cos_region.placeholder")),
+    endpoint: z.string().nullish(),
+    usePathStyle: z.boolean().nullish(),
+    bucket: z.string().nonempty(t("workflow_node.apply.form.tencentcloud_cos_bucket.placeholder")),
+    pathPrefix: z.string().nullish(),
+    acl: z.string().nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderTencentCloudCOS, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
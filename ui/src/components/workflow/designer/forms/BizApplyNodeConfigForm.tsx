@@ -28,10 +28,20 @@ import AccessSelect from "@/components/access/AccessSelect";
 import MultipleSplitValueInput from "@/components/MultipleSplitValueInput";
 import ACMEDns01ProviderSelect from "@/components/provider/ACMEDns01ProviderSelect";
 import ACMEHttp01ProviderSelect from "@/components/provider/ACMEHttp01ProviderSelect";
+import ACMETlsAlpn01ProviderSelect from "@/components/provider/ACMETlsAlpn01ProviderSelect";
 import CAProviderSelect from "@/components/provider/CAProviderSelect";
 import Show from "@/components/Show";
 import { type AccessModel } from "@/domain/access";
-import { ACME_DNS01_PROVIDERS, ACME_HTTP01_PROVIDERS, acmeDns01ProvidersMap, acmeHttp01ProvidersMap, caProvidersMap } from "@/domain/provider";
+import {
+  ACME_DNS01_PROVIDERS,
+  ACME_HTTP01_PROVIDERS,
+  ACME_TLSALPN01_PROVIDERS,
+  CA_PROVIDERS,
+  acmeDns01ProvidersMap,
+  acmeHttp01ProvidersMap,
+  acmeTlsAlpn01ProvidersMap,
+  caProvidersMap,
+} from "@/domain/provider";
 import { type WorkflowNodeConfigForBizApply, defaultNodeConfigForBizApply } from "@/domain/workflow";
 import { useAntdForm, useZustandShallowSelector } from "@/hooks";
 import { useAccessesStore } from "@/stores/access";
@@ -39,12 +49,22 @@ import { useContactEmailsStore } from "@/stores/contact";
 import { validDomainName, validIPv4Address, validIPv6Address } from "@/utils/validators";
 
 import { FormNestedFieldsContextProvider, NodeFormContextProvider } from "./_context";
+import BizApplyNodeConfigFieldsCAProviderPrivateCA from "./BizApplyNodeConfigFieldsCAProviderPrivateCA";
 import BizApplyNodeConfigFieldsProviderAliyunESA from "./BizApplyNodeConfigFieldsProviderAliyunESA";
+import BizApplyNodeConfigFieldsProviderAliyunOSS from "./BizApplyNodeConfigFieldsProviderAliyunOSS";
 import BizApplyNodeConfigFieldsProviderAWSRoute53 from "./BizApplyNodeConfigFieldsProviderAWSRoute53";
+import BizApplyNodeConfigFieldsProviderAWSS3 from "./BizApplyNodeConfigFieldsProviderAWSS3";
 import BizApplyNodeConfigFieldsProviderHuaweiCloudDNS from "./BizApplyNodeConfigFieldsProviderHuaweiCloudDNS";
 import BizApplyNodeConfigFieldsProviderJDCloudDNS from "./BizApplyNodeConfigFieldsProviderJDCloudDNS";
 import BizApplyNodeConfigFieldsProviderLocal from "./BizApplyNodeConfigFieldsProviderLocal";
+import BizApplyNodeConfigFieldsProviderLocalStandalone from "./BizApplyNodeConfigFieldsProviderLocalStandalone";
+import BizApplyNodeConfigFieldsProviderLocalTLSALPN from "./BizApplyNodeConfigFieldsProviderLocalTLSALPN";
+import BizApplyNodeConfigFieldsProviderQiniuKodo from "./BizApplyNodeConfigFieldsProviderQiniuKodo";
+import BizApplyNodeConfigFieldsProviderRFC2136 from "./BizApplyNodeConfigFieldsProviderRFC2136";
+import BizApplyNodeConfigFieldsProviderS3 from "./BizApplyNodeConfigFieldsProviderS3";
 import BizApplyNodeConfigFieldsProviderSSH from "./BizApplyNodeConfigFieldsProviderSSH";
+import BizApplyNodeConfigFieldsProviderSSHTLSALPN from "./BizApplyNodeConfigFieldsProviderSSHTLSALPN";
+import BizApplyNodeConfigFieldsProviderTencentCloudCOS from "./BizApplyNodeConfigFieldsProviderTencentCloudCOS";
 import BizApplyNodeConfigFieldsProviderTencentCloudEO from "./BizApplyNodeConfigFieldsProviderTencentCloudEO";
 import { NodeType } from "../nodes/typings";
 
@@ -52,6 +72,7 @@ const MULTIPLE_INPUT_SEPARATOR = ";";
 
 const CHALLENGE_TYPE_DNS01 = "dns-01";
 const CHALLENGE_TYPE_HTTP01 = "http-01";
+const CHALLENGE_TYPE_TLSALPN01 = "tls-alpn-01";
 
 export interface BizApplyNodeConfigFormProps {
   form: FormInstance;
@@ -70,6 +91,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
     if (option.reserve) return false;
     if (fieldChallengeType === CHALLENGE_TYPE_DNS01) return acmeDns01ProvidersMap.get(fieldProvider)?.provider === option.provider;
     if (fieldChallengeType === CHALLENGE_TYPE_HTTP01) return acmeHttp01ProvidersMap.get(fieldProvider)?.provider === option.provider;
+    if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01) return acmeTlsAlpn01ProvidersMap.get(fieldProvider)?.provider === option.provider;
     return false;
   };
   const accessOptionFilterForCA = (_: string, option: AccessModel) => {
@@ -119,6 +141,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
             case ACME_DNS01_PROVIDERS.JDCLOUD_DNS: {
               return BizApplyNodeConfigFieldsProviderJDCloudDNS;
             }
+            case ACME_DNS01_PROVIDERS.RFC2136: {
+              return BizApplyNodeConfigFieldsProviderRFC2136;
+            }
             case ACME_DNS01_PROVIDERS.TENCENTCLOUD_EO: {
               return BizApplyNodeConfigFieldsProviderTencentCloudEO;
             }
@@ -128,17 +153,58 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
 
       case CHALLENGE_TYPE_HTTP01:
         switch (fieldProvider) {
+          case ACME_HTTP01_PROVIDERS.ALIYUN_OSS: {
+            return BizApplyNodeConfigFieldsProviderAliyunOSS;
+          }
+          case ACME_HTTP01_PROVIDERS.AWS_S3: {
+            return BizApplyNodeConfigFieldsProviderAWSS3;
+          }
           case ACME_HTTP01_PROVIDERS.LOCAL: {
             return BizApplyNodeConfigFieldsProviderLocal;
           }
+          case ACME_HTTP01_PROVIDERS.LOCAL_STANDALONE: {
+            return BizApplyNodeConfigFieldsProviderLocalStandalone;
+          }
+          case ACME_HTTP01_PROVIDERS.QINIU_KODO: {
+            return BizApplyNodeConfigFieldsProviderQiniuKodo;
+          }
+          case ACME_HTTP01_PROVIDERS.S3: {
+            return BizApplyNodeConfigFieldsProviderS3;
+          }
           case ACME_HTTP01_PROVIDERS.SSH: {
             return BizApplyNodeConfigFieldsProviderSSH;
           }
+          case ACME_HTTP01_PROVIDERS.TENCENTCLOUD_COS: {
+            return BizApplyNodeConfigFieldsProviderTencentCloudCOS;
+          }
+        }
+        break;
+
+      case CHALLENGE_TYPE_TLSALPN01:
+        switch (fieldProvider) {
+          case ACME_TLSALPN01_PROVIDERS.LOCAL: {
+            return BizApplyNodeConfigFieldsProviderLocalTLSALPN;
+          }
+          case ACME_TLSALPN01_PROVIDERS.SSH: {
+            return BizApplyNodeConfigFieldsProviderSSHTLSALPN;
+          }
         }
         break;
     }
   }, [fieldChallengeType, fieldProvider]);
 
+  const NestedCAProviderConfigFields = useMemo(() => {
+    /*
+      注意：如果追加新的子组件，请保持以 ASCII 排序。
+      NOTICE: If you add new child component, please keep ASCII order.
+      */
+    switch (fieldCAProvider) {
+      case CA_PROVIDERS.PRIVATECA: {
+        return BizApplyNodeConfigFieldsCAProviderPrivateCA;
+      }
+    }
+  }, [fieldCAProvider]);
+
   const [showProviderAccess, setShowProviderAccess] = useState(false);
   useEffect(() => {
     // 内置的质询提供商（如本地主机）无需显示授权信息字段
@@ -165,6 +231,17 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
         }
         break;
 
+      case CHALLENGE_TYPE_TLSALPN01:
+        {
+          if (fieldProvider) {
+            const provider = acmeTlsAlpn01ProvidersMap.get(fieldProvider);
+            setShowProviderAccess(!provider?.builtin);
+          } else {
+            setShowProviderAccess(false);
+          }
+        }
+        break;
+
       default:
         {
           setShowProviderAccess(false);
@@ -198,6 +275,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
         .filter((access) => {
           if (fieldChallengeType === CHALLENGE_TYPE_DNS01) return acmeDns01ProvidersMap.get(fieldProvider)?.provider === access.provider;
           if (fieldChallengeType === CHALLENGE_TYPE_HTTP01) return acmeHttp01ProvidersMap.get(fieldProvider)?.provider === access.provider;
+          if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01) return acmeTlsAlpn01ProvidersMap.get(fieldProvider)?.provider === access.provider;
           return false;
         });
       if (availableAccesses.length === 1) {
@@ -243,6 +321,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
         break;
 
       case CHALLENGE_TYPE_HTTP01:
+      case CHALLENGE_TYPE_TLSALPN01:
         {
           formInst.setFieldValue("provider", void 0);
           formInst.setFieldValue("providerAccessId", void 0);
@@ -272,11 +351,14 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
     if (value == null || value === "") {
       formInst.setFieldValue("caProvider", void 0);
       formInst.setFieldValue("caProviderAccessId", void 0);
+      formInst.setFieldValue("caProviderConfig", void 0);
     } else if (value === initialValues?.caProvider) {
       formInst.setFieldValue("caProviderAccessId", initialValues?.caProviderAccessId);
+      formInst.setFieldValue("caProviderConfig", initialValues?.caProviderConfig);
     } else {
       if (caProvidersMap.get(fieldCAProvider)?.provider !== caProvidersMap.get(value!)?.provider) {
         formInst.setFieldValue("caProviderAccessId", void 0);
+        formInst.setFieldValue("caProviderConfig", void 0);
       }
     }
   };
@@ -293,7 +375,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
               <span
                 dangerouslySetInnerHTML={{
                   __html:
-                    fieldChallengeType === CHALLENGE_TYPE_HTTP01
+                    fieldChallengeType === CHALLENGE_TYPE_HTTP01 || fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
                       ? t("workflow_node.apply.form.domains.help_no_wildcard")
                       : t("workflow_node.apply.form.domains.help"),
                 }}
@@ -329,6 +411,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
 
           <Form.Item
             name="challengeType"
+            dependencies={["caProvider"]}
             label={t("workflow_node.apply.form.challenge_type.label")}
             rules={[formRule]}
             tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.challenge_type.tooltip") }}></span>}
@@ -336,18 +419,21 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
             <Radio.Group block onChange={(e) => handleChallengeTypeChange(e.target.value)}>
               <Radio.Button value={CHALLENGE_TYPE_DNS01}>DNS-01</Radio.Button>
               <Radio.Button value={CHALLENGE_TYPE_HTTP01}>HTTP-01</Radio.Button>
+              <Radio.Button value={CHALLENGE_TYPE_TLSALPN01}>TLS-ALPN-01</Radio.Button>
             </Radio.Group>
           </Form.Item>
 
           <Form.Item
             name="provider"
-            dependencies={["challengeType"]}
+            dependencies={["challengeType", "caProvider"]}
             label={
               fieldChallengeType === CHALLENGE_TYPE_DNS01
                 ? t("workflow_node.apply.form.provider_dns01.label")
                 : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                   ? t("workflow_node.apply.form.provider_http01.label")
-                  : t("workflow_node.apply.form.provider.label")
+                  : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                    ? t("workflow_node.apply.form.provider_tlsalpn01.label")
+                    : t("workflow_node.apply.form.provider.label")
             }
             rules={[formRule]}
           >
@@ -367,6 +453,14 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                 onSelect={handleProviderSelect}
                 onClear={handleProviderSelect}
               />
+            ) : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01 ? (
+              <ACMETlsAlpn01ProviderSelect
+                placeholder={t("workflow_node.apply.form.provider_tlsalpn01.placeholder")}
+                showAvailability
+                showSearch
+                onSelect={handleProviderSelect}
+                onClear={handleProviderSelect}
+              />
             ) : (
               <Select disabled placeholder={t("workflow_node.apply.form.provider.placeholder")} />
             )}
@@ -380,7 +474,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                 ? t("workflow_node.apply.form.provider_access_dns01.label")
                 : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                   ? t("workflow_node.apply.form.provider_access_http01.label")
-                  : t("workflow_node.apply.form.provider_access.label")
+                  : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                    ? t("workflow_node.apply.form.provider_access_tlsalpn01.label")
+                    : t("workflow_node.apply.form.provider_access.label")
             }
           >
             <div className="absolute -top-[6px] right-0 -translate-y-full">
@@ -392,11 +488,18 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                     <IconPlus size="1.25em" />
                   </Button>
                 }
-                usage={fieldChallengeType === CHALLENGE_TYPE_DNS01 ? "dns" : fieldChallengeType === CHALLENGE_TYPE_HTTP01 ? "hosting" : "dns-hosting"}
+                usage={
+                  fieldChallengeType === CHALLENGE_TYPE_DNS01
+                    ? "dns"
+                    : fieldChallengeType === CHALLENGE_TYPE_HTTP01 || fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                      ? "hosting"
+                      : "dns-hosting"
+                }
                 afterSubmit={(record) => {
                   if (!accessOptionFilter(record.provider, record)) return;
                   if (fieldChallengeType === CHALLENGE_TYPE_DNS01 && acmeDns01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
                   if (fieldChallengeType === CHALLENGE_TYPE_HTTP01 && acmeHttp01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
+                  if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01 && acmeTlsAlpn01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
                   formInst.setFieldValue("providerAccessId", record.id);
                 }}
               />
@@ -409,7 +512,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                     ? t("workflow_node.apply.form.provider_access_dns01.placeholder")
                     : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                       ? t("workflow_node.apply.form.provider_access_http01.placeholder")
-                      : t("workflow_node.apply.form.provider_access.placeholder")
+                      : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                        ? t("workflow_node.apply.form.provider_access_tlsalpn01.placeholder")
+                        : t("workflow_node.apply.form.provider_access.placeholder")
                 }
                 showSearch
                 onFilter={accessOptionFilter}
@@ -492,6 +597,10 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
             </Form.Item>
           </Form.Item>
 
+          <FormNestedFieldsContextProvider value={{ parentNamePath: "caProviderConfig" }}>
+            {NestedCAProviderConfigFields && <NestedCAProviderConfigFields />}
+          </FormNestedFieldsContextProvider>
+
           <Form.Item
             name="validityLifetime"
             label={t("workflow_node.apply.form.validity_lifetime.label")}
@@ -821,8 +930,8 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
           .every((e) => validDomainName(e, { allowWildcard: true }));
       }, t("common.errmsg.domain_invalid")),
       contactEmail: z.email(t("common.errmsg.email_invalid")),
-      challengeType: z.string(t("workflow_node.apply.form.challenge_type.placeholder")).nonempty(t("workflow_node.apply.form.challenge_type.placeholder")),
-      provider: z.string(t("workflow_node.apply.form.provider.placeholder")).nonempty(t("workflow_node.apply.form.provider.placeholder")),
+      challengeType: z.string().nullish(),
+      provider: z.string().nullish(),
       providerAccessId: z.string(t("workflow_node.apply.form.provider_access.placeholder")).nullish(),
       providerConfig: z.any().nullish(),
       caProvider: z.string().nullish(),
@@ -878,6 +987,25 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
       ),
     })
     .superRefine((values, ctx) => {
+      // 私有 CA 直接签发证书，无需质询
+      if (values.caProvider !== CA_PROVIDERS.PRIVATECA) {
+        if (!values.challengeType) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.apply.form.challenge_type.placeholder"),
+            path: ["challengeType"],
+          });
+        }
+
+        if (!values.provider) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.apply.form.provider.placeholder"),
+            path: ["provider"],
+          });
+        }
+      }
+
       if (values.domains) {
         if (values.challengeType === CHALLENGE_TYPE_HTTP01 && values.domains.includes("*")) {
           ctx.addIssue({
@@ -885,6 +1013,12 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
             message: t("workflow_node.apply.form.domains.errmsg.no_wildcard_in_http01"),
             path: ["domains"],
           });
+        } else if (values.challengeType === CHALLENGE_TYPE_TLSALPN01 && values.domains.includes("*")) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.apply.form.domains.errmsg.no_wildcard_in_tlsalpn01"),
+            path: ["domains"],
+          });
         }
       }
 
@@ -915,6 +1049,19 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
               }
             }
             break;
+
+          case CHALLENGE_TYPE_TLSALPN01:
+            {
+              const provider = acmeTlsAlpn01ProvidersMap.get(values.provider);
+              if (!provider?.builtin && !values.providerAccessId) {
+                ctx.addIssue({
+                  code: "custom",
+                  message: t("workflow_node.deploy.form.provider_access.placeholder"),
+                  path: ["providerAccessId"],
+                });
+              }
+            }
+            break;
         }
       }
 
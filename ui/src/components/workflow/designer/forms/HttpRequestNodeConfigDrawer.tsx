@@ -0,0 +1,40 @@
+import { useTranslation } from "react-i18next";
+import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
+import { Form } from "antd";
+
+import { NodeConfigDrawer } from "./_shared";
+import HttpRequestNodeConfigForm from "./HttpRequestNodeConfigForm";
+import { NodeType } from "../nodes/typings";
+
+export interface HttpRequestNodeConfigDrawerProps {
+  afterClose?: () => void;
+  loading?: boolean;
+  node: FlowNodeEntity;
+  open?: boolean;
+  onOpenChange?: (open: boolean) => void;
+}
+
+const HttpRequestNodeConfigDrawer = ({ node, ...props }: HttpRequestNodeConfigDrawerProps) => {
+  if (node.flowNodeType !== NodeType.HttpRequest) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.HttpRequest}`);
+  }
+
+  const { i18n } = useTranslation();
+
+  const [formInst] = Form.useForm();
+
+  return (
+    <NodeConfigDrawer
+      anchor={{
+        items: HttpRequestNodeConfigForm.getAnchorItems({ i18n }),
+      }}
+      form={formInst}
+      node={node}
+      {...props}
+    >
+      <HttpRequestNodeConfigForm form={formInst} node={node} />
+    </NodeConfigDrawer>
+  );
+};
+
+export default HttpRequestNodeConfigDrawer;
@@ -0,0 +1,237 @@
+import { useMemo } from "react";
+import { getI18n, useTranslation } from "react-i18next";
+import { type FlowNodeEntity, getNodeForm } from "@flowgram.ai/fixed-layout-editor";
+import { type AnchorProps, Divider, Form, type FormInstance, Input, InputNumber, Select, Switch, Typography } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import KeyValueInput from "@/components/KeyValueInput";
+import TextFileInput from "@/components/TextFileInput";
+import { type WorkflowNodeConfigForHttpRequest, defaultNodeConfigForHttpRequest } from "@/domain/workflow";
+import { useAntdForm } from "@/hooks";
+
+import { NodeFormContextProvider } from "./_context";
+import { NodeType } from "../nodes/typings";
+
+const HTTP_METHODS = ["GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"] as const;
+
+export interface HttpRequestNodeConfigFormProps {
+  form: FormInstance;
+  node: FlowNodeEntity;
+}
+
+const HttpRequestNodeConfigForm = ({ node, ...props }: HttpRequestNodeConfigFormProps) => {
+  if (node.flowNodeType !== NodeType.HttpRequest) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.HttpRequest}`);
+  }
+
+  const { i18n, t } = useTranslation();
+
+  const initialValues = useMemo(() => {
+    return getNodeForm(node)?.getValueIn("config") as WorkflowNodeConfigForHttpRequest | undefined;
+  }, [node]);
+
+  const formSchema = getSchema({ i18n });
+  const formRule = createSchemaFieldRule(formSchema);
+  const { form: formInst, formProps } = useAntdForm<z.infer<typeof formSchema>>({
+    form: props.form,
+    name: "workflowNodeHttpRequestConfigForm",
+    initialValues: initialValues ?? getInitialValues(),
+  });
+
+  return (
+    <NodeFormContextProvider value={{ node }}>
+      <Form {...formProps} clearOnDestroy={true} form={formInst} layout="vertical" preserve={false} scrollToFirstError>
+        <div id="parameters" data-anchor="parameters">
+          <Form.Item name="method" label={t("workflow_node.http_request.form.method.label")} rules={[formRule]}>
+            <Select
+              options={HTTP_METHODS.map((s) => ({ label: s, value: s }))}
+              placeholder={t("workflow_node.http_request.form.method.placeholder")}
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="url"
+            label={t("workflow_node.http_request.form.url.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.url.tooltip") }}></span>}
+          >
+            <Input placeholder={t("workflow_node.http_request.form.url.placeholder")} />
+          </Form.Item>
+
+          <Form.Item
+            name="headers"
+            label={t("workflow_node.http_request.form.headers.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.headers.tooltip") }}></span>}
+          >
+            <KeyValueInput
+              keyPlaceholder={t("workflow_node.http_request.form.headers.key.placeholder")}
+              valuePlaceholder={t("workflow_node.http_request.form.headers.value.placeholder")}
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="body"
+            label={t("workflow_node.http_request.form.body.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.body.tooltip") }}></span>}
+          >
+            <Input.TextArea autoSize={{ minRows: 3, maxRows: 10 }} placeholder={t("workflow_node.http_request.form.body.placeholder")} />
+          </Form.Item>
+
+          <Form.Item name="timeout" label={t("workflow_node.http_request.form.timeout.label")} rules={[formRule]}>
+            <InputNumber
+              style={{ width: "100%" }}
+              min={1}
+              max={3600}
+              placeholder={t("workflow_node.http_request.form.timeout.placeholder")}
+              addonAfter={t("workflow_node.http_request.form.timeout.unit")}
+            />
+          </Form.Item>
+        </div>
+
+        <div id="tls" data-anchor="tls">
+          <Divider size="small">
+            <Typography.Text className="text-xs font-normal" type="secondary">
+              {t("workflow_node.http_request.form_anchor.tls.title")}
+            </Typography.Text>
+          </Divider>
+
+          <Form.Item name="allowInsecureConnections" label={t("workflow_node.http_request.form.allow_insecure_conns.label")} rules={[formRule]}>
+            <Switch
+              checkedChildren={t("workflow_node.http_request.form.allow_insecure_conns.switch.on")}
+              unCheckedChildren={t("workflow_node.http_request.form.allow_insecure_conns.switch.off")}
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="caCertificate"
+            label={t("workflow_node.http_request.form.ca_certificate.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.ca_certificate.tooltip") }}></span>}
+          >
+            <TextFileInput autoSize={{ minRows: 3, maxRows: 10 }} placeholder={t("workflow_node.http_request.form.ca_certificate.placeholder")} />
+          </Form.Item>
+
+          <Form.Item
+            name="clientCertificate"
+            label={t("workflow_node.http_request.form.client_certificate.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.client_certificate.tooltip") }}></span>}
+          >
+            <TextFileInput autoSize={{ minRows: 3, maxRows: 10 }} placeholder={t("workflow_node.http_request.form.client_certificate.placeholder")} />
+          </Form.Item>
+
+          <Form.Item name="clientPrivateKey" label={t("workflow_node.http_request.form.client_private_key.label")} rules={[formRule]}>
+            <TextFileInput autoSize={{ minRows: 3, maxRows: 10 }} placeholder={t("workflow_node.http_request.form.client_private_key.placeholder")} />
+          </Form.Item>
+        </div>
+
+        <div id="response" data-anchor="response">
+          <Divider size="small">
+            <Typography.Text className="text-xs font-normal" type="secondary">
+              {t("workflow_node.http_request.form_anchor.response.title")}
+            </Typography.Text>
+          </Divider>
+
+          <Form.Item
+            name="responseFields"
+            label={t("workflow_node.http_request.form.response_fields.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.response_fields.tooltip") }}></span>}
+          >
+            <KeyValueInput
+              keyPlaceholder={t("workflow_node.http_request.form.response_fields.key.placeholder")}
+              valuePlaceholder={t("workflow_node.http_request.form.response_fields.value.placeholder")}
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="ignoreErrorStatus"
+            label={t("workflow_node.http_request.form.ignore_error_status.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.http_request.form.ignore_error_status.tooltip") }}></span>}
+          >
+            <Switch />
+          </Form.Item>
+        </div>
+      </Form>
+    </NodeFormContextProvider>
+  );
+};
+
+const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
+  const { t } = i18n;
+
+  return ["parameters", "tls", "response"].map((key) => ({
+    key: key,
+    title: t(`workflow_node.http_request.form_anchor.${key}.tab`),
+    href: "#" + key,
+  }));
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    ...defaultNodeConfigForHttpRequest(),
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z
+    .object({
+      method: z.enum(HTTP_METHODS, t("workflow_node.http_request.form.method.placeholder")),
+      url: z.string(t("workflow_node.http_request.form.url.placeholder")).nonempty(t("workflow_node.http_request.form.url.placeholder")),
+      headers: z.record(z.string(), z.string()).nullish(),
+      body: z.string().nullish(),
+      timeout: z.preprocess(
+        (v) => (v == null || v === "" ? void 0 : Number(v)),
+        z
+          .number()
+          .int(t("workflow_node.http_request.form.timeout.placeholder"))
+          .gte(1, t("workflow_node.http_request.form.timeout.placeholder"))
+          .lte(3600, t("workflow_node.http_request.form.timeout.placeholder"))
+          .nullish()
+      ),
+      allowInsecureConnections: z.boolean().nullish(),
+      caCertificate: z
+        .string()
+        .max(20480, t("common.errmsg.string_max", { max: 20480 }))
+        .nullish(),
+      clientCertificate: z
+        .string()
+        .max(20480, t("common.errmsg.string_max", { max: 20480 }))
+        .nullish(),
+      clientPrivateKey: z
+        .string()
+        .max(20480, t("common.errmsg.string_max", { max: 20480 }))
+        .nullish(),
+      responseFields: z
+        .record(z.string(), z.string())
+        .nullish()
+        .refine((v) => {
+          if (!v) return true;
+          return Object.keys(v).every((key) => key !== "status" && key !== "body");
+        }, t("workflow_node.http_request.form.response_fields.errmsg.reserved")),
+      ignoreErrorStatus: z.boolean().nullish(),
+    })
+    .superRefine((values, ctx) => {
+      // 客户端证书与私钥需成对提供
+      if (!!values.clientCertificate !== !!values.clientPrivateKey) {
+        ctx.addIssue({
+          code: "custom",
+          message: t("workflow_node.http_request.form.client_certificate.errmsg.unpaired"),
+          path: [values.clientCertificate ? "clientPrivateKey" : "clientCertificate"],
+        });
+      }
+    });
+};
+
+const _default = Object.assign(HttpRequestNodeConfigForm, {
+  getAnchorItems,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,40 @@
+import { useTranslation } from "react-i18next";
+import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
+import { Form } from "antd";
+
+import { NodeConfigDrawer } from "./_shared";
+import ScriptNodeConfigForm from "./ScriptNodeConfigForm";
+import { NodeType } from "../nodes/typings";
+
+export interface ScriptNodeConfigDrawerProps {
+  afterClose?: () => void;
+  loading?: boolean;
+  node: FlowNodeEntity;
+  open?: boolean;
+  onOpenChange?: (open: boolean) => void;
+}
+
+const ScriptNodeConfigDrawer = ({ node, ...props }: ScriptNodeConfigDrawerProps) => {
+  if (node.flowNodeType !== NodeType.Script) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.Script}`);
+  }
+
+  const { i18n } = useTranslation();
+
+  const [formInst] = Form.useForm();
+
+  return (
+    <NodeConfigDrawer
+      anchor={{
+        items: ScriptNodeConfigForm.getAnchorItems({ i18n }),
+      }}
+      form={formInst}
+      node={node}
+      {...props}
+    >
+      <ScriptNodeConfigForm form={formInst} node={node} />
+    </NodeConfigDrawer>
+  );
+};
+
+export default ScriptNodeConfigDrawer;
@@ -0,0 +1,94 @@
+import { useMemo } from "react";
+import { getI18n, useTranslation } from "react-i18next";
+import { type FlowNodeEntity, getNodeForm } from "@flowgram.ai/fixed-layout-editor";
+import { type AnchorProps, Form, type FormInstance } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import CodeInput from "@/components/CodeInput";
+import { type WorkflowNodeConfigForScript, defaultNodeConfigForScript } from "@/domain/workflow";
+import { useAntdForm } from "@/hooks";
+
+import { NodeFormContextProvider } from "./_context";
+import { NodeType } from "../nodes/typings";
+
+export interface ScriptNodeConfigFormProps {
+  form: FormInstance;
+  node: FlowNodeEntity;
+}
+
+const ScriptNodeConfigForm = ({ node, ...props }: ScriptNodeConfigFormProps) => {
+  if (node.flowNodeType !== NodeType.Script) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.Script}`);
+  }
+
+  const { i18n, t } = useTranslation();
+
+  const initialValues = useMemo(() => {
+    return getNodeForm(node)?.getValueIn("config") as WorkflowNodeConfigForScript | undefined;
+  }, [node]);
+
+  const formSchema = getSchema({ i18n });
+  const formRule = createSchemaFieldRule(formSchema);
+  const { form: formInst, formProps } = useAntdForm<z.infer<typeof formSchema>>({
+    form: props.form,
+    name: "workflowNodeScriptConfigForm",
+    initialValues: initialValues ?? getInitialValues(),
+  });
+
+  return (
+    <NodeFormContextProvider value={{ node }}>
+      <Form {...formProps} clearOnDestroy={true} form={formInst} layout="vertical" preserve={false} scrollToFirstError>
+        <div id="parameters" data-anchor="parameters">
+          <Form.Item
+            name="script"
+            label={t("workflow_node.script.form.script.label")}
+            extra={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.script.form.script.help") }}></span>}
+            rules={[formRule]}
+          >
+            <CodeInput
+              height="auto"
+              minHeight="256px"
+              maxHeight="640px"
+              language="python"
+              placeholder={t("workflow_node.script.form.script.placeholder")}
+            />
+          </Form.Item>
+        </div>
+      </Form>
+    </NodeFormContextProvider>
+  );
+};
+
+const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
+  const { t } = i18n;
+
+  return ["parameters"].map((key) => ({
+    key: key,
+    title: t(`workflow_node.script.form_anchor.${key}.tab`),
+    href: "#" + key,
+  }));
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    ...defaultNodeConfigForScript(),
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    script: z
+      .string(t("workflow_node.script.form.script.placeholder"))
+      .refine((v) => !!v?.trim(), t("workflow_node.script.form.script.placeholder")),
+  });
+};
+
+const _default = Object.assign(ScriptNodeConfigForm, {
+  getAnchorItems,
+  getSchema,
+});
+
+export default _default;
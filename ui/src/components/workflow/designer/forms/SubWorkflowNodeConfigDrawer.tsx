@@ -0,0 +1,40 @@
+import { useTranslation } from "react-i18next";
+import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
+import { Form } from "antd";
+
+import { NodeConfigDrawer } from "./_shared";
+import SubWorkflowNodeConfigForm from "./SubWorkflowNodeConfigForm";
+import { NodeType } from "../nodes/typings";
+
+export interface SubWorkflowNodeConfigDrawerProps {
+  afterClose?: () => void;
+  loading?: boolean;
+  node: FlowNodeEntity;
+  open?: boolean;
+  onOpenChange?: (open: boolean) => void;
+}
+
+const SubWorkflowNodeConfigDrawer = ({ node, ...props }: SubWorkflowNodeConfigDrawerProps) => {
+  if (node.flowNodeType !== NodeType.SubWorkflow) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.SubWorkflow}`);
+  }
+
+  const { i18n } = useTranslation();
+
+  const [formInst] = Form.useForm();
+
+  return (
+    <NodeConfigDrawer
+      anchor={{
+        items: SubWorkflowNodeConfigForm.getAnchorItems({ i18n }),
+      }}
+      form={formInst}
+      node={node}
+      {...props}
+    >
+      <SubWorkflowNodeConfigForm form={formInst} node={node} />
+    </NodeConfigDrawer>
+  );
+};
+
+export default SubWorkflowNodeConfigDrawer;
@@ -0,0 +1,215 @@
+import { useMemo, useState } from "react";
+import { getI18n, useTranslation } from "react-i18next";
+import { type FlowNodeEntity, getNodeForm } from "@flowgram.ai/fixed-layout-editor";
+import { useRequest } from "ahooks";
+import { type AnchorProps, Divider, Form, type FormInstance, Select, Switch, Typography, theme } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import KeyValueInput from "@/components/KeyValueInput";
+import { type WorkflowModel, type WorkflowNodeConfigForSubWorkflow, defaultNodeConfigForSubWorkflow } from "@/domain/workflow";
+import { useAntdForm, useZustandShallowSelector } from "@/hooks";
+import { list as listWorkflows } from "@/repository/workflow";
+import { useWorkflowStore } from "@/stores/workflow";
+
+import { getAllPreviousNodes } from "../_util";
+import { NodeFormContextProvider } from "./_context";
+import { NodeType } from "../nodes/typings";
+
+export interface SubWorkflowNodeConfigFormProps {
+  form: FormInstance;
+  node: FlowNodeEntity;
+}
+
+const SubWorkflowNodeConfigForm = ({ node, ...props }: SubWorkflowNodeConfigFormProps) => {
+  if (node.flowNodeType !== NodeType.SubWorkflow) {
+    console.warn(`[certimate] current workflow node type is not: ${NodeType.SubWorkflow}`);
+  }
+
+  const { i18n, t } = useTranslation();
+
+  const { token: themeToken } = theme.useToken();
+
+  const { workflow } = useWorkflowStore(useZustandShallowSelector(["workflow"]));
+
+  const initialValues = useMemo(() => {
+    return getNodeForm(node)?.getValueIn("config") as WorkflowNodeConfigForSubWorkflow | undefined;
+  }, [node]);
+
+  const formSchema = getSchema({ i18n }).superRefine((values, ctx) => {
+    if (values.certificateOutputNodeId) {
+      if (!certificateOutputNodeIdOptions.some((option) => option.value === values.certificateOutputNodeId)) {
+        ctx.addIssue({
+          code: "custom",
+          message: t("workflow_node.sub_workflow.form.certificate_output_node_id.placeholder"),
+          path: ["certificateOutputNodeId"],
+        });
+      }
+    }
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const { form: formInst, formProps } = useAntdForm<z.infer<typeof formSchema>>({
+    form: props.form,
+    name: "workflowNodeSubWorkflowConfigForm",
+    initialValues: initialValues ?? getInitialValues(),
+  });
+
+  const [workflows, setWorkflows] = useState<WorkflowModel[]>([]);
+  const { loading: workflowsLoading } = useRequest(
+    () => {
+      return listWorkflows({ page: 1, perPage: 500, sort: "name" });
+    },
+    {
+      onSuccess: (res) => {
+        setWorkflows(res.items);
+      },
+    }
+  );
+
+  const workflowIdOptions = useMemo(() => {
+    // 子工作流不能是当前工作流本身，且须已发布
+    return workflows
+      .filter((item) => item.id !== workflow?.id)
+      .map((item) => ({
+        label: item.name,
+        value: item.id,
+        disabled: !item.hasContent,
+      }));
+  }, [workflows, workflow?.id]);
+
+  const certificateOutputNodeIdOptions = useMemo(() => {
+    return getAllPreviousNodes(node)
+      .filter((node) => node.flowNodeType === NodeType.BizApply || node.flowNodeType === NodeType.BizUpload)
+      .map((node) => {
+        return {
+          label: getNodeForm(node)?.getValueIn("name"),
+          value: node.id,
+        };
+      });
+  }, [node]);
+
+  return (
+    <NodeFormContextProvider value={{ node }}>
+      <Form {...formProps} clearOnDestroy={true} form={formInst} layout="vertical" preserve={false} scrollToFirstError>
+        <div id="parameters" data-anchor="parameters">
+          <Form.Item
+            name="workflowId"
+            label={t("workflow_node.sub_workflow.form.workflow_id.label")}
+            extra={t("workflow_node.sub_workflow.form.workflow_id.help")}
+            rules={[formRule]}
+          >
+            <Select
+              loading={workflowsLoading}
+              optionFilterProp="label"
+              optionRender={({ label, value }) => {
+                return (
+                  <div className="flex items-center justify-between gap-4 overflow-hidden">
+                    <div className="flex-1 truncate">{label}</div>
+                    <div className="origin-right scale-90 font-mono text-xs" style={{ color: themeToken.colorTextSecondary }}>
+                      (ID: {value})
+                    </div>
+                  </div>
+                );
+              }}
+              options={workflowIdOptions}
+              placeholder={t("workflow_node.sub_workflow.form.workflow_id.placeholder")}
+              showSearch
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="certificateOutputNodeId"
+            label={t("workflow_node.sub_workflow.form.certificate_output_node_id.label")}
+            extra={t("workflow_node.sub_workflow.form.certificate_output_node_id.help")}
+            rules={[formRule]}
+          >
+            <Select
+              allowClear
+              optionRender={({ label, value }) => {
+                return (
+                  <div className="flex items-center justify-between gap-4 overflow-hidden">
+                    <div className="flex-1 truncate">{label}</div>
+                    <div className="origin-right scale-90 font-mono text-xs" style={{ color: themeToken.colorTextSecondary }}>
+                      (NodeID: {value})
+                    </div>
+                  </div>
+                );
+              }}
+              options={certificateOutputNodeIdOptions}
+              placeholder={t("workflow_node.sub_workflow.form.certificate_output_node_id.placeholder")}
+            />
+          </Form.Item>
+        </div>
+
+        <div id="variables" data-anchor="variables">
+          <Divider size="small">
+            <Typography.Text className="text-xs font-normal" type="secondary">
+              {t("workflow_node.sub_workflow.form_anchor.variables.title")}
+            </Typography.Text>
+          </Divider>
+
+          <Form.Item
+            name="variables"
+            label={t("workflow_node.sub_workflow.form.variables.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.sub_workflow.form.variables.tooltip") }}></span>}
+          >
+            <KeyValueInput
+              keyPlaceholder={t("workflow_node.sub_workflow.form.variables.key.placeholder")}
+              valuePlaceholder={t("workflow_node.sub_workflow.form.variables.value.placeholder")}
+            />
+          </Form.Item>
+
+          <Form.Item
+            name="inheritVariables"
+            label={t("workflow_node.sub_workflow.form.inherit_variables.label")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.sub_workflow.form.inherit_variables.tooltip") }}></span>}
+          >
+            <Switch />
+          </Form.Item>
+        </div>
+      </Form>
+    </NodeFormContextProvider>
+  );
+};
+
+const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
+  const { t } = i18n;
+
+  return ["parameters", "variables"].map((key) => ({
+    key: key,
+    title: t(`workflow_node.sub_workflow.form_anchor.${key}.tab`),
+    href: "#" + key,
+  }));
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    ...defaultNodeConfigForSubWorkflow(),
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    workflowId: z.string(t("workflow_node.sub_workflow.form.workflow_id.placeholder")).nonempty(t("workflow_node.sub_workflow.form.workflow_id.placeholder")),
+    certificateOutputNodeId: z.string().nullish(),
+    variables: z
+      .record(z.string(), z.string())
+      .nullish()
+      .refine((v) => {
+        if (!v) return true;
+        return Object.keys(v).every((key) => /^[A-Za-z_][A-Za-z0-9_]*$/.test(key));
+      }, t("workflow_node.sub_workflow.form.variables.errmsg.invalid")),
+    inheritVariables: z.boolean().nullish(),
+  });
+};
+
+const _default = Object.assign(SubWorkflowNodeConfigForm, {
+  getAnchorItems,
+  getSchema,
+});
+
+export default _default;
@@ -0,0 +1,73 @@
+import { getI18n } from "react-i18next";
+import { FeedbackLevel, Field } from "@flowgram.ai/fixed-layout-editor";
+import { IconUserCheck } from "@tabler/icons-react";
+import { Avatar } from "antd";
+
+import { notificationProvidersMap } from "@/domain/provider";
+import { newNode } from "@/domain/workflow";
+
+import { BaseNode } from "./_shared";
+import { NodeKindType, type NodeRegistry, NodeType } from "./typings";
+import ApprovalNodeConfigForm from "../forms/ApprovalNodeConfigForm";
+
+export const ApprovalNodeRegistry: NodeRegistry = {
+  type: NodeType.Approval,
+
+  kind: NodeKindType.Basis,
+
+  meta: {
+    labelText: getI18n().t("workflow_node.approval.label"),
+
+    icon: IconUserCheck,
+    iconColor: "#fff",
+    iconBgColor: "#0693d4",
+
+    clickable: true,
+    expandable: false,
+  },
+
+  formMeta: {
+    validate: {
+      ["config"]: ({ value }) => {
+        const res = ApprovalNodeConfigForm.getSchema({}).safeParse(value);
+        if (!res.success) {
+          return {
+            message: res.error.message,
+            level: FeedbackLevel.Error,
+          };
+        }
+      },
+    },
+
+    render: () => {
+      const { t } = getI18n();
+
+      return (
+        <BaseNode
+          description={
+            <div className="flex items-center justify-between gap-1">
+              <Field<string> name="config.provider">
+                {({ field: { value } }) => (
+                  <>
+                    {value ? (
+                      <>
+                        <div className="flex-1 truncate">{t(notificationProvidersMap.get(value)?.name ?? "")}</div>
+                        <Avatar shape="square" src={notificationProvidersMap.get(value)?.icon} size={20} />
+                      </>
+                    ) : (
+                      t("workflow.detail.design.editor.placeholder")
+                    )}
+                  </>
+                )}
+              </Field>
+            </div>
+          }
+        />
+      );
+    },
+  },
+
+  onAdd: () => {
+    return newNode(NodeType.Approval, { i18n: getI18n() });
+  },
+};
@@ -0,0 +1,71 @@
+import { getI18n } from "react-i18next";
+import { FeedbackLevel, Field } from "@flowgram.ai/fixed-layout-editor";
+import { IconWorld } from "@tabler/icons-react";
+
+import { newNode } from "@/domain/workflow";
+
+import { BaseNode } from "./_shared";
+import { NodeKindType, type NodeRegistry, NodeType } from "./typings";
+import HttpRequestNodeConfigForm from "../forms/HttpRequestNodeConfigForm";
+
+export const HttpRequestNodeRegistry: NodeRegistry = {
+  type: NodeType.HttpRequest,
+
+  kind: NodeKindType.Basis,
+
+  meta: {
+    labelText: getI18n().t("workflow_node.http_request.label"),
+
+    icon: IconWorld,
+    iconColor: "#fff",
+    iconBgColor: "#0d9488",
+
+    clickable: true,
+    expandable: false,
+  },
+
+  formMeta: {
+    validate: {
+      ["config"]: ({ value }) => {
+        const res = HttpRequestNodeConfigForm.getSchema({}).safeParse(value);
+        if (!res.success) {
+          return {
+            message: res.error.message,
+            level: FeedbackLevel.Error,
+          };
+        }
+      },
+    },
+
+    render: () => {
+      const { t } = getI18n();
+
+      return (
+        <BaseNode
+          description={
+            <div className="flex items-center gap-1 overflow-hidden">
+              <Field<string> name="config.method">
+                {({ field: { value } }) => (
+                  <>
+                    <div className="font-mono">{value}</div>
+                  </>
+                )}
+              </Field>
+              <Field<string> name="config.url">
+                {({ field: { value } }) => (
+                  <>
+                    <div className="flex-1 truncate">{value || t("workflow.detail.design.editor.placeholder")}</div>
+                  </>
+                )}
+              </Field>
+            </div>
+          }
+        />
+      );
+    },
+  },
+
+  onAdd() {
+    return newNode(NodeType.HttpRequest, { i18n: getI18n() });
+  },
+};
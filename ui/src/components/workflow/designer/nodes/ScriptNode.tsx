@@ -0,0 +1,62 @@
+import { getI18n } from "react-i18next";
+import { FeedbackLevel, Field } from "@flowgram.ai/fixed-layout-editor";
+import { IconCode } from "@tabler/icons-react";
+
+import { newNode } from "@/domain/workflow";
+
+import { BaseNode } from "./_shared";
+import { NodeKindType, type NodeRegistry, NodeType } from "./typings";
+import ScriptNodeConfigForm from "../forms/ScriptNodeConfigForm";
+
+export const ScriptNodeRegistry: NodeRegistry = {
+  type: NodeType.Script,
+
+  kind: NodeKindType.Basis,
+
+  meta: {
+    labelText: getI18n().t("workflow_node.script.label"),
+
+    icon: IconCode,
+    iconColor: "#fff",
+    iconBgColor: "#7c3aed",
+
+    clickable: true,
+    expandable: false,
+  },
+
+  formMeta: {
+    validate: {
+      ["config"]: ({ value }) => {
+        const res = ScriptNodeConfigForm.getSchema({}).safeParse(value);
+        if (!res.success) {
+          return {
+            message: res.error.message,
+            level: FeedbackLevel.Error,
+          };
+        }
+      },
+    },
+
+    render: () => {
+      const { t } = getI18n();
+
+      return (
+        <BaseNode
+          description={
+            <Field<string> name="config.script">
+              {({ field: { value } }) => (
+                <>
+                  <div className="truncate font-mono">{value?.trim() ? value.trim().split("\n")[0] : t("workflow.detail.design.editor.placeholder")}</div>
+                </>
+              )}
+            </Field>
+          }
+        />
+      );
+    },
+  },
+
+  onAdd() {
+    return newNode(NodeType.Script, { i18n: getI18n() });
+  },
+};
@@ -0,0 +1,74 @@
+import { getI18n } from "react-i18next";
+import { FeedbackLevel, Field } from "@flowgram.ai/fixed-layout-editor";
+import { IconSubtask } from "@tabler/icons-react";
+
+import { newNode } from "@/domain/workflow";
+
+import { getAllPreviousNodes } from "../_util";
+import { BaseNode } from "./_shared";
+import { NodeKindType, type NodeRegistry, NodeType } from "./typings";
+import SubWorkflowNodeConfigForm from "../forms/SubWorkflowNodeConfigForm";
+
+export const SubWorkflowNodeRegistry: NodeRegistry = {
+  type: NodeType.SubWorkflow,
+
+  kind: NodeKindType.Logic,
+
+  meta: {
+    labelText: getI18n().t("workflow_node.sub_workflow.label"),
+
+    icon: IconSubtask,
+    iconColor: "#fff",
+    iconBgColor: "#373c43",
+
+    clickable: true,
+    expandable: false,
+  },
+
+  formMeta: {
+    validate: {
+      ["config"]: ({ value }) => {
+        const res = SubWorkflowNodeConfigForm.getSchema({}).safeParse(value);
+        if (!res.success) {
+          return {
+            message: res.error.message,
+            level: FeedbackLevel.Error,
+          };
+        }
+      },
+      ["config.certificateOutputNodeId"]: ({ value, context: { node } }) => {
+        if (value == null) return;
+
+        const prevNodeIds = getAllPreviousNodes(node).map((e) => e.id);
+        if (!prevNodeIds.includes(value)) {
+          return {
+            message: "Invalid input",
+            level: FeedbackLevel.Error,
+          };
+        }
+      },
+    },
+
+    render: () => {
+      const { t } = getI18n();
+
+      return (
+        <BaseNode
+          description={
+            <Field<string> name="config.workflowId">
+              {({ field: { value } }) => (
+                <>
+                  <div className="truncate">{value ? `WorkflowID: ${value}` : t("workflow.detail.design.editor.placeholder")}</div>
+                </>
+              )}
+            </Field>
+          }
+        />
+      );
+    },
+  },
+
+  onAdd() {
+    return newNode(NodeType.SubWorkflow, { i18n: getI18n() });
+  },
+};
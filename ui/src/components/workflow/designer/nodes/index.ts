@@ -1,4 +1,5 @@
-﻿import { BizApplyNodeRegistry } from "./BizApplyNodeRegistry";
+﻿import { ApprovalNodeRegistry } from "./ApprovalNode";
+import { BizApplyNodeRegistry } from "./BizApplyNodeRegistry";
 import { BizDeployNodeRegistry } from "./BizDeployNodeRegistry";
 import { BizMonitorNodeRegistry } from "./BizMonitorNodeRegistry";
 import { BizNotifyNodeRegistry } from "./BizNotifyNodeRegistry";
@@ -6,7 +7,10 @@ import { BizUploadNodeRegistry } from "./BizUploadNodeRegistry";
 import { BranchBlockNodeRegistry, ConditionNodeRegistry } from "./ConditionNode";
 import { DelayNodeRegistry } from "./DelayNode";
 import { EndNodeRegistry } from "./EndNode";
+import { HttpRequestNodeRegistry } from "./HttpRequestNode";
+import { ScriptNodeRegistry } from "./ScriptNode";
 import { StartNodeRegistry } from "./StartNode";
+import { SubWorkflowNodeRegistry } from "./SubWorkflowNode";
 import { CatchBlockNodeRegistry, TryCatchNodeRegistry } from "./TryCatchNode";
 
 export const getAllNodeRegistries = () => {
@@ -14,6 +18,9 @@ export const getAllNodeRegistries = () => {
     StartNodeRegistry,
     EndNodeRegistry,
     DelayNodeRegistry,
+    ScriptNodeRegistry,
+    HttpRequestNodeRegistry,
+    ApprovalNodeRegistry,
     BizApplyNodeRegistry,
     BizUploadNodeRegistry,
     BizMonitorNodeRegistry,
@@ -23,6 +30,7 @@ export const getAllNodeRegistries = () => {
     BranchBlockNodeRegistry,
     TryCatchNodeRegistry,
     CatchBlockNodeRegistry,
+    SubWorkflowNodeRegistry,
   ];
 };
 
@@ -19,6 +19,10 @@ export enum NodeType {
   TryCatch = "tryCatch",
   TryBlock = "tryBlock",
   CatchBlock = "catchBlock",
+  SubWorkflow = "subWorkflow",
+  Script = "script",
+  HttpRequest = "httpRequest",
+  Approval = "approval",
   BizApply = "bizApply",
   BizUpload = "bizUpload",
   BizMonitor = "bizMonitor",
@@ -35,6 +39,10 @@ console.assert(NodeType.BranchBlock === WORKFLOW_NODE_TYPES.BRANCHBLOCK);
 console.assert(NodeType.TryCatch === WORKFLOW_NODE_TYPES.TRYCATCH);
 console.assert(NodeType.TryBlock === WORKFLOW_NODE_TYPES.TRYBLOCK);
 console.assert(NodeType.CatchBlock === WORKFLOW_NODE_TYPES.CATCHBLOCK);
+console.assert(NodeType.SubWorkflow === WORKFLOW_NODE_TYPES.SUB_WORKFLOW);
+console.assert(NodeType.Script === WORKFLOW_NODE_TYPES.SCRIPT);
+console.assert(NodeType.HttpRequest === WORKFLOW_NODE_TYPES.HTTP_REQUEST);
+console.assert(NodeType.Approval === WORKFLOW_NODE_TYPES.APPROVAL);
 console.assert(NodeType.BizApply === WORKFLOW_NODE_TYPES.BIZ_APPLY);
 console.assert(NodeType.BizUpload === WORKFLOW_NODE_TYPES.BIZ_UPLOAD);
 console.assert(NodeType.BizMonitor === WORKFLOW_NODE_TYPES.BIZ_MONITOR);
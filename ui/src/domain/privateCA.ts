@@ -0,0 +1,18 @@
+export interface PrivateCAModel extends BaseModel {
+  name: string;
+  parentRef?: string;
+  commonName: string;
+  serialNumber: string;
+  certificate: string;
+  keyAlgorithm: string;
+  validityNotBefore: ISO8601String;
+  validityNotAfter: ISO8601String;
+}
+
+export const PRIVATE_CA_PROFILES = Object.freeze({
+  SERVER: "server",
+  CLIENT: "client",
+  PEER: "peer",
+} as const);
+
+export type PrivateCAProfileType = (typeof PRIVATE_CA_PROFILES)[keyof typeof PRIVATE_CA_PROFILES];
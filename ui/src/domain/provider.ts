@@ -73,10 +73,13 @@ export const ACCESS_PROVIDERS = Object.freeze({
   NS1: "ns1",
   PORKBUN: "porkbun",
   POWERDNS: "powerdns",
+  PRIVATECA: "privateca",
   PROXMOXVE: "proxmoxve",
   QINIU: "qiniu",
   RAINYUN: "rainyun",
   RATPANEL: "ratpanel",
+  RFC2136: "rfc2136",
+  S3: "s3",
   SAFELINE: "safeline",
   SECTIGO: "sectigo",
   SLACKBOT: "slackbot",
@@ -161,6 +164,7 @@ export const accessProvidersMap: Map<AccessProvider["type"] | string, AccessProv
       [ACCESS_PROVIDERS.APISIX, "provider.apisix", "/imgs/providers/apisix.svg", [ACCESS_USAGES.HOSTING]],
       [ACCESS_PROVIDERS.KONG, "provider.kong", "/imgs/providers/kong.png", [ACCESS_USAGES.HOSTING]],
       [ACCESS_PROVIDERS.PROXMOXVE, "provider.proxmoxve", "/imgs/providers/proxmoxve.svg", [ACCESS_USAGES.HOSTING]],
+      [ACCESS_PROVIDERS.S3, "provider.s3", "/imgs/providers/s3.svg", [ACCESS_USAGES.HOSTING]],
 
       [ACCESS_PROVIDERS.CLOUDFLARE, "provider.cloudflare", "/imgs/providers/cloudflare.svg", [ACCESS_USAGES.DNS]],
       [ACCESS_PROVIDERS.CLOUDNS, "provider.cloudns", "/imgs/providers/cloudns.png", [ACCESS_USAGES.DNS]],
@@ -185,11 +189,13 @@ export const accessProvidersMap: Map<AccessProvider["type"] | string, AccessProv
       [ACCESS_PROVIDERS.CMCCCLOUD, "provider.cmcccloud", "/imgs/providers/cmcccloud.svg", [ACCESS_USAGES.DNS]],
       [ACCESS_PROVIDERS.WESTCN, "provider.westcn", "/imgs/providers/westcn.svg", [ACCESS_USAGES.DNS]],
       [ACCESS_PROVIDERS.POWERDNS, "provider.powerdns", "/imgs/providers/powerdns.svg", [ACCESS_USAGES.DNS]],
+      [ACCESS_PROVIDERS.RFC2136, "provider.rfc2136", "/imgs/providers/rfc2136.svg", [ACCESS_USAGES.DNS]],
       [ACCESS_PROVIDERS.ACMEDNS, "provider.acmedns", "/imgs/providers/acmedns.png", [ACCESS_USAGES.DNS]],
       [ACCESS_PROVIDERS.ACMEHTTPREQ, "provider.acmehttpreq", "/imgs/providers/acmehttpreq.svg", [ACCESS_USAGES.DNS]],
 
       [ACCESS_PROVIDERS.LETSENCRYPT, "provider.letsencrypt", "/imgs/providers/letsencrypt.svg", [ACCESS_USAGES.CA], "builtin"],
       [ACCESS_PROVIDERS.LETSENCRYPTSTAGING, "provider.letsencryptstaging", "/imgs/providers/letsencrypt.svg", [ACCESS_USAGES.CA], "builtin"],
+      [ACCESS_PROVIDERS.PRIVATECA, "provider.privateca", "/imgs/providers/privateca.svg", [ACCESS_USAGES.CA], "builtin"],
       [ACCESS_PROVIDERS.ACTALISSSL, "provider.actalisssl", "/imgs/providers/actalisssl.png", [ACCESS_USAGES.CA]],
       [ACCESS_PROVIDERS.GLOBALSIGNATLAS, "provider.globalsignatlas", "/imgs/providers/globalsignatlas.png", [ACCESS_USAGES.CA]],
       [ACCESS_PROVIDERS.GOOGLETRUSTSERVICES, "provider.googletrustservices", "/imgs/providers/google.svg", [ACCESS_USAGES.CA]],
@@ -232,6 +238,7 @@ export const CA_PROVIDERS = Object.freeze({
   GOOGLETRUSTSERVICES: `${ACCESS_PROVIDERS.GOOGLETRUSTSERVICES}`,
   LETSENCRYPT: `${ACCESS_PROVIDERS.LETSENCRYPT}`,
   LETSENCRYPTSTAGING: `${ACCESS_PROVIDERS.LETSENCRYPTSTAGING}`,
+  PRIVATECA: `${ACCESS_PROVIDERS.PRIVATECA}`,
   SECTIGO: `${ACCESS_PROVIDERS.SECTIGO}`,
   SSLCOM: `${ACCESS_PROVIDERS.SSLCOM}`,
   ZEROSSL: `${ACCESS_PROVIDERS.ZEROSSL}`,
@@ -250,6 +257,7 @@ export const caProvidersMap: Map<CAProvider["type"] | string, CAProvider> = new
     [
       [CA_PROVIDERS.LETSENCRYPT, "builtin"],
       [CA_PROVIDERS.LETSENCRYPTSTAGING, "builtin"],
+      [CA_PROVIDERS.PRIVATECA, "builtin"],
       [CA_PROVIDERS.ACTALISSSL],
       [CA_PROVIDERS.GLOBALSIGNATLAS],
       [CA_PROVIDERS.GOOGLETRUSTSERVICES],
@@ -318,6 +326,7 @@ export const ACME_DNS01_PROVIDERS = Object.freeze({
   PORKBUN: `${ACCESS_PROVIDERS.PORKBUN}`,
   POWERDNS: `${ACCESS_PROVIDERS.POWERDNS}`,
   RAINYUN: `${ACCESS_PROVIDERS.RAINYUN}`,
+  RFC2136: `${ACCESS_PROVIDERS.RFC2136}`,
   SPACESHIP: `${ACCESS_PROVIDERS.SPACESHIP}`,
   UCLOUD_UDNR: `${ACCESS_PROVIDERS.UCLOUD}-udnr`,
   TENCENTCLOUD: `${ACCESS_PROVIDERS.TENCENTCLOUD}`, // 兼容旧值，等同于 `TENCENTCLOUD_DNS`
@@ -380,6 +389,7 @@ export const acmeDns01ProvidersMap: Map<ACMEDns01Provider["type"] | string, ACME
       [ACME_DNS01_PROVIDERS.UCLOUD_UDNR, "provider.ucloud.udnr"],
       [ACME_DNS01_PROVIDERS.WESTCN, "provider.westcn"],
       [ACME_DNS01_PROVIDERS.POWERDNS, "provider.powerdns"],
+      [ACME_DNS01_PROVIDERS.RFC2136, "provider.rfc2136"],
       [ACME_DNS01_PROVIDERS.ACMEDNS, "provider.acmedns"],
       [ACME_DNS01_PROVIDERS.ACMEHTTPREQ, "provider.acmehttpreq"],
     ] satisfies Array<[ACMEDns01ProviderType, string]>
@@ -402,8 +412,14 @@ export const acmeDns01ProvidersMap: Map<ACMEDns01Provider["type"] | string, ACME
   NOTICE: If you add new constant, please keep ASCII order.
  */
 export const ACME_HTTP01_PROVIDERS = Object.freeze({
+  ALIYUN_OSS: `${ACCESS_PROVIDERS.ALIYUN}-oss`,
+  AWS_S3: `${ACCESS_PROVIDERS.AWS}-s3`,
   LOCAL: `${ACCESS_PROVIDERS.LOCAL}`,
+  LOCAL_STANDALONE: `${ACCESS_PROVIDERS.LOCAL}-standalone`,
+  QINIU_KODO: `${ACCESS_PROVIDERS.QINIU}-kodo`,
+  S3: `${ACCESS_PROVIDERS.S3}`,
   SSH: `${ACCESS_PROVIDERS.SSH}`,
+  TENCENTCLOUD_COS: `${ACCESS_PROVIDERS.TENCENTCLOUD}-cos`,
 } as const);
 
 export type ACMEHttp01ProviderType = (typeof ACME_HTTP01_PROVIDERS)[keyof typeof ACME_HTTP01_PROVIDERS];
@@ -418,7 +434,13 @@ export const acmeHttp01ProvidersMap: Map<ACMEHttp01Provider["type"] | string, AC
   (
     [
       [ACME_HTTP01_PROVIDERS.LOCAL, "provider.local", "builtin"],
+      [ACME_HTTP01_PROVIDERS.LOCAL_STANDALONE, "provider.local.standalone", "builtin"],
       [ACME_HTTP01_PROVIDERS.SSH, "provider.ssh"],
+      [ACME_HTTP01_PROVIDERS.S3, "provider.s3"],
+      [ACME_HTTP01_PROVIDERS.ALIYUN_OSS, "provider.aliyun.oss"],
+      [ACME_HTTP01_PROVIDERS.TENCENTCLOUD_COS, "provider.tencentcloud.cos"],
+      [ACME_HTTP01_PROVIDERS.QINIU_KODO, "provider.qiniu.kodo"],
+      [ACME_HTTP01_PROVIDERS.AWS_S3, "provider.aws.s3"],
     ] satisfies Array<[ACMEHttp01ProviderType, string, "builtin"] | [ACMEHttp01ProviderType, string]>
   ).map(([type, name, builtin]) => [
     type,
@@ -433,6 +455,43 @@ export const acmeHttp01ProvidersMap: Map<ACMEHttp01Provider["type"] | string, AC
 );
 // #endregion
 
+// #region ACMETLSALPN01Provider
+/*
+  注意：如果追加新的常量值，请保持以 ASCII 排序。
+  NOTICE: If you add new constant, please keep ASCII order.
+ */
+export const ACME_TLSALPN01_PROVIDERS = Object.freeze({
+  LOCAL: `${ACCESS_PROVIDERS.LOCAL}`,
+  SSH: `${ACCESS_PROVIDERS.SSH}`,
+} as const);
+
+export type ACMETlsAlpn01ProviderType = (typeof ACME_TLSALPN01_PROVIDERS)[keyof typeof ACME_TLSALPN01_PROVIDERS];
+
+export interface ACMETlsAlpn01Provider extends BaseProviderWithAccess<ACMETlsAlpn01ProviderType> {}
+
+export const acmeTlsAlpn01ProvidersMap: Map<ACMETlsAlpn01Provider["type"] | string, ACMETlsAlpn01Provider> = new Map(
+  /*
+    注意：此处的顺序决定显示在前端的顺序。
+    NOTICE: The following order determines the order displayed at the frontend.
+   */
+  (
+    [
+      [ACME_TLSALPN01_PROVIDERS.LOCAL, "provider.local", "builtin"],
+      [ACME_TLSALPN01_PROVIDERS.SSH, "provider.ssh"],
+    ] satisfies Array<[ACMETlsAlpn01ProviderType, string, "builtin"] | [ACMETlsAlpn01ProviderType, string]>
+  ).map(([type, name, builtin]) => [
+    type,
+    {
+      type: type,
+      name: name,
+      icon: accessProvidersMap.get(type.split("-")[0])!.icon,
+      provider: type.split("-")[0] as AccessProviderType,
+      builtin: builtin === "builtin",
+    },
+  ])
+);
+// #endregion
+
 // #region DeploymentProvider
 /*
   注意：如果追加新的常量值，请保持以 ASCII 排序。
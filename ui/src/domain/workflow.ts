@@ -43,6 +43,10 @@ export const WORKFLOW_NODE_TYPES = Object.freeze({
   TRYCATCH: "tryCatch",
   TRYBLOCK: "tryBlock",
   CATCHBLOCK: "catchBlock",
+  SUB_WORKFLOW: "subWorkflow",
+  SCRIPT: "script",
+  HTTP_REQUEST: "httpRequest",
+  APPROVAL: "approval",
   BIZ_APPLY: "bizApply",
   BIZ_UPLOAD: "bizUpload",
   BIZ_MONITOR: "bizMonitor",
@@ -91,6 +95,59 @@ export const defaultNodeConfigForBranchBlock = (): Partial<WorkflowNodeConfigFor
   return {};
 };
 
+export type WorkflowNodeConfigForSubWorkflow = {
+  workflowId: string;
+  certificateOutputNodeId?: string;
+  variables?: Record<string, string>;
+  inheritVariables?: boolean;
+};
+
+export const defaultNodeConfigForSubWorkflow = (): Partial<WorkflowNodeConfigForSubWorkflow> => {
+  return {};
+};
+
+export type WorkflowNodeConfigForScript = {
+  script: string;
+};
+
+export const defaultNodeConfigForScript = (): Partial<WorkflowNodeConfigForScript> => {
+  return {};
+};
+
+export type WorkflowNodeConfigForHttpRequest = {
+  method: string;
+  url: string;
+  headers?: Record<string, string>;
+  body?: string;
+  timeout?: number;
+  allowInsecureConnections?: boolean;
+  caCertificate?: string;
+  clientCertificate?: string;
+  clientPrivateKey?: string;
+  responseFields?: Record<string, string>;
+  ignoreErrorStatus?: boolean;
+};
+
+export const defaultNodeConfigForHttpRequest = (): Partial<WorkflowNodeConfigForHttpRequest> => {
+  return {
+    method: "GET",
+    timeout: 30,
+  };
+};
+
+export type WorkflowNodeConfigForApproval = {
+  provider?: string;
+  providerAccessId?: string;
+  providerConfig?: Record<string, unknown>;
+  subject?: string;
+  message?: string;
+  waitTimeout?: number;
+};
+
+export const defaultNodeConfigForApproval = (): Partial<WorkflowNodeConfigForApproval> => {
+  return {};
+};
+
 export type WorkflowNodeConfigForBizApply = {
   domains: string;
   contactEmail: string;
@@ -272,6 +329,46 @@ export const newNode = (type: WorkflowNodeType, { i18n = getI18n() }: { i18n?: R
         },
       };
 
+    case WORKFLOW_NODE_TYPES.SUB_WORKFLOW:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.sub_workflow.default_name"),
+          config: defaultNodeConfigForSubWorkflow(),
+        },
+      };
+
+    case WORKFLOW_NODE_TYPES.SCRIPT:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.script.default_name"),
+          config: defaultNodeConfigForScript(),
+        },
+      };
+
+    case WORKFLOW_NODE_TYPES.HTTP_REQUEST:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.http_request.default_name"),
+          config: defaultNodeConfigForHttpRequest(),
+        },
+      };
+
+    case WORKFLOW_NODE_TYPES.APPROVAL:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.approval.default_name"),
+          config: defaultNodeConfigForApproval(),
+        },
+      };
+
     case WORKFLOW_NODE_TYPES.BIZ_APPLY:
       return {
         id: newNodeId(),
@@ -342,6 +439,7 @@ const _deepCloneNode = (node: WorkflowNode, { withCopySuffix, nodeIdMap }: { wit
 
     if (draft.data?.config) {
       switch (draft.type) {
+        case WORKFLOW_NODE_TYPES.SUB_WORKFLOW:
         case WORKFLOW_NODE_TYPES.BIZ_DEPLOY:
           {
             const prevNodeId = draft.data.config.certificateOutputNodeId as string;
@@ -0,0 +1,18 @@
+import { type PrivateCAModel } from "@/domain/privateCA";
+import { COLLECTION_NAME_PRIVATE_CA, getPocketBase } from "./_pocketbase";
+
+export const list = async () => {
+  const list = await getPocketBase()
+    .collection(COLLECTION_NAME_PRIVATE_CA)
+    .getFullList<PrivateCAModel>({
+      batch: 65535,
+      // 私钥字段无需返回前端
+      fields: ["id", "name", "parentRef", "commonName", "serialNumber", "keyAlgorithm", "validityNotBefore", "validityNotAfter", "created", "updated"].join(","),
+      sort: "-created",
+      requestKey: null,
+    });
+  return {
+    totalItems: list.length,
+    items: list,
+  };
+};